@@ -40,8 +40,10 @@ func Convert(item, out interface{}) error {
 	}
 
 	// Converting between maps and structs only occurs when autogenerated resources convert the result
-	// of an HTTP request. Those results do not contain omitted fields, so no need to set them.
-	if _, ok := item.(map[string]interface{}); !ok {
+	// of an HTTP request, or when a struct is sent as raw JSON. Maps have no omitted fields to set.
+	_, itemIsMap := item.(map[string]interface{})
+	_, outIsMap := out.(*map[string]interface{})
+	if !itemIsMap && !outIsMap {
 		setOmittedFields(item, out)
 	}
 
@@ -285,6 +285,40 @@ func TestSetOmittedFields(t *testing.T) {
 	}
 }
 
+func TestConvertStructToMap(t *testing.T) {
+	type Inner struct {
+		InnerNotOmitted string   `json:"notOmitted"`
+		InnerOmitted    []string `json:"-"`
+	}
+	input := &struct {
+		NotOmitted string   `json:"notOmitted"`
+		Omitted    []string `json:"-"`
+		Pointer    *Inner   `json:"pointer"`
+	}{
+		NotOmitted: "foo",
+		Omitted:    []string{"foo"},
+		Pointer: &Inner{
+			InnerNotOmitted: "bar",
+			InnerOmitted:    []string{"bar"},
+		},
+	}
+
+	output := make(map[string]interface{})
+	if err := Convert(input, &output); err != nil {
+		t.Fatalf("Error converting struct to map: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"notOmitted": "foo",
+		"pointer": map[string]interface{}{
+			"notOmitted": "bar",
+		},
+	}
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, output)
+	}
+}
+
 type ResourceDataMock struct {
 	FieldsInSchema      map[string]interface{}
 	FieldsWithHasChange []string
@@ -1,11 +1,6 @@
 package google
 
 import (
-	"fmt"
-	"log"
-	"time"
-
-	"github.com/hashicorp/terraform/helper/resource"
 	"google.golang.org/api/cloudfunctions/v1"
 )
 
@@ -14,57 +9,44 @@ type CloudFunctionsOperationWaiter struct {
 	Op      *cloudfunctions.Operation
 }
 
-func (w *CloudFunctionsOperationWaiter) RefreshFunc() resource.StateRefreshFunc {
-	return func() (interface{}, string, error) {
-		op, err := w.Service.Operations.Get(w.Op.Name).Do()
+func (w *CloudFunctionsOperationWaiter) QueryOp() error {
+	op, err := w.Service.Operations.Get(w.Op.Name).Do()
+	if err != nil {
+		return err
+	}
 
-		if err != nil {
-			return nil, "", err
-		}
+	w.Op = op
+	return nil
+}
 
-		status := "PENDING"
-		if op.Done == true {
-			status = "DONE"
-		}
+func (w *CloudFunctionsOperationWaiter) IsDone() bool {
+	return w.Op.Done
+}
 
-		log.Printf("[DEBUG] Got %q when asking for operation %q", status, w.Op.Name)
-		return op, status, nil
+func (w *CloudFunctionsOperationWaiter) OpError() error {
+	if w.Op.Error != nil {
+		return OperationStatusError{Code: w.Op.Error.Code, Message: w.Op.Error.Message}
 	}
+	return nil
 }
 
-func (w *CloudFunctionsOperationWaiter) Conf() *resource.StateChangeConf {
-	return &resource.StateChangeConf{
-		Pending: []string{"PENDING"},
-		Target:  []string{"DONE"},
-		Refresh: w.RefreshFunc(),
-	}
+func (w *CloudFunctionsOperationWaiter) State() string {
+	return doneState(w.Op.Done)
 }
 
-func cloudFunctionsOperationWait(client *cloudfunctions.Service,
-	op *cloudfunctions.Operation, activity string) error {
-	return cloudFunctionsOperationWaitTime(client, op, activity, 4)
+func (w *CloudFunctionsOperationWaiter) OpName() string {
+	return w.Op.Name
 }
 
-func cloudFunctionsOperationWaitTime(client *cloudfunctions.Service, op *cloudfunctions.Operation,
-	activity string, timeoutMin int) error {
+func cloudFunctionsOperationWait(config *Config, op *cloudfunctions.Operation, activity string) error {
+	return cloudFunctionsOperationWaitTime(config, op, activity, 4)
+}
+
+func cloudFunctionsOperationWaitTime(config *Config, op *cloudfunctions.Operation, activity string, timeoutMin int) error {
 	w := &CloudFunctionsOperationWaiter{
-		Service: client,
+		Service: config.clientCloudFunctions,
 		Op:      op,
 	}
 
-	state := w.Conf()
-	state.Delay = 10 * time.Second
-	state.Timeout = time.Duration(timeoutMin) * time.Minute
-	state.MinTimeout = 2 * time.Second
-	opRaw, err := state.WaitForState()
-	if err != nil {
-		return fmt.Errorf("Error waiting for %s: %s", activity, err)
-	}
-
-	resultOp := opRaw.(*cloudfunctions.Operation)
-	if resultOp.Error != nil {
-		return fmt.Errorf(resultOp.Error.Message)
-	}
-
-	return nil
+	return operationWaitTime(config, w, activity, timeoutMin)
 }
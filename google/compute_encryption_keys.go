@@ -0,0 +1,165 @@
+package google
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"google.golang.org/api/compute/v1"
+)
+
+// Cloud KMS keys are sent and read as the raw kmsKeyName of a customer
+// encryption key.
+
+func kmsKeySelfLinkSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ForceNew:     true,
+		ValidateFunc: validateKmsCryptoKeyId,
+	}
+}
+
+// customerEncryptionKeySchema is the schema of an *_encryption_key block,
+// which takes either a customer-supplied key or a Cloud KMS key.
+func customerEncryptionKeySchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"raw_key": &schema.Schema{
+					Type:      schema.TypeString,
+					Optional:  true,
+					ForceNew:  true,
+					Sensitive: true,
+				},
+
+				"kms_key_self_link": kmsKeySelfLinkSchema(),
+
+				"sha256": &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+}
+
+// validateKmsCryptoKeyId accepts the crypto key formats that
+// CryptoIdParseFunc does.
+func validateKmsCryptoKeyId(v interface{}, k string) (ws []string, errors []error) {
+	// The project is only used to complete the short format, so any value
+	// will do for validation.
+	if _, err := parseKmsCryptoKeyId(v.(string), &Config{Project: "project"}); err != nil {
+		errors = append(errors, fmt.Errorf("%q: %s", k, err))
+	}
+	return
+}
+
+// expandKmsKeyName returns the kmsKeyName for a kms_key_self_link, or "" if
+// it isn't set.
+func expandKmsKeyName(v interface{}, config *Config) (string, error) {
+	if v == nil || v.(string) == "" {
+		return "", nil
+	}
+
+	cryptoKeyId, err := parseKmsCryptoKeyId(v.(string), config)
+	if err != nil {
+		return "", err
+	}
+	return cryptoKeyId.cryptoKeyId(), nil
+}
+
+// flattenKmsKeySelfLink returns the kms_key_self_link for the kmsKeyName the
+// API returned. The API names the key version in use, so the version is
+// dropped, and the configured value is kept if it names the same key. Some
+// keys, like those of a snapshot's source disk, aren't returned at all, in
+// which case the configured value is kept as well.
+func flattenKmsKeySelfLink(configured string, kmsKeyName interface{}, config *Config) string {
+	name, _ := kmsKeyName.(string)
+	if name == "" {
+		return configured
+	}
+	name = strings.Split(name, "/cryptoKeyVersions/")[0]
+
+	if configured != "" {
+		if cryptoKeyId, err := parseKmsCryptoKeyId(configured, config); err == nil && cryptoKeyId.cryptoKeyId() == name {
+			return configured
+		}
+	}
+	return name
+}
+
+// kmsKeySelfLinksEqual reports whether a and b are the same Cloud KMS key,
+// possibly in different forms.
+func kmsKeySelfLinksEqual(a, b string, config *Config) bool {
+	if a == b {
+		return true
+	}
+	aId, err := parseKmsCryptoKeyId(a, config)
+	if err != nil {
+		return false
+	}
+	bId, err := parseKmsCryptoKeyId(b, config)
+	if err != nil {
+		return false
+	}
+	return aId.cryptoKeyId() == bId.cryptoKeyId()
+}
+
+// expandCustomerEncryptionKey returns the raw JSON of the *_encryption_key
+// block at key, or nil if it isn't set.
+func expandCustomerEncryptionKey(d *schema.ResourceData, key string, config *Config) (map[string]interface{}, error) {
+	if _, ok := d.GetOk(key); !ok {
+		return nil, nil
+	}
+
+	rawKey := d.Get(key + ".0.raw_key").(string)
+	kmsKeyName, err := expandKmsKeyName(d.Get(key+".0.kms_key_self_link"), config)
+	if err != nil {
+		return nil, err
+	}
+	if (rawKey == "") == (kmsKeyName == "") {
+		return nil, fmt.Errorf("Exactly one of raw_key or kms_key_self_link must be set in %s", key)
+	}
+
+	if kmsKeyName != "" {
+		return map[string]interface{}{"kmsKeyName": kmsKeyName}, nil
+	}
+	return map[string]interface{}{"rawKey": rawKey}, nil
+}
+
+// flattenCustomerEncryptionKey flattens the raw JSON of a customer encryption
+// key into the *_encryption_key block at key. Raw keys aren't returned by the
+// API, so they are copied from the configuration. The block is only set if it
+// was configured or a Cloud KMS key is in use.
+func flattenCustomerEncryptionKey(d *schema.ResourceData, key string, raw interface{}, config *Config) []map[string]interface{} {
+	apiKey, _ := raw.(map[string]interface{})
+	kmsKeySelfLink := flattenKmsKeySelfLink(d.Get(key+".0.kms_key_self_link").(string), apiKey["kmsKeyName"], config)
+	if _, ok := d.GetOk(key); !ok && kmsKeySelfLink == "" {
+		return nil
+	}
+
+	return []map[string]interface{}{
+		{
+			"raw_key":           d.Get(key + ".0.raw_key"),
+			"kms_key_self_link": kmsKeySelfLink,
+			"sha256":            apiKey["sha256"],
+		},
+	}
+}
+
+// rawCustomerEncryptionKey returns the raw JSON of a customer encryption key
+// read with the typed clients, for flattenCustomerEncryptionKey.
+func rawCustomerEncryptionKey(key *compute.CustomerEncryptionKey) map[string]interface{} {
+	if key == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"rawKey": key.RawKey,
+		"sha256": key.Sha256,
+	}
+}
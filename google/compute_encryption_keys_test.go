@@ -0,0 +1,111 @@
+package google
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestValidateKmsCryptoKeyId(t *testing.T) {
+	cases := map[string]struct {
+		Id          string
+		ExpectError bool
+	}{
+		"id":            {Id: "my-project/us-central1/my-key-ring/my-key"},
+		"short id":      {Id: "us-central1/my-key-ring/my-key"},
+		"relative link": {Id: "projects/my-project/locations/us-central1/keyRings/my-key-ring/cryptoKeys/my-key"},
+		"key ring only": {Id: "my-project/us-central1/my-key-ring/", ExpectError: true},
+		"key version": {
+			Id:          "projects/my-project/locations/us-central1/keyRings/my-key-ring/cryptoKeys/my-key/cryptoKeyVersions/1",
+			ExpectError: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		_, errors := validateKmsCryptoKeyId(tc.Id, "kms_key_self_link")
+		if (len(errors) > 0) != tc.ExpectError {
+			t.Errorf("%s: expected error: %t, got %v", tn, tc.ExpectError, errors)
+		}
+	}
+}
+
+func TestFlattenKmsKeySelfLink(t *testing.T) {
+	config := &Config{Project: "my-project"}
+	relativeLink := "projects/my-project/locations/us-central1/keyRings/my-key-ring/cryptoKeys/my-key"
+
+	cases := map[string]struct {
+		Configured string
+		KmsKeyName interface{}
+		Expected   string
+	}{
+		"not returned": {
+			Configured: "us-central1/my-key-ring/my-key",
+			KmsKeyName: nil,
+			Expected:   "us-central1/my-key-ring/my-key",
+		},
+		"same key": {
+			Configured: "us-central1/my-key-ring/my-key",
+			KmsKeyName: relativeLink + "/cryptoKeyVersions/1",
+			Expected:   "us-central1/my-key-ring/my-key",
+		},
+		"different key": {
+			Configured: "us-central1/my-key-ring/other-key",
+			KmsKeyName: relativeLink + "/cryptoKeyVersions/1",
+			Expected:   relativeLink,
+		},
+		"not configured": {
+			Configured: "",
+			KmsKeyName: relativeLink + "/cryptoKeyVersions/3",
+			Expected:   relativeLink,
+		},
+	}
+
+	for tn, tc := range cases {
+		if got := flattenKmsKeySelfLink(tc.Configured, tc.KmsKeyName, config); got != tc.Expected {
+			t.Errorf("%s: expected %q, got %q", tn, tc.Expected, got)
+		}
+	}
+}
+
+func TestKmsKeySelfLinksEqual(t *testing.T) {
+	config := &Config{Project: "my-project"}
+
+	cases := map[string]struct {
+		A, B     string
+		Expected bool
+	}{
+		"same":           {A: "us-central1/my-key-ring/my-key", B: "us-central1/my-key-ring/my-key", Expected: true},
+		"different form": {A: "us-central1/my-key-ring/my-key", B: "projects/my-project/locations/us-central1/keyRings/my-key-ring/cryptoKeys/my-key", Expected: true},
+		"different key":  {A: "us-central1/my-key-ring/my-key", B: "us-central1/my-key-ring/other-key"},
+		"removed":        {A: "us-central1/my-key-ring/my-key", B: ""},
+	}
+
+	for tn, tc := range cases {
+		if got := kmsKeySelfLinksEqual(tc.A, tc.B, config); got != tc.Expected {
+			t.Errorf("%s: expected %t, got %t", tn, tc.Expected, got)
+		}
+	}
+}
+
+// testAccComputeKmsKey returns a Cloud KMS key that the Compute Engine service
+// agent of the test project can encrypt disks with.
+func testAccComputeKmsKey(keyRingName, cryptoKeyName string) string {
+	return fmt.Sprintf(`
+data "google_project" "project" {}
+
+resource "google_kms_key_ring" "foobar" {
+	name     = "%s"
+	location = "us-central1"
+}
+
+resource "google_kms_crypto_key" "foobar" {
+	name     = "%s"
+	key_ring = "${google_kms_key_ring.foobar.id}"
+}
+
+resource "google_kms_crypto_key_iam_member" "foobar" {
+	crypto_key_id = "${google_kms_crypto_key.foobar.id}"
+	role          = "roles/cloudkms.cryptoKeyEncrypterDecrypter"
+	member        = "serviceAccount:service-${data.google_project.project.number}@compute-system.iam.gserviceaccount.com"
+}
+`, keyRingName, cryptoKeyName)
+}
@@ -0,0 +1,195 @@
+package google
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"google.golang.org/api/compute/v1"
+)
+
+var statefulDiskDeleteRules = []string{"NEVER", "ON_PERMANENT_INSTANCE_DELETION"}
+
+func statefulDiskSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"device_name": &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+
+				"delete_rule": &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "NEVER",
+					ValidateFunc: validation.StringInSlice(statefulDiskDeleteRules, false),
+				},
+			},
+		},
+	}
+}
+
+// instanceGroupManagerBetaUrl returns the beta API URL of a zonal or
+// regional instance group manager, given its location as "zones/<zone>" or
+// "regions/<region>".
+func instanceGroupManagerBetaUrl(project, location, name string) string {
+	return fmt.Sprintf("https://www.googleapis.com/compute/beta/projects/%s/%s/instanceGroupManagers/%s", project, location, name)
+}
+
+var computeApiVersionRegexp = regexp.MustCompile("/compute/[a-zA-Z0-9]*/projects/")
+
+func convertSelfLinkToBeta(link string) string {
+	return computeApiVersionRegexp.ReplaceAllString(link, "/compute/beta/projects/")
+}
+
+// expandStatefulPolicy builds the statefulPolicy to patch onto a manager.
+// Patching merges maps, so disks that were removed from stateful_disk are
+// sent as null to take them out of the policy.
+func expandStatefulPolicy(d *schema.ResourceData) map[string]interface{} {
+	disks := make(map[string]interface{})
+
+	o, n := d.GetChange("stateful_disk")
+	for _, raw := range o.(*schema.Set).List() {
+		disks[raw.(map[string]interface{})["device_name"].(string)] = nil
+	}
+	for _, raw := range n.(*schema.Set).List() {
+		disk := raw.(map[string]interface{})
+		disks[disk["device_name"].(string)] = map[string]interface{}{
+			"autoDelete": disk["delete_rule"].(string),
+		}
+	}
+
+	return map[string]interface{}{
+		"preservedState": map[string]interface{}{
+			"disks": disks,
+		},
+	}
+}
+
+func flattenStatefulDisks(manager map[string]interface{}) []map[string]interface{} {
+	policy, _ := manager["statefulPolicy"].(map[string]interface{})
+	preservedState, _ := policy["preservedState"].(map[string]interface{})
+	disks, _ := preservedState["disks"].(map[string]interface{})
+
+	result := make([]map[string]interface{}, 0, len(disks))
+	for deviceName, raw := range disks {
+		disk, _ := raw.(map[string]interface{})
+		result = append(result, map[string]interface{}{
+			"device_name": deviceName,
+			"delete_rule": disk["autoDelete"],
+		})
+	}
+	return result
+}
+
+func updateStatefulPolicy(d *schema.ResourceData, config *Config, managerUrl, project string) error {
+	policy := expandStatefulPolicy(d)
+	log.Printf("[DEBUG] Updating stateful policy of %s: %#v", managerUrl, policy)
+	res, err := Patch(config, managerUrl, map[string]interface{}{"statefulPolicy": policy}, nil, nil)
+	if err != nil {
+		return fmt.Errorf("Error updating stateful policy: %s", err)
+	}
+
+	op := &compute.Operation{}
+	if err := Convert(res, op); err != nil {
+		return err
+	}
+
+	return computeOperationWaitTimeConfig(config, op, project, "Updating stateful policy", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+}
+
+func readStatefulDisks(d *schema.ResourceData, config *Config, managerUrl string) error {
+	res, err := Get(config, managerUrl)
+	if err != nil {
+		return fmt.Errorf("Error reading stateful policy: %s", err)
+	}
+
+	return d.Set("stateful_disk", flattenStatefulDisks(res))
+}
+
+// listPerInstanceConfigs returns every per-instance config of the manager at
+// managerUrl.
+func listPerInstanceConfigs(config *Config, managerUrl string) ([]interface{}, error) {
+	var configs []interface{}
+	url := managerUrl + "/listPerInstanceConfigs"
+	for {
+		res, err := Post(config, url, map[string]interface{}{})
+		if err != nil {
+			return nil, err
+		}
+
+		items, _ := res["items"].([]interface{})
+		configs = append(configs, items...)
+
+		token, _ := res["nextPageToken"].(string)
+		if token == "" {
+			return configs, nil
+		}
+		url = fmt.Sprintf("%s/listPerInstanceConfigs?pageToken=%s", managerUrl, token)
+	}
+}
+
+// instanceGroupManagerIsStateful returns whether the manager at managerUrl
+// has a stateful policy or any per-instance configs.
+func instanceGroupManagerIsStateful(config *Config, managerUrl string) (bool, error) {
+	res, err := Get(config, managerUrl)
+	if err != nil {
+		return false, err
+	}
+
+	if len(flattenStatefulDisks(res)) > 0 {
+		return true, nil
+	}
+	status, _ := res["status"].(map[string]interface{})
+	stateful, _ := status["stateful"].(map[string]interface{})
+	isStateful, _ := stateful["isStateful"].(bool)
+	return isStateful, nil
+}
+
+// perInstanceConfigsPending returns how many of the manager's per-instance
+// configs are still being applied to or removed from their instances.
+func perInstanceConfigsPending(config *Config, managerUrl string) (int, error) {
+	configs, err := listPerInstanceConfigs(config, managerUrl)
+	if err != nil {
+		return 0, err
+	}
+
+	return countPendingPerInstanceConfigs(configs), nil
+}
+
+// countPendingPerInstanceConfigs counts the configs that are APPLYING or
+// DELETING. Other statuses are final: a config updated with minimal_action
+// NONE stays NONE until the instance is next recreated.
+func countPendingPerInstanceConfigs(configs []interface{}) int {
+	pending := 0
+	for _, raw := range configs {
+		switch raw.(map[string]interface{})["status"] {
+		case "APPLYING", "DELETING":
+			pending++
+		}
+	}
+	return pending
+}
+
+// perInstanceConfigDiskHash hashes disks by device name and the relative
+// path of their source, so that self links in any API version match.
+func perInstanceConfigDiskHash(v interface{}) int {
+	var buf bytes.Buffer
+	m := v.(map[string]interface{})
+	buf.WriteString(fmt.Sprintf("%s-", m["device_name"].(string)))
+	if path, err := getRelativePath(m["source"].(string)); err == nil {
+		buf.WriteString(fmt.Sprintf("%s-", path))
+	} else {
+		buf.WriteString(fmt.Sprintf("%s-", m["source"].(string)))
+	}
+	buf.WriteString(fmt.Sprintf("%s-", m["mode"].(string)))
+	buf.WriteString(fmt.Sprintf("%s-", m["delete_rule"].(string)))
+	return hashcode.String(buf.String())
+}
@@ -2,9 +2,13 @@ package google
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	computeBeta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
 )
 
 func expandAliasIpRanges(ranges []interface{}) []*computeBeta.AliasIpRange {
@@ -30,11 +34,14 @@ func flattenAliasIpRange(ranges []*computeBeta.AliasIpRange) []map[string]interf
 	return rangesSchema
 }
 
-func flattenScheduling(scheduling *computeBeta.Scheduling) []map[string]interface{} {
+// flattenScheduling flattens scheduling along with its node affinities, which
+// are passed in as raw JSON.
+func flattenScheduling(scheduling *computeBeta.Scheduling, rawNodeAffinities interface{}) []map[string]interface{} {
 	result := make([]map[string]interface{}, 0, 1)
 	schedulingMap := map[string]interface{}{
 		"on_host_maintenance": scheduling.OnHostMaintenance,
 		"preemptible":         scheduling.Preemptible,
+		"node_affinities":     flattenNodeAffinities(rawNodeAffinities),
 	}
 	if scheduling.AutomaticRestart != nil {
 		schedulingMap["automatic_restart"] = *scheduling.AutomaticRestart
@@ -43,6 +50,140 @@ func flattenScheduling(scheduling *computeBeta.Scheduling) []map[string]interfac
 	return result
 }
 
+func nodeAffinitiesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"key": &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+					ForceNew: true,
+				},
+
+				"operator": &schema.Schema{
+					Type:         schema.TypeString,
+					Required:     true,
+					ForceNew:     true,
+					ValidateFunc: validation.StringInSlice([]string{"IN", "NOT_IN"}, false),
+				},
+
+				"values": &schema.Schema{
+					Type:     schema.TypeSet,
+					Required: true,
+					ForceNew: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+					Set:      schema.HashString,
+				},
+			},
+		},
+	}
+}
+
+// expandNodeAffinities returns the nodeAffinities to send for the scheduling
+// block, or nil if there are none.
+func expandNodeAffinities(d TerraformResourceData) []interface{} {
+	v, ok := d.GetOk("scheduling.0.node_affinities")
+	if !ok {
+		return nil
+	}
+
+	affinities := make([]interface{}, 0, v.(*schema.Set).Len())
+	for _, raw := range v.(*schema.Set).List() {
+		affinity := raw.(map[string]interface{})
+		affinities = append(affinities, map[string]interface{}{
+			"key":      affinity["key"].(string),
+			"operator": affinity["operator"].(string),
+			"values":   convertStringSet(affinity["values"].(*schema.Set)),
+		})
+	}
+	return affinities
+}
+
+func flattenNodeAffinities(raw interface{}) []map[string]interface{} {
+	affinities, _ := raw.([]interface{})
+	result := make([]map[string]interface{}, 0, len(affinities))
+	for _, rawAffinity := range affinities {
+		affinity, ok := rawAffinity.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		values, _ := affinity["values"].([]interface{})
+		result = append(result, map[string]interface{}{
+			"key":      affinity["key"],
+			"operator": affinity["operator"],
+			"values":   schema.NewSet(schema.HashString, values),
+		})
+	}
+	return result
+}
+
+// postWithRawFields sends item, e.g. a beta instance or instance template,
+// to url with fields added to it that the vendored compute clients don't
+// have yet. Those fields are only in the beta API, so resources that use them
+// are written with postWithRawFields against beta URLs and read back with Get,
+// and the raw JSON is converted into the client structs for everything else.
+// fields is keyed by dot-separated paths into the request body, where numbers index
+// into lists, e.g. "scheduling.nodeAffinities" or
+// "disks.0.diskEncryptionKey.kmsKeyName".
+func postWithRawFields(config *Config, url string, item interface{}, fields map[string]interface{}) (*compute.Operation, error) {
+	body := make(map[string]interface{})
+	if err := Convert(item, &body); err != nil {
+		return nil, err
+	}
+
+	for path, value := range fields {
+		if err := setRawField(body, strings.Split(path, "."), value); err != nil {
+			return nil, fmt.Errorf("Error setting %s: %s", path, err)
+		}
+	}
+
+	res, err := Post(config, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	op := &compute.Operation{}
+	if err := Convert(res, op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func setRawField(node interface{}, keys []string, value interface{}) error {
+	key := keys[0]
+
+	if list, ok := node.([]interface{}); ok {
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(list) {
+			return fmt.Errorf("invalid list index %q", key)
+		}
+		if len(keys) == 1 {
+			list[i] = value
+			return nil
+		}
+		if list[i] == nil {
+			list[i] = make(map[string]interface{})
+		}
+		return setRawField(list[i], keys[1:], value)
+	}
+
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("can't set %q on %T", key, node)
+	}
+	if len(keys) == 1 {
+		m[key] = value
+		return nil
+	}
+	if m[key] == nil {
+		m[key] = make(map[string]interface{})
+	}
+	return setRawField(m[key], keys[1:], value)
+}
+
 func flattenAccessConfigs(accessConfigs []*computeBeta.AccessConfig) ([]map[string]interface{}, string) {
 	flattened := make([]map[string]interface{}, len(accessConfigs))
 	natIP := ""
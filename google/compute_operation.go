@@ -2,11 +2,8 @@ package google
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"log"
-	"time"
-
-	"github.com/hashicorp/terraform/helper/resource"
 
 	computeBeta "google.golang.org/api/compute/v0.beta"
 	"google.golang.org/api/compute/v1"
@@ -18,35 +15,44 @@ type ComputeOperationWaiter struct {
 	Project string
 }
 
-func (w *ComputeOperationWaiter) RefreshFunc() resource.StateRefreshFunc {
-	return func() (interface{}, string, error) {
-		var op *compute.Operation
-		var err error
-
-		if w.Op.Zone != "" {
-			zone := GetResourceNameFromSelfLink(w.Op.Zone)
-			op, err = w.Service.ZoneOperations.Get(w.Project, zone, w.Op.Name).Do()
-		} else if w.Op.Region != "" {
-			region := GetResourceNameFromSelfLink(w.Op.Region)
-			op, err = w.Service.RegionOperations.Get(w.Project, region, w.Op.Name).Do()
-		} else {
-			op, err = w.Service.GlobalOperations.Get(w.Project, w.Op.Name).Do()
-		}
-		if err != nil {
-			return nil, "", err
-		}
-
-		log.Printf("[DEBUG] Got %q when asking for operation %q", op.Status, w.Op.Name)
-		return op, op.Status, nil
+func (w *ComputeOperationWaiter) QueryOp() error {
+	var op *compute.Operation
+	var err error
+
+	if w.Op.Zone != "" {
+		zone := GetResourceNameFromSelfLink(w.Op.Zone)
+		op, err = w.Service.ZoneOperations.Get(w.Project, zone, w.Op.Name).Do()
+	} else if w.Op.Region != "" {
+		region := GetResourceNameFromSelfLink(w.Op.Region)
+		op, err = w.Service.RegionOperations.Get(w.Project, region, w.Op.Name).Do()
+	} else {
+		op, err = w.Service.GlobalOperations.Get(w.Project, w.Op.Name).Do()
+	}
+	if err != nil {
+		return err
 	}
+
+	w.Op = op
+	return nil
+}
+
+func (w *ComputeOperationWaiter) IsDone() bool {
+	return w.Op.Status == "DONE"
 }
 
-func (w *ComputeOperationWaiter) Conf() *resource.StateChangeConf {
-	return &resource.StateChangeConf{
-		Pending: []string{"PENDING", "RUNNING"},
-		Target:  []string{"DONE"},
-		Refresh: w.RefreshFunc(),
+func (w *ComputeOperationWaiter) OpError() error {
+	if w.Op.Error != nil {
+		return ComputeOperationError(*w.Op.Error)
 	}
+	return nil
+}
+
+func (w *ComputeOperationWaiter) State() string {
+	return fmt.Sprintf("%s (%d%%)", w.Op.Status, w.Op.Progress)
+}
+
+func (w *ComputeOperationWaiter) OpName() string {
+	return w.Op.Name
 }
 
 // ComputeOperationError wraps compute.OperationError and implements the
@@ -62,6 +68,10 @@ func (e ComputeOperationError) Error() string {
 	return buf.String()
 }
 
+// computeOperationWait and computeOperationWaitTime take the compute client
+// that generated resources pass in. They can't see the provider's stop
+// context, so they only give up when timeoutMin passes; hand-written
+// resources should use the Config variants below instead.
 func computeOperationWait(client *compute.Service, op *compute.Operation, project, activity string) error {
 	return computeOperationWaitTime(client, op, project, activity, 4)
 }
@@ -73,29 +83,39 @@ func computeOperationWaitTime(client *compute.Service, op *compute.Operation, pr
 		Project: project,
 	}
 
-	state := w.Conf()
-	state.Delay = 10 * time.Second
-	state.Timeout = time.Duration(timeoutMin) * time.Minute
-	state.MinTimeout = 2 * time.Second
-	opRaw, err := state.WaitForState()
+	return operationWaitTimeContext(context.Background(), w, activity, timeoutMin)
+}
+
+func computeBetaOperationWaitTime(client *compute.Service, op *computeBeta.Operation, project, activity string, timeoutMin int) error {
+	opV1 := &compute.Operation{}
+	err := Convert(op, opV1)
 	if err != nil {
-		return fmt.Errorf("Error waiting for %s: %s", activity, err)
+		return err
 	}
 
-	resultOp := opRaw.(*compute.Operation)
-	if resultOp.Error != nil {
-		return ComputeOperationError(*resultOp.Error)
+	return computeOperationWaitTime(client, opV1, project, activity, timeoutMin)
+}
+
+func computeOperationWaitConfig(config *Config, op *compute.Operation, project, activity string) error {
+	return computeOperationWaitTimeConfig(config, op, project, activity, 4)
+}
+
+func computeOperationWaitTimeConfig(config *Config, op *compute.Operation, project, activity string, timeoutMin int) error {
+	w := &ComputeOperationWaiter{
+		Service: config.clientCompute,
+		Op:      op,
+		Project: project,
 	}
 
-	return nil
+	return operationWaitTime(config, w, activity, timeoutMin)
 }
 
-func computeBetaOperationWaitTime(client *compute.Service, op *computeBeta.Operation, project, activity string, timeoutMin int) error {
+func computeBetaOperationWaitTimeConfig(config *Config, op *computeBeta.Operation, project, activity string, timeoutMin int) error {
 	opV1 := &compute.Operation{}
 	err := Convert(op, opV1)
 	if err != nil {
 		return err
 	}
 
-	return computeOperationWaitTime(client, opV1, project, activity, timeoutMin)
+	return computeOperationWaitTimeConfig(config, opV1, project, activity, timeoutMin)
 }
@@ -23,3 +23,22 @@ func computeSharedOperationWaitTime(client *compute.Service, op interface{}, pro
 		panic("Attempted to wait on an Operation of unknown type.")
 	}
 }
+
+func computeSharedOperationWaitConfig(config *Config, op interface{}, project string, activity string) error {
+	return computeSharedOperationWaitTimeConfig(config, op, project, 4, activity)
+}
+
+func computeSharedOperationWaitTimeConfig(config *Config, op interface{}, project string, minutes int, activity string) error {
+	if op == nil {
+		panic("Attempted to wait on an Operation that was nil.")
+	}
+
+	switch op.(type) {
+	case *compute.Operation:
+		return computeOperationWaitTimeConfig(config, op.(*compute.Operation), project, activity, minutes)
+	case *computeBeta.Operation:
+		return computeBetaOperationWaitTimeConfig(config, op.(*computeBeta.Operation), project, activity, minutes)
+	default:
+		panic("Attempted to wait on an Operation of unknown type.")
+	}
+}
@@ -0,0 +1,84 @@
+package google
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func shieldedInstanceConfigSchema(forceNew bool) *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		// The API fills in defaults for images that support Shielded VM.
+		Computed: true,
+		ForceNew: forceNew,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"enable_secure_boot": &schema.Schema{
+					Type:     schema.TypeBool,
+					Optional: true,
+					ForceNew: forceNew,
+					Default:  false,
+				},
+
+				"enable_vtpm": &schema.Schema{
+					Type:     schema.TypeBool,
+					Optional: true,
+					ForceNew: forceNew,
+					Default:  true,
+				},
+
+				"enable_integrity_monitoring": &schema.Schema{
+					Type:     schema.TypeBool,
+					Optional: true,
+					ForceNew: forceNew,
+					Default:  true,
+				},
+			},
+		},
+	}
+}
+
+// The fields of the API's shieldedInstanceConfig.
+var shieldedInstanceConfigFields = []string{"enableSecureBoot", "enableVtpm", "enableIntegrityMonitoring"}
+
+// expandShieldedInstanceConfig returns the shieldedInstanceConfig to send,
+// or nil if the block isn't set.
+func expandShieldedInstanceConfig(d TerraformResourceData) map[string]interface{} {
+	v, ok := d.GetOk("shielded_instance_config")
+	if !ok || len(v.([]interface{})) == 0 || v.([]interface{})[0] == nil {
+		return nil
+	}
+
+	config := v.([]interface{})[0].(map[string]interface{})
+	return map[string]interface{}{
+		"enableSecureBoot":          config["enable_secure_boot"].(bool),
+		"enableVtpm":                config["enable_vtpm"].(bool),
+		"enableIntegrityMonitoring": config["enable_integrity_monitoring"].(bool),
+	}
+}
+
+func flattenShieldedInstanceConfig(raw interface{}) []map[string]interface{} {
+	config, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	return []map[string]interface{}{
+		{
+			"enable_secure_boot":          config["enableSecureBoot"] == true,
+			"enable_vtpm":                 config["enableVtpm"] == true,
+			"enable_integrity_monitoring": config["enableIntegrityMonitoring"] == true,
+		},
+	}
+}
+
+func instanceBetaUrl(project, zone, name string) string {
+	return fmt.Sprintf("https://www.googleapis.com/compute/beta/projects/%s/zones/%s/instances/%s", project, zone, name)
+}
+
+func instanceTemplateBetaUrl(project, name string) string {
+	return fmt.Sprintf("https://www.googleapis.com/compute/beta/projects/%s/global/instanceTemplates/%s", project, name)
+}
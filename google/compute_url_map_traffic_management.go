@@ -0,0 +1,611 @@
+package google
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+var urlMapRedirectResponseCodes = []string{
+	"MOVED_PERMANENTLY_DEFAULT",
+	"FOUND",
+	"SEE_OTHER",
+	"TEMPORARY_REDIRECT",
+	"PERMANENT_REDIRECT",
+}
+
+func urlMapHeadersToAddSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"header_name": &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+
+				"header_value": &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+
+				"replace": &schema.Schema{
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+			},
+		},
+	}
+}
+
+func urlMapHeaderActionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"request_headers_to_add": urlMapHeadersToAddSchema(),
+
+				"request_headers_to_remove": &schema.Schema{
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+
+				"response_headers_to_add": urlMapHeadersToAddSchema(),
+
+				"response_headers_to_remove": &schema.Schema{
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+}
+
+func urlMapDefaultUrlRedirectSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{"default_service"},
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"host_redirect": &schema.Schema{
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+
+				"path_redirect": &schema.Schema{
+					Type:          schema.TypeString,
+					Optional:      true,
+					ConflictsWith: []string{"default_url_redirect.0.prefix_redirect"},
+				},
+
+				"prefix_redirect": &schema.Schema{
+					Type:          schema.TypeString,
+					Optional:      true,
+					ConflictsWith: []string{"default_url_redirect.0.path_redirect"},
+				},
+
+				"https_redirect": &schema.Schema{
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+
+				"redirect_response_code": &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "MOVED_PERMANENTLY_DEFAULT",
+					ValidateFunc: validation.StringInSlice(urlMapRedirectResponseCodes, false),
+				},
+
+				"strip_query": &schema.Schema{
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+			},
+		},
+	}
+}
+
+func urlMapRouteRulesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"priority": &schema.Schema{
+					Type:         schema.TypeInt,
+					Required:     true,
+					ValidateFunc: validation.IntBetween(0, 2147483647),
+				},
+
+				"description": &schema.Schema{
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+
+				"service": &schema.Schema{
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+
+				"match_rules": &schema.Schema{
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"full_path_match": &schema.Schema{
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+
+							"prefix_match": &schema.Schema{
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+
+							"regex_match": &schema.Schema{
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+
+							"ignore_case": &schema.Schema{
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  false,
+							},
+						},
+					},
+				},
+
+				"route_action": &schema.Schema{
+					Type:     schema.TypeList,
+					Optional: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"weighted_backend_services": &schema.Schema{
+								Type:     schema.TypeList,
+								Optional: true,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"backend_service": &schema.Schema{
+											Type:     schema.TypeString,
+											Required: true,
+										},
+
+										"weight": &schema.Schema{
+											Type:         schema.TypeInt,
+											Required:     true,
+											ValidateFunc: validation.IntBetween(0, 1000),
+										},
+
+										"header_action": urlMapHeaderActionSchema(),
+									},
+								},
+							},
+
+							"url_rewrite": &schema.Schema{
+								Type:     schema.TypeList,
+								Optional: true,
+								MaxItems: 1,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"host_rewrite": &schema.Schema{
+											Type:         schema.TypeString,
+											Optional:     true,
+											ValidateFunc: validation.StringLenBetween(1, 255),
+										},
+
+										"path_prefix_rewrite": &schema.Schema{
+											Type:         schema.TypeString,
+											Optional:     true,
+											ValidateFunc: validation.StringLenBetween(1, 1024),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+
+				"header_action": urlMapHeaderActionSchema(),
+			},
+		},
+	}
+}
+
+// customizeDiffUrlMap rejects combinations of route rule and test fields
+// that the API would only refuse at apply time. Values that are only known
+// after apply, like the self link of a new backend service, read as empty
+// here, so only fields that are set together are rejected, not missing ones.
+// Those are checked by validateUrlMapTargets on create and update.
+func customizeDiffUrlMap(d *schema.ResourceDiff, meta interface{}) error {
+	if err := validateUrlMapPathMatchers(d.Get("path_matcher").([]interface{})); err != nil {
+		return err
+	}
+	return validateUrlMapTests(d.Get("test").([]interface{}))
+}
+
+// validateUrlMapTargets checks that requests that match no rule, and requests
+// that match a route rule, have somewhere to go.
+func validateUrlMapTargets(d *schema.ResourceData) error {
+	if d.Get("default_service").(string) == "" && len(d.Get("default_url_redirect").([]interface{})) == 0 {
+		return fmt.Errorf("one of default_service or default_url_redirect must be set")
+	}
+	for _, v := range d.Get("path_matcher").([]interface{}) {
+		pathMatcher := v.(map[string]interface{})
+		for _, vr := range pathMatcher["route_rules"].([]interface{}) {
+			routeRule := vr.(map[string]interface{})
+			if routeRule["service"].(string) == "" && len(routeRule["route_action"].([]interface{})) == 0 {
+				return fmt.Errorf("path_matcher %q: route rule with priority %d must set one of service or route_action", pathMatcher["name"], routeRule["priority"])
+			}
+		}
+	}
+	return nil
+}
+
+func validateUrlMapPathMatchers(pathMatchers []interface{}) error {
+	for _, v := range pathMatchers {
+		pathMatcher := v.(map[string]interface{})
+		name := pathMatcher["name"].(string)
+		routeRules := pathMatcher["route_rules"].([]interface{})
+
+		if len(pathMatcher["path_rule"].([]interface{})) > 0 && len(routeRules) > 0 {
+			return fmt.Errorf("path_matcher %q: only one of path_rule or route_rules can be set", name)
+		}
+
+		priorities := make(map[int]bool)
+		for _, vr := range routeRules {
+			routeRule := vr.(map[string]interface{})
+			priority := routeRule["priority"].(int)
+			if priorities[priority] {
+				return fmt.Errorf("path_matcher %q: more than one route rule has priority %d", name, priority)
+			}
+			priorities[priority] = true
+
+			weightedBackendServices := 0
+			if routeAction := routeRule["route_action"].([]interface{}); len(routeAction) > 0 && routeAction[0] != nil {
+				weightedBackendServices = len(routeAction[0].(map[string]interface{})["weighted_backend_services"].([]interface{}))
+			}
+			if routeRule["service"].(string) != "" && weightedBackendServices > 0 {
+				return fmt.Errorf("path_matcher %q: route rule with priority %d can only set one of service or route_action.weighted_backend_services", name, priority)
+			}
+
+			for _, vm := range routeRule["match_rules"].([]interface{}) {
+				matchRule, _ := vm.(map[string]interface{})
+				matches := 0
+				for _, k := range []string{"full_path_match", "prefix_match", "regex_match"} {
+					if matchRule[k] != nil && matchRule[k].(string) != "" {
+						matches++
+					}
+				}
+				if matches > 1 {
+					return fmt.Errorf("path_matcher %q: match rules of the route rule with priority %d can only set one of full_path_match, prefix_match or regex_match", name, priority)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func validateUrlMapTests(tests []interface{}) error {
+	for _, v := range tests {
+		test := v.(map[string]interface{})
+		if test["service"].(string) != "" && test["expected_redirect_response_code"].(int) != 0 {
+			return fmt.Errorf("test for %s%s: only one of service or expected_redirect_response_code can be set", test["host"], test["path"])
+		}
+	}
+	return nil
+}
+
+func validateUrlMapTestRedirectResponseCode(v interface{}, k string) (warnings []string, errors []error) {
+	switch code := v.(int); code {
+	case 301, 302, 303, 307, 308:
+	default:
+		errors = append(errors, fmt.Errorf("%q must be one of 301, 302, 303, 307 or 308, got %d", k, code))
+	}
+	return
+}
+
+// expandUrlMapHeaderAction returns the raw JSON of a header_action block, or
+// nil if it isn't set.
+func expandUrlMapHeaderAction(v interface{}) map[string]interface{} {
+	l, _ := v.([]interface{})
+	if len(l) == 0 || l[0] == nil {
+		return nil
+	}
+
+	headerAction := l[0].(map[string]interface{})
+	return map[string]interface{}{
+		"requestHeadersToAdd":     expandUrlMapHeadersToAdd(headerAction["request_headers_to_add"]),
+		"requestHeadersToRemove":  headerAction["request_headers_to_remove"],
+		"responseHeadersToAdd":    expandUrlMapHeadersToAdd(headerAction["response_headers_to_add"]),
+		"responseHeadersToRemove": headerAction["response_headers_to_remove"],
+	}
+}
+
+func expandUrlMapHeadersToAdd(v interface{}) []interface{} {
+	headers := make([]interface{}, 0)
+	for _, raw := range v.([]interface{}) {
+		header := raw.(map[string]interface{})
+		headers = append(headers, map[string]interface{}{
+			"headerName":  header["header_name"],
+			"headerValue": header["header_value"],
+			"replace":     header["replace"],
+		})
+	}
+	return headers
+}
+
+// expandUrlMapRedirect returns the raw JSON of a default_url_redirect block,
+// or nil if it isn't set.
+func expandUrlMapRedirect(v interface{}) map[string]interface{} {
+	l, _ := v.([]interface{})
+	if len(l) == 0 || l[0] == nil {
+		return nil
+	}
+
+	redirect := l[0].(map[string]interface{})
+	return omitEmptyStrings(map[string]interface{}{
+		"hostRedirect":         redirect["host_redirect"],
+		"pathRedirect":         redirect["path_redirect"],
+		"prefixRedirect":       redirect["prefix_redirect"],
+		"httpsRedirect":        redirect["https_redirect"],
+		"redirectResponseCode": redirect["redirect_response_code"],
+		"stripQuery":           redirect["strip_query"],
+	})
+}
+
+// expandUrlMapRouteRules returns the raw JSON of the route_rules of a
+// path_matcher, or nil if there are none.
+func expandUrlMapRouteRules(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	if len(l) == 0 {
+		return nil
+	}
+
+	routeRules := make([]interface{}, 0, len(l))
+	for _, raw := range l {
+		routeRule := raw.(map[string]interface{})
+
+		matchRules := make([]interface{}, 0)
+		for _, rawMatch := range routeRule["match_rules"].([]interface{}) {
+			matchRule, _ := rawMatch.(map[string]interface{})
+			matchRules = append(matchRules, omitEmptyStrings(map[string]interface{}{
+				"fullPathMatch": matchRule["full_path_match"],
+				"prefixMatch":   matchRule["prefix_match"],
+				"regexMatch":    matchRule["regex_match"],
+				"ignoreCase":    matchRule["ignore_case"],
+			}))
+		}
+
+		rule := omitEmptyStrings(map[string]interface{}{
+			"priority":    routeRule["priority"],
+			"description": routeRule["description"],
+			"service":     routeRule["service"],
+			"matchRules":  matchRules,
+		})
+		if routeAction := expandUrlMapRouteAction(routeRule["route_action"]); routeAction != nil {
+			rule["routeAction"] = routeAction
+		}
+		if headerAction := expandUrlMapHeaderAction(routeRule["header_action"]); headerAction != nil {
+			rule["headerAction"] = headerAction
+		}
+		routeRules = append(routeRules, rule)
+	}
+	return routeRules
+}
+
+func expandUrlMapRouteAction(v interface{}) map[string]interface{} {
+	l, _ := v.([]interface{})
+	if len(l) == 0 || l[0] == nil {
+		return nil
+	}
+	routeAction := l[0].(map[string]interface{})
+
+	weightedBackendServices := make([]interface{}, 0)
+	for _, raw := range routeAction["weighted_backend_services"].([]interface{}) {
+		backendService := raw.(map[string]interface{})
+		weighted := map[string]interface{}{
+			"backendService": backendService["backend_service"],
+			"weight":         backendService["weight"],
+		}
+		if headerAction := expandUrlMapHeaderAction(backendService["header_action"]); headerAction != nil {
+			weighted["headerAction"] = headerAction
+		}
+		weightedBackendServices = append(weightedBackendServices, weighted)
+	}
+
+	result := map[string]interface{}{
+		"weightedBackendServices": weightedBackendServices,
+	}
+	if l, _ := routeAction["url_rewrite"].([]interface{}); len(l) > 0 && l[0] != nil {
+		urlRewrite := l[0].(map[string]interface{})
+		result["urlRewrite"] = omitEmptyStrings(map[string]interface{}{
+			"hostRewrite":       urlRewrite["host_rewrite"],
+			"pathPrefixRewrite": urlRewrite["path_prefix_rewrite"],
+		})
+	}
+	return result
+}
+
+// omitEmptyStrings drops unset string fields from m, as the API treats an
+// empty match or redirect field as set.
+func omitEmptyStrings(m map[string]interface{}) map[string]interface{} {
+	for k, v := range m {
+		if v == nil || v == "" {
+			delete(m, k)
+		}
+	}
+	return m
+}
+
+func flattenUrlMapHeaderAction(raw interface{}) []map[string]interface{} {
+	headerAction, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	return []map[string]interface{}{
+		{
+			"request_headers_to_add":     flattenUrlMapHeadersToAdd(headerAction["requestHeadersToAdd"]),
+			"request_headers_to_remove":  headerAction["requestHeadersToRemove"],
+			"response_headers_to_add":    flattenUrlMapHeadersToAdd(headerAction["responseHeadersToAdd"]),
+			"response_headers_to_remove": headerAction["responseHeadersToRemove"],
+		},
+	}
+}
+
+func flattenUrlMapHeadersToAdd(raw interface{}) []map[string]interface{} {
+	l, _ := raw.([]interface{})
+	headers := make([]map[string]interface{}, 0, len(l))
+	for _, v := range l {
+		header, _ := v.(map[string]interface{})
+		headers = append(headers, map[string]interface{}{
+			"header_name":  header["headerName"],
+			"header_value": header["headerValue"],
+			"replace":      header["replace"] == true,
+		})
+	}
+	return headers
+}
+
+func flattenUrlMapRedirect(raw interface{}) []map[string]interface{} {
+	redirect, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	code, _ := redirect["redirectResponseCode"].(string)
+	if code == "" {
+		code = "MOVED_PERMANENTLY_DEFAULT"
+	}
+	return []map[string]interface{}{
+		{
+			"host_redirect":          redirect["hostRedirect"],
+			"path_redirect":          redirect["pathRedirect"],
+			"prefix_redirect":        redirect["prefixRedirect"],
+			"https_redirect":         redirect["httpsRedirect"] == true,
+			"redirect_response_code": code,
+			"strip_query":            redirect["stripQuery"] == true,
+		},
+	}
+}
+
+func flattenUrlMapRouteRules(raw interface{}) []map[string]interface{} {
+	l, _ := raw.([]interface{})
+	routeRules := make([]map[string]interface{}, 0, len(l))
+	for _, v := range l {
+		routeRule, _ := v.(map[string]interface{})
+
+		rawMatchRules, _ := routeRule["matchRules"].([]interface{})
+		matchRules := make([]map[string]interface{}, 0, len(rawMatchRules))
+		for _, vm := range rawMatchRules {
+			matchRule, _ := vm.(map[string]interface{})
+			matchRules = append(matchRules, map[string]interface{}{
+				"full_path_match": matchRule["fullPathMatch"],
+				"prefix_match":    matchRule["prefixMatch"],
+				"regex_match":     matchRule["regexMatch"],
+				"ignore_case":     matchRule["ignoreCase"] == true,
+			})
+		}
+
+		routeRules = append(routeRules, map[string]interface{}{
+			"priority":      flattenUrlMapInt(routeRule["priority"]),
+			"description":   routeRule["description"],
+			"service":       flattenUrlMapServiceLink(routeRule["service"]),
+			"match_rules":   matchRules,
+			"route_action":  flattenUrlMapRouteAction(routeRule["routeAction"]),
+			"header_action": flattenUrlMapHeaderAction(routeRule["headerAction"]),
+		})
+	}
+	return routeRules
+}
+
+func flattenUrlMapRouteAction(raw interface{}) []map[string]interface{} {
+	routeAction, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	rawWeighted, _ := routeAction["weightedBackendServices"].([]interface{})
+	weightedBackendServices := make([]map[string]interface{}, 0, len(rawWeighted))
+	for _, v := range rawWeighted {
+		backendService, _ := v.(map[string]interface{})
+		weightedBackendServices = append(weightedBackendServices, map[string]interface{}{
+			"backend_service": flattenUrlMapServiceLink(backendService["backendService"]),
+			"weight":          flattenUrlMapInt(backendService["weight"]),
+			"header_action":   flattenUrlMapHeaderAction(backendService["headerAction"]),
+		})
+	}
+
+	var urlRewrite []map[string]interface{}
+	if rewrite, ok := routeAction["urlRewrite"].(map[string]interface{}); ok {
+		urlRewrite = []map[string]interface{}{
+			{
+				"host_rewrite":        rewrite["hostRewrite"],
+				"path_prefix_rewrite": rewrite["pathPrefixRewrite"],
+			},
+		}
+	}
+
+	return []map[string]interface{}{
+		{
+			"weighted_backend_services": weightedBackendServices,
+			"url_rewrite":               urlRewrite,
+		},
+	}
+}
+
+// The beta API returns beta self links, while default_service and the other
+// v1 fields of the URL map hold v1 ones.
+func flattenUrlMapServiceLink(raw interface{}) string {
+	link, _ := raw.(string)
+	if link == "" {
+		return ""
+	}
+	return ConvertSelfLinkToV1(link)
+}
+
+func flattenUrlMapInt(raw interface{}) int {
+	if v, ok := raw.(float64); ok {
+		return int(v)
+	}
+	return 0
+}
+
+// rawUrlMapEntries indexes the raw JSON entries of the list at key, e.g. the
+// pathMatchers of a URL map, by ident.
+func rawUrlMapEntries(rawUrlMap map[string]interface{}, key string, ident func(map[string]interface{}) string) map[string]map[string]interface{} {
+	entries := make(map[string]map[string]interface{})
+	l, _ := rawUrlMap[key].([]interface{})
+	for _, v := range l {
+		if entry, ok := v.(map[string]interface{}); ok {
+			entries[ident(entry)] = entry
+		}
+	}
+	return entries
+}
+
+func rawUrlMapPathMatcherIdent(pathMatcher map[string]interface{}) string {
+	name, _ := pathMatcher["name"].(string)
+	return name
+}
+
+func rawUrlMapTestIdent(test map[string]interface{}) string {
+	return fmt.Sprintf("%s/%s", test["host"], test["path"])
+}
+
+func urlMapBetaUrl(project, name string) string {
+	return fmt.Sprintf("https://www.googleapis.com/compute/beta/projects/%s/global/urlMaps/%s", project, name)
+}
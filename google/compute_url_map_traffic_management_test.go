@@ -0,0 +1,204 @@
+package google
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func testUrlMapRouteRule(priority int, service string, backendServices int, matchRules ...map[string]interface{}) map[string]interface{} {
+	weighted := make([]interface{}, backendServices)
+	for i := range weighted {
+		weighted[i] = map[string]interface{}{"backend_service": "bs", "weight": 100}
+	}
+
+	rules := make([]interface{}, len(matchRules))
+	for i, m := range matchRules {
+		rules[i] = m
+	}
+
+	return map[string]interface{}{
+		"priority":     priority,
+		"service":      service,
+		"match_rules":  rules,
+		"route_action": []interface{}{map[string]interface{}{"weighted_backend_services": weighted}},
+	}
+}
+
+func TestValidateUrlMapPathMatchers(t *testing.T) {
+	pathRule := map[string]interface{}{"paths": []interface{}{"/*"}, "service": "bs"}
+	prefixMatch := map[string]interface{}{"full_path_match": "", "prefix_match": "/", "regex_match": ""}
+
+	cases := map[string]struct {
+		PathRules   []interface{}
+		RouteRules  []interface{}
+		ExpectError bool
+	}{
+		"path rules only": {
+			PathRules: []interface{}{pathRule},
+		},
+		"route rules only": {
+			RouteRules: []interface{}{
+				testUrlMapRouteRule(1, "bs", 0, prefixMatch),
+				testUrlMapRouteRule(2, "", 2, prefixMatch),
+			},
+		},
+		"path and route rules": {
+			PathRules:   []interface{}{pathRule},
+			RouteRules:  []interface{}{testUrlMapRouteRule(1, "bs", 0)},
+			ExpectError: true,
+		},
+		"duplicate priority": {
+			RouteRules: []interface{}{
+				testUrlMapRouteRule(1, "bs", 0),
+				testUrlMapRouteRule(1, "", 2),
+			},
+			ExpectError: true,
+		},
+		"service and weighted backend services": {
+			RouteRules:  []interface{}{testUrlMapRouteRule(1, "bs", 2)},
+			ExpectError: true,
+		},
+		"service unknown until apply": {
+			RouteRules: []interface{}{testUrlMapRouteRule(1, "", 0)},
+		},
+		"several matches in a match rule": {
+			RouteRules: []interface{}{
+				testUrlMapRouteRule(1, "bs", 0, map[string]interface{}{"full_path_match": "/a", "prefix_match": "/", "regex_match": ""}),
+			},
+			ExpectError: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		if tc.PathRules == nil {
+			tc.PathRules = []interface{}{}
+		}
+		if tc.RouteRules == nil {
+			tc.RouteRules = []interface{}{}
+		}
+		pathMatchers := []interface{}{
+			map[string]interface{}{
+				"name":        "matcher",
+				"path_rule":   tc.PathRules,
+				"route_rules": tc.RouteRules,
+			},
+		}
+
+		err := validateUrlMapPathMatchers(pathMatchers)
+		if (err != nil) != tc.ExpectError {
+			t.Errorf("%s: expected error: %t, got %v", tn, tc.ExpectError, err)
+		}
+	}
+}
+
+func TestValidateUrlMapTargets(t *testing.T) {
+	routeRule := func(service string, routeAction bool) map[string]interface{} {
+		rule := map[string]interface{}{
+			"priority": 1,
+			"service":  service,
+		}
+		if routeAction {
+			rule["route_action"] = []interface{}{
+				map[string]interface{}{
+					"url_rewrite": []interface{}{map[string]interface{}{"path_prefix_rewrite": "/"}},
+				},
+			}
+		}
+		return rule
+	}
+
+	cases := map[string]struct {
+		Raw         map[string]interface{}
+		ExpectError bool
+	}{
+		"default service": {
+			Raw: map[string]interface{}{"default_service": "bs"},
+		},
+		"default url redirect": {
+			Raw: map[string]interface{}{
+				"default_url_redirect": []interface{}{map[string]interface{}{"host_redirect": "example.com"}},
+			},
+		},
+		"no default": {
+			Raw:         map[string]interface{}{},
+			ExpectError: true,
+		},
+		"route rule with service": {
+			Raw: map[string]interface{}{
+				"default_service": "bs",
+				"path_matcher": []interface{}{
+					map[string]interface{}{"name": "matcher", "default_service": "bs", "route_rules": []interface{}{routeRule("bs", false)}},
+				},
+			},
+		},
+		"route rule with route action": {
+			Raw: map[string]interface{}{
+				"default_service": "bs",
+				"path_matcher": []interface{}{
+					map[string]interface{}{"name": "matcher", "default_service": "bs", "route_rules": []interface{}{routeRule("", true)}},
+				},
+			},
+		},
+		"route rule without a target": {
+			Raw: map[string]interface{}{
+				"default_service": "bs",
+				"path_matcher": []interface{}{
+					map[string]interface{}{"name": "matcher", "default_service": "bs", "route_rules": []interface{}{routeRule("", false)}},
+				},
+			},
+			ExpectError: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		tc.Raw["name"] = "urlmap"
+		d := schema.TestResourceDataRaw(t, resourceComputeUrlMap().Schema, tc.Raw)
+
+		err := validateUrlMapTargets(d)
+		if (err != nil) != tc.ExpectError {
+			t.Errorf("%s: expected error: %t, got %v", tn, tc.ExpectError, err)
+		}
+	}
+}
+
+func TestValidateUrlMapTests(t *testing.T) {
+	cases := map[string]struct {
+		Service      string
+		RedirectCode int
+		ExpectError  bool
+	}{
+		"service":  {Service: "bs"},
+		"redirect": {RedirectCode: 301},
+		"both":     {Service: "bs", RedirectCode: 301, ExpectError: true},
+	}
+
+	for tn, tc := range cases {
+		tests := []interface{}{
+			map[string]interface{}{
+				"host":                            "mysite.com",
+				"path":                            "/",
+				"service":                         tc.Service,
+				"expected_redirect_response_code": tc.RedirectCode,
+			},
+		}
+
+		err := validateUrlMapTests(tests)
+		if (err != nil) != tc.ExpectError {
+			t.Errorf("%s: expected error: %t, got %v", tn, tc.ExpectError, err)
+		}
+	}
+}
+
+func TestValidateUrlMapTestRedirectResponseCode(t *testing.T) {
+	for _, code := range []int{301, 302, 303, 307, 308} {
+		if _, errors := validateUrlMapTestRedirectResponseCode(code, "expected_redirect_response_code"); len(errors) > 0 {
+			t.Errorf("%d: expected no error, got %v", code, errors)
+		}
+	}
+	for _, code := range []int{200, 304, 404} {
+		if _, errors := validateUrlMapTestRedirectResponseCode(code, "expected_redirect_response_code"); len(errors) == 0 {
+			t.Errorf("%d: expected an error", code)
+		}
+	}
+}
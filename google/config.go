@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/logging"
 	"github.com/hashicorp/terraform/helper/pathorcontents"
@@ -29,6 +30,7 @@ import (
 	"google.golang.org/api/dataflow/v1b3"
 	"google.golang.org/api/dataproc/v1"
 	"google.golang.org/api/dns/v1"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iam/v1"
 	cloudlogging "google.golang.org/api/logging/v2"
 	"google.golang.org/api/pubsub/v1"
@@ -40,19 +42,112 @@ import (
 	"google.golang.org/api/storage/v1"
 )
 
+const (
+	defaultTokenInfoURL = "https://www.googleapis.com/oauth2/v3/tokeninfo"
+
+	// Static access tokens with less than this left on them are likely to
+	// expire partway through an apply.
+	accessTokenExpiryWarning = 10 * time.Minute
+)
+
+var defaultClientScopes = []string{
+	"https://www.googleapis.com/auth/compute",
+	"https://www.googleapis.com/auth/cloud-platform",
+	"https://www.googleapis.com/auth/ndev.clouddns.readwrite",
+	"https://www.googleapis.com/auth/devstorage.full_control",
+}
+
+// customEndpointServices lists the services whose endpoint can be overridden
+// with a <service>_custom_endpoint provider attribute.
+var customEndpointServices = []string{
+	"bigquery",
+	"cloud_billing",
+	"cloudfunctions",
+	"cloudiot",
+	"compute",
+	"compute_beta",
+	"container",
+	"container_beta",
+	"dataflow",
+	"dataproc",
+	"dns",
+	"iam",
+	"iam_credentials",
+	"kms",
+	"logging",
+	"pubsub",
+	"resource_manager",
+	"resource_manager_v2beta1",
+	"runtimeconfig",
+	"service_management",
+	"source_repo",
+	"spanner",
+	"sqladmin",
+	"storage",
+}
+
 // Config is the configuration structure used to instantiate the Google
 // provider.
 type Config struct {
+	// StopContext is cancelled when Terraform is interrupted, and stops any
+	// long-running operation waits in progress.
+	StopContext context.Context
+
 	Credentials string
+	AccessToken string
 	Project     string
 	Region      string
 	Zone        string
 
+	ImpersonateServiceAccount          string
+	ImpersonateServiceAccountDelegates []string
+
+	// With UserProjectOverride set, requests are billed and counted against
+	// the quota of BillingProject, or Project if that is empty, instead of
+	// the project the credentials belong to.
+	BillingProject      string
+	UserProjectOverride bool
+
+	// Scopes requested for the provider's credentials. defaultClientScopes
+	// is used when empty.
+	Scopes []string
+
+	// Retry policy for requests failing with a transient error. The
+	// defaultRequest* values are used for any left unset.
+	RequestMaxAttempts int
+	RequestMinBackoff  time.Duration
+	RequestMaxBackoff  time.Duration
+
+	// RequestRateLimits caps the rate of requests sent to each API, keyed by
+	// the API names rateLimitServices returns.
+	RequestRateLimits map[string]RequestRateLimit
+
+	// IamBatching merges IAM member and binding changes made to the same
+	// resource within IamBatchSendAfter of each other into one update.
+	IamBatching       bool
+	IamBatchSendAfter time.Duration
+
+	// DefaultLabels are applied to every resource with a top-level labels
+	// field, in addition to the resource's own labels. See labels.go.
+	DefaultLabels map[string]string
+
+	// CustomEndpoints maps a service name from customEndpointServices to the
+	// base path its requests should be sent to instead of the Google default.
+	CustomEndpoints map[string]string
+
 	client    *http.Client
 	userAgent string
 
+	// endpointOverrides maps a default API base path to its custom endpoint,
+	// for rewriting the fully-qualified URLs given to sendRequest.
+	endpointOverrides map[string]string
+
 	tokenSource oauth2.TokenSource
 
+	// tokenInfoURL overrides the endpoint the expiry of AccessToken is looked
+	// up at, for tests.
+	tokenInfoURL string
+
 	clientBilling                *cloudbilling.Service
 	clientCompute                *compute.Service
 	clientComputeBeta            *computeBeta.Service
@@ -78,21 +173,44 @@ type Config struct {
 	clientCloudIoT               *cloudiot.Service
 
 	bigtableClientFactory *BigtableClientFactory
+
+	iamBatcher *iamPolicyBatcher
 }
 
 func (c *Config) loadAndValidate() error {
 	var account accountFile
-	clientScopes := []string{
-		"https://www.googleapis.com/auth/compute",
-		"https://www.googleapis.com/auth/cloud-platform",
-		"https://www.googleapis.com/auth/ndev.clouddns.readwrite",
-		"https://www.googleapis.com/auth/devstorage.full_control",
+	clientScopes := c.Scopes
+	if len(clientScopes) == 0 {
+		clientScopes = defaultClientScopes
 	}
+	c.endpointOverrides = make(map[string]string)
 
 	var client *http.Client
 	var tokenSource oauth2.TokenSource
 
-	if c.Credentials != "" {
+	if c.AccessToken != "" {
+		contents, _, err := pathorcontents.Read(c.AccessToken)
+		if err != nil {
+			return fmt.Errorf("Error loading access token: %s", err)
+		}
+
+		log.Printf("[INFO] Authenticating using configured access_token...")
+		log.Printf("[INFO]   -- Scopes: %s", clientScopes)
+		token := &oauth2.Token{AccessToken: strings.TrimSpace(contents)}
+
+		tokenSource = oauth2.StaticTokenSource(token)
+		client = oauth2.NewClient(context.Background(), tokenSource)
+
+		// A static token can't be refreshed, so let the user know if it is
+		// unlikely to last for the whole run. The lookup is only informative,
+		// so it doesn't hold up configuring the provider, e.g. when the
+		// tokeninfo endpoint isn't reachable.
+		infoURL := c.tokenInfoURL
+		if infoURL == "" {
+			infoURL = defaultTokenInfoURL
+		}
+		go logAccessTokenExpiry(client, infoURL)
+	} else if c.Credentials != "" {
 		contents, _, err := pathorcontents.Read(c.Credentials)
 		if err != nil {
 			return fmt.Errorf("Error loading credentials: %s", err)
@@ -136,10 +254,50 @@ func (c *Config) loadAndValidate() error {
 		}
 	}
 
+	if c.ImpersonateServiceAccount != "" {
+		log.Printf("[INFO] Impersonating service account %s", c.ImpersonateServiceAccount)
+		if len(c.ImpersonateServiceAccountDelegates) > 0 {
+			log.Printf("[INFO]   -- Delegates: %s", c.ImpersonateServiceAccountDelegates)
+		}
+
+		// The client built above carries the caller's own identity and is
+		// only used to mint tokens for the target service account.
+		tokenSource = newImpersonatedTokenSource(client, c.basePath("iam_credentials", iamCredentialsBasePath), c.ImpersonateServiceAccount, c.ImpersonateServiceAccountDelegates, clientScopes)
+		client = oauth2.NewClient(context.Background(), tokenSource)
+	}
+
 	c.tokenSource = tokenSource
 
+	if c.UserProjectOverride {
+		userProject := c.userProject()
+		if userProject == "" {
+			return fmt.Errorf("user_project_override requires billing_project or project to be set")
+		}
+		log.Printf("[INFO] Billing requests to project %s", userProject)
+		client.Transport = newUserProjectTransport(client.Transport, userProject)
+	}
+
 	client.Transport = logging.NewTransport("Google", client.Transport)
 
+	if len(c.RequestRateLimits) > 0 {
+		for service, limit := range c.RequestRateLimits {
+			log.Printf("[INFO] Limiting %s requests to %g per second (burst %d)", service, limit.RequestsPerSecond, limit.Burst)
+		}
+		client.Transport = newRateLimitTransport(client.Transport, c.RequestRateLimits)
+	}
+
+	maxAttempts, minBackoff, maxBackoff := c.RequestMaxAttempts, c.RequestMinBackoff, c.RequestMaxBackoff
+	if maxAttempts == 0 {
+		maxAttempts = defaultRequestMaxAttempts
+	}
+	if minBackoff == 0 {
+		minBackoff = defaultRequestMinBackoff
+	}
+	if maxBackoff == 0 {
+		maxBackoff = defaultRequestMaxBackoff
+	}
+	client.Transport = newRetryTransport(client.Transport, maxAttempts, minBackoff, maxBackoff)
+
 	projectURL := "https://www.terraform.io"
 	userAgent := fmt.Sprintf("Terraform/%s (+%s)",
 		version.String(), projectURL)
@@ -155,6 +313,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientCompute.UserAgent = userAgent
+	c.clientCompute.BasePath = c.basePath("compute", c.clientCompute.BasePath)
 
 	log.Printf("[INFO] Instantiating GCE Beta client...")
 	c.clientComputeBeta, err = computeBeta.New(client)
@@ -162,6 +321,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientComputeBeta.UserAgent = userAgent
+	c.clientComputeBeta.BasePath = c.basePath("compute_beta", c.clientComputeBeta.BasePath)
 
 	log.Printf("[INFO] Instantiating GKE client...")
 	c.clientContainer, err = container.New(client)
@@ -169,6 +329,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientContainer.UserAgent = userAgent
+	c.clientContainer.BasePath = c.basePath("container", c.clientContainer.BasePath)
 
 	log.Printf("[INFO] Instantiating GKE Beta client...")
 	c.clientContainerBeta, err = containerBeta.New(client)
@@ -176,6 +337,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientContainerBeta.UserAgent = userAgent
+	c.clientContainerBeta.BasePath = c.basePath("container_beta", c.clientContainerBeta.BasePath)
 
 	log.Printf("[INFO] Instantiating Google Cloud DNS client...")
 	c.clientDns, err = dns.New(client)
@@ -183,6 +345,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientDns.UserAgent = userAgent
+	c.clientDns.BasePath = c.basePath("dns", c.clientDns.BasePath)
 
 	log.Printf("[INFO] Instantiating Google Cloud KMS Client...")
 	c.clientKms, err = cloudkms.New(client)
@@ -190,6 +353,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientKms.UserAgent = userAgent
+	c.clientKms.BasePath = c.basePath("kms", c.clientKms.BasePath)
 
 	log.Printf("[INFO] Instantiating Google Stackdriver Logging client...")
 	c.clientLogging, err = cloudlogging.New(client)
@@ -197,6 +361,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientLogging.UserAgent = userAgent
+	c.clientLogging.BasePath = c.basePath("logging", c.clientLogging.BasePath)
 
 	log.Printf("[INFO] Instantiating Google Storage Client...")
 	c.clientStorage, err = storage.New(client)
@@ -204,6 +369,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientStorage.UserAgent = userAgent
+	c.clientStorage.BasePath = c.basePath("storage", c.clientStorage.BasePath)
 
 	log.Printf("[INFO] Instantiating Google SqlAdmin Client...")
 	c.clientSqlAdmin, err = sqladmin.New(client)
@@ -211,6 +377,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientSqlAdmin.UserAgent = userAgent
+	c.clientSqlAdmin.BasePath = c.basePath("sqladmin", c.clientSqlAdmin.BasePath)
 
 	log.Printf("[INFO] Instantiating Google Pubsub Client...")
 	c.clientPubsub, err = pubsub.New(client)
@@ -218,6 +385,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientPubsub.UserAgent = userAgent
+	c.clientPubsub.BasePath = c.basePath("pubsub", c.clientPubsub.BasePath)
 
 	log.Printf("[INFO] Instantiating Google Dataflow Client...")
 	c.clientDataflow, err = dataflow.New(client)
@@ -225,6 +393,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientDataflow.UserAgent = userAgent
+	c.clientDataflow.BasePath = c.basePath("dataflow", c.clientDataflow.BasePath)
 
 	log.Printf("[INFO] Instantiating Google Cloud ResourceManager Client...")
 	c.clientResourceManager, err = cloudresourcemanager.New(client)
@@ -232,6 +401,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientResourceManager.UserAgent = userAgent
+	c.clientResourceManager.BasePath = c.basePath("resource_manager", c.clientResourceManager.BasePath)
 
 	log.Printf("[INFO] Instantiating Google Cloud ResourceManager V Client...")
 	c.clientResourceManagerV2Beta1, err = resourceManagerV2Beta1.New(client)
@@ -239,6 +409,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientResourceManagerV2Beta1.UserAgent = userAgent
+	c.clientResourceManagerV2Beta1.BasePath = c.basePath("resource_manager_v2beta1", c.clientResourceManagerV2Beta1.BasePath)
 
 	log.Printf("[INFO] Instantiating Google Cloud Runtimeconfig Client...")
 	c.clientRuntimeconfig, err = runtimeconfig.New(client)
@@ -246,6 +417,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientRuntimeconfig.UserAgent = userAgent
+	c.clientRuntimeconfig.BasePath = c.basePath("runtimeconfig", c.clientRuntimeconfig.BasePath)
 
 	log.Printf("[INFO] Instantiating Google Cloud IAM Client...")
 	c.clientIAM, err = iam.New(client)
@@ -253,6 +425,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientIAM.UserAgent = userAgent
+	c.clientIAM.BasePath = c.basePath("iam", c.clientIAM.BasePath)
 
 	log.Printf("[INFO] Instantiating Google Cloud Service Management Client...")
 	c.clientServiceMan, err = servicemanagement.New(client)
@@ -260,6 +433,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientServiceMan.UserAgent = userAgent
+	c.clientServiceMan.BasePath = c.basePath("service_management", c.clientServiceMan.BasePath)
 
 	log.Printf("[INFO] Instantiating Google Cloud Billing Client...")
 	c.clientBilling, err = cloudbilling.New(client)
@@ -267,6 +441,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientBilling.UserAgent = userAgent
+	c.clientBilling.BasePath = c.basePath("cloud_billing", c.clientBilling.BasePath)
 
 	log.Printf("[INFO] Instantiating Google Cloud BigQuery Client...")
 	c.clientBigQuery, err = bigquery.New(client)
@@ -274,6 +449,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientBigQuery.UserAgent = userAgent
+	c.clientBigQuery.BasePath = c.basePath("bigquery", c.clientBigQuery.BasePath)
 
 	log.Printf("[INFO] Instantiating Google Cloud CloudFunctions Client...")
 	c.clientCloudFunctions, err = cloudfunctions.New(client)
@@ -281,6 +457,16 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientCloudFunctions.UserAgent = userAgent
+	c.clientCloudFunctions.BasePath = c.basePath("cloudfunctions", c.clientCloudFunctions.BasePath)
+
+	if c.IamBatching {
+		sendAfter := c.IamBatchSendAfter
+		if sendAfter == 0 {
+			sendAfter = defaultIamBatchSendAfter
+		}
+		log.Printf("[INFO] Batching IAM policy changes sent within %s of each other", sendAfter)
+		c.iamBatcher = newIamPolicyBatcher(sendAfter)
+	}
 
 	c.bigtableClientFactory = &BigtableClientFactory{
 		UserAgent:   userAgent,
@@ -293,6 +479,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientSourceRepo.UserAgent = userAgent
+	c.clientSourceRepo.BasePath = c.basePath("source_repo", c.clientSourceRepo.BasePath)
 
 	log.Printf("[INFO] Instantiating Google Cloud Spanner Client...")
 	c.clientSpanner, err = spanner.New(client)
@@ -300,6 +487,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientSpanner.UserAgent = userAgent
+	c.clientSpanner.BasePath = c.basePath("spanner", c.clientSpanner.BasePath)
 
 	log.Printf("[INFO] Instantiating Google Cloud Dataproc Client...")
 	c.clientDataproc, err = dataproc.New(client)
@@ -307,6 +495,7 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientDataproc.UserAgent = userAgent
+	c.clientDataproc.BasePath = c.basePath("dataproc", c.clientDataproc.BasePath)
 
 	log.Printf("[INFO] Instantiating Google Cloud IoT Core Client...")
 	c.clientCloudIoT, err = cloudiot.New(client)
@@ -314,10 +503,110 @@ func (c *Config) loadAndValidate() error {
 		return err
 	}
 	c.clientCloudIoT.UserAgent = userAgent
+	c.clientCloudIoT.BasePath = c.basePath("cloudiot", c.clientCloudIoT.BasePath)
 
 	return nil
 }
 
+// userProject returns the project requests should be billed to, or "" if
+// they should be billed to the credentials' project.
+func (c *Config) userProject() string {
+	if !c.UserProjectOverride {
+		return ""
+	}
+	if c.BillingProject != "" {
+		return c.BillingProject
+	}
+	return c.Project
+}
+
+func (c *Config) stopContext() context.Context {
+	if c.StopContext == nil {
+		return context.Background()
+	}
+	return c.StopContext
+}
+
+// basePath returns the custom endpoint configured for service, or def if
+// there isn't one. Overridden defaults are remembered so that requests
+// made through sendRequest are routed to the same place.
+func (c *Config) basePath(service, def string) string {
+	endpoint, ok := c.CustomEndpoints[service]
+	if !ok || endpoint == "" {
+		return def
+	}
+	if !strings.HasSuffix(endpoint, "/") {
+		endpoint += "/"
+	}
+
+	log.Printf("[INFO]   -- Using custom endpoint %s for %s", endpoint, service)
+	// Some services share a default base path across API versions; the
+	// first one configured wins.
+	if _, ok := c.endpointOverrides[def]; !ok {
+		c.endpointOverrides[def] = endpoint
+	}
+	return endpoint
+}
+
+// rewriteEndpoint swaps the default base path at the start of url for its
+// custom endpoint, if one is configured.
+func (c *Config) rewriteEndpoint(url string) string {
+	var longest string
+	for def := range c.endpointOverrides {
+		if strings.HasPrefix(url, def) && len(def) > len(longest) {
+			longest = def
+		}
+	}
+	if longest == "" {
+		return url
+	}
+	return c.endpointOverrides[longest] + strings.TrimPrefix(url, longest)
+}
+
+func logAccessTokenExpiry(client *http.Client, url string) {
+	ttl, err := accessTokenTTL(client, url)
+	if err != nil {
+		log.Printf("[WARN] Unable to determine the expiry of the configured access_token: %s", err)
+	} else if ttl < accessTokenExpiryWarning {
+		log.Printf("[WARN] The configured access_token expires in %s and cannot be refreshed; long-running operations may fail", ttl)
+	} else {
+		log.Printf("[INFO] The configured access_token expires in %s", ttl)
+	}
+}
+
+// accessTokenTTL asks the tokeninfo endpoint how long the token attached to
+// client's requests remains valid for.
+func accessTokenTTL(client *http.Client, url string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return 0, err
+	}
+	res, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	defer googleapi.CloseBody(res)
+	if err := googleapi.CheckResponse(res); err != nil {
+		return 0, err
+	}
+
+	var info struct {
+		ExpiresIn json.Number `json:"expires_in"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&info); err != nil {
+		return 0, err
+	}
+	seconds, err := info.ExpiresIn.Int64()
+	if err != nil {
+		return 0, fmt.Errorf("Invalid expires_in %q: %s", info.ExpiresIn, err)
+	}
+
+	return time.Duration(seconds) * time.Second, nil
+}
+
 // accountFile represents the structure of the account file JSON file.
 type accountFile struct {
 	PrivateKeyId string `json:"private_key_id"`
@@ -1,8 +1,14 @@
 package google
 
 import (
+	"context"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
 )
 
 const testFakeCredentialsPath = "./test-fixtures/fake_account.json"
@@ -48,3 +54,105 @@ func TestConfigLoadAndValidate_accountFileJSONInvalid(t *testing.T) {
 		t.Fatalf("expected error, but got nil")
 	}
 }
+
+func TestConfigLoadAndValidate_accessToken(t *testing.T) {
+	lookedUp := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookedUp <- r.Header.Get("Authorization")
+		w.Write([]byte(`{"expires_in": "3599"}`))
+	}))
+	defer server.Close()
+
+	config := Config{
+		AccessToken:  "ya29.not-a-real-token",
+		Project:      "my-gce-project",
+		Region:       "us-central1",
+		tokenInfoURL: server.URL,
+	}
+
+	err := config.loadAndValidate()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	select {
+	case auth := <-lookedUp:
+		if auth != "Bearer ya29.not-a-real-token" {
+			t.Fatalf("expected the token expiry to be looked up with the configured access token, got %q", auth)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the token expiry to be looked up")
+	}
+
+	token, err := config.tokenSource.Token()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if token.AccessToken != "ya29.not-a-real-token" {
+		t.Fatalf("expected the configured access token, got %q", token.AccessToken)
+	}
+}
+
+func TestAccessTokenTTL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer ya29.not-a-real-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error_description": "Invalid Value"}`))
+			return
+		}
+		w.Write([]byte(`{"azp": "123", "scope": "https://www.googleapis.com/auth/cloud-platform", "expires_in": "299"}`))
+	}))
+	defer server.Close()
+
+	client := oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "ya29.not-a-real-token"}))
+	ttl, err := accessTokenTTL(client, server.URL)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if ttl != 299*time.Second {
+		t.Fatalf("expected 299s, got %s", ttl)
+	}
+
+	if _, err := accessTokenTTL(http.DefaultClient, server.URL); err == nil {
+		t.Fatalf("expected error, but got nil")
+	}
+}
+
+func TestConfigLoadAndValidate_customEndpoints(t *testing.T) {
+	config := Config{
+		Credentials: testFakeCredentialsPath,
+		Project:     "my-gce-project",
+		Region:      "us-central1",
+		Scopes:      []string{"https://www.googleapis.com/auth/cloud-platform"},
+		CustomEndpoints: map[string]string{
+			"compute":  "https://private.googleapis.com/compute/v1/projects",
+			"sqladmin": "http://localhost:8080/sql/v1beta4/",
+		},
+	}
+
+	err := config.loadAndValidate()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if expected := "https://private.googleapis.com/compute/v1/projects/"; config.clientCompute.BasePath != expected {
+		t.Errorf("expected compute base path %q, got %q", expected, config.clientCompute.BasePath)
+	}
+	if expected := "http://localhost:8080/sql/v1beta4/"; config.clientSqlAdmin.BasePath != expected {
+		t.Errorf("expected sqladmin base path %q, got %q", expected, config.clientSqlAdmin.BasePath)
+	}
+	if expected := "https://www.googleapis.com/storage/v1/"; config.clientStorage.BasePath != expected {
+		t.Errorf("expected default storage base path %q, got %q", expected, config.clientStorage.BasePath)
+	}
+
+	cases := map[string]string{
+		"https://www.googleapis.com/compute/v1/projects/p/global/backendBuckets/b": "https://private.googleapis.com/compute/v1/projects/p/global/backendBuckets/b",
+		"https://www.googleapis.com/compute/beta/projects/p/global/networks/n":     "https://www.googleapis.com/compute/beta/projects/p/global/networks/n",
+		"https://www.googleapis.com/sql/v1beta4/projects/p/instances/i":            "http://localhost:8080/sql/v1beta4/projects/p/instances/i",
+	}
+	for url, expected := range cases {
+		if v := config.rewriteEndpoint(url); v != expected {
+			t.Errorf("expected %q to be rewritten to %q, got %q", url, expected, v)
+		}
+	}
+}
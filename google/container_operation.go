@@ -1,11 +1,9 @@
 package google
 
 import (
+	"errors"
 	"fmt"
-	"log"
-	"time"
 
-	"github.com/hashicorp/terraform/helper/resource"
 	"google.golang.org/api/container/v1"
 	containerBeta "google.golang.org/api/container/v1beta1"
 )
@@ -24,62 +22,70 @@ type ContainerBetaOperationWaiter struct {
 	Location string
 }
 
-func (w *ContainerOperationWaiter) Conf() *resource.StateChangeConf {
-	return &resource.StateChangeConf{
-		Pending: []string{"PENDING", "RUNNING"},
-		Target:  []string{"DONE"},
-		Refresh: w.RefreshFunc(),
+func (w *ContainerOperationWaiter) QueryOp() error {
+	op, err := w.Service.Projects.Zones.Operations.Get(
+		w.Project, w.Zone, w.Op.Name).Do()
+	if err != nil {
+		return err
 	}
-}
 
-func (w *ContainerBetaOperationWaiter) Conf() *resource.StateChangeConf {
-	return &resource.StateChangeConf{
-		Pending: []string{"PENDING", "RUNNING"},
-		Target:  []string{"DONE"},
-		Refresh: w.RefreshFunc(),
-	}
+	w.Op = op
+	return nil
 }
 
-func (w *ContainerOperationWaiter) RefreshFunc() resource.StateRefreshFunc {
-	return func() (interface{}, string, error) {
-		resp, err := w.Service.Projects.Zones.Operations.Get(
-			w.Project, w.Zone, w.Op.Name).Do()
+// GKE reports a failure in the status message, which may be set before the
+// operation is marked as done.
+func (w *ContainerOperationWaiter) IsDone() bool {
+	return w.Op.Status == "DONE" || w.Op.StatusMessage != ""
+}
 
-		if err != nil {
-			return nil, "", err
-		}
+func (w *ContainerOperationWaiter) OpError() error {
+	if w.Op.StatusMessage != "" {
+		return errors.New(w.Op.StatusMessage)
+	}
+	return nil
+}
 
-		if resp.StatusMessage != "" {
-			return resp, resp.Status, fmt.Errorf(resp.StatusMessage)
-		}
+func (w *ContainerOperationWaiter) State() string {
+	return w.Op.Status
+}
 
-		log.Printf("[DEBUG] Progress of operation %q: %q", w.Op.Name, resp.Status)
+func (w *ContainerOperationWaiter) OpName() string {
+	return w.Op.Name
+}
 
-		return resp, resp.Status, err
+func (w *ContainerBetaOperationWaiter) QueryOp() error {
+	name := fmt.Sprintf("projects/%s/locations/%s/operations/%s",
+		w.Project, w.Location, w.Op.Name)
+	op, err := w.Service.Projects.Locations.Operations.Get(name).Do()
+	if err != nil {
+		return err
 	}
-}
 
-func (w *ContainerBetaOperationWaiter) RefreshFunc() resource.StateRefreshFunc {
-	return func() (interface{}, string, error) {
-		name := fmt.Sprintf("projects/%s/locations/%s/operations/%s",
-			w.Project, w.Location, w.Op.Name)
-		resp, err := w.Service.Projects.Locations.Operations.Get(name).Do()
+	w.Op = op
+	return nil
+}
 
-		if err != nil {
-			return nil, "", err
-		}
+func (w *ContainerBetaOperationWaiter) IsDone() bool {
+	return w.Op.Status == "DONE" || w.Op.StatusMessage != ""
+}
 
-		if resp.StatusMessage != "" {
-			return resp, resp.Status, fmt.Errorf(resp.StatusMessage)
-		}
+func (w *ContainerBetaOperationWaiter) OpError() error {
+	if w.Op.StatusMessage != "" {
+		return errors.New(w.Op.StatusMessage)
+	}
+	return nil
+}
 
-		log.Printf("[DEBUG] Progress of operation %q: %q", w.Op.Name, resp.Status)
+func (w *ContainerBetaOperationWaiter) State() string {
+	return w.Op.Status
+}
 
-		return resp, resp.Status, err
-	}
+func (w *ContainerBetaOperationWaiter) OpName() string {
+	return w.Op.Name
 }
 
-func containerOperationWait(config *Config, op *container.Operation, project, zone, activity string, timeoutMinutes, minTimeoutSeconds int) error {
+func containerOperationWait(config *Config, op *container.Operation, project, zone, activity string, timeoutMinutes int) error {
 	w := &ContainerOperationWaiter{
 		Service: config.clientContainer,
 		Op:      op,
@@ -87,11 +93,10 @@ func containerOperationWait(config *Config, op *container.Operation, project, zo
 		Zone:    zone,
 	}
 
-	state := w.Conf()
-	return waitForState(state, activity, timeoutMinutes, minTimeoutSeconds)
+	return operationWaitTime(config, w, activity, timeoutMinutes)
 }
 
-func containerBetaOperationWait(config *Config, op *containerBeta.Operation, project, location, activity string, timeoutMinutes, minTimeoutSeconds int) error {
+func containerBetaOperationWait(config *Config, op *containerBeta.Operation, project, location, activity string, timeoutMinutes int) error {
 	w := &ContainerBetaOperationWaiter{
 		Service:  config.clientContainerBeta,
 		Op:       op,
@@ -99,30 +104,19 @@ func containerBetaOperationWait(config *Config, op *containerBeta.Operation, pro
 		Location: location,
 	}
 
-	state := w.Conf()
-	return waitForState(state, activity, timeoutMinutes, minTimeoutSeconds)
-}
-
-func waitForState(state *resource.StateChangeConf, activity string, timeoutMinutes, minTimeoutSeconds int) error {
-	state.Timeout = time.Duration(timeoutMinutes) * time.Minute
-	state.MinTimeout = time.Duration(minTimeoutSeconds) * time.Second
-	_, err := state.WaitForState()
-	if err != nil {
-		return fmt.Errorf("Error waiting for %s: %s", activity, err)
-	}
-	return nil
+	return operationWaitTime(config, w, activity, timeoutMinutes)
 }
 
-func containerSharedOperationWait(config *Config, op interface{}, project, location, activity string, timeoutMinutes, minTimeoutSeconds int) error {
+func containerSharedOperationWait(config *Config, op interface{}, project, location, activity string, timeoutMinutes int) error {
 	if op == nil {
 		panic("Attempted to wait on an Operation that was nil.")
 	}
 
 	switch op.(type) {
 	case *container.Operation:
-		return containerOperationWait(config, op.(*container.Operation), project, location, activity, timeoutMinutes, minTimeoutSeconds)
+		return containerOperationWait(config, op.(*container.Operation), project, location, activity, timeoutMinutes)
 	case *containerBeta.Operation:
-		return containerBetaOperationWait(config, op.(*containerBeta.Operation), project, location, activity, timeoutMinutes, minTimeoutSeconds)
+		return containerBetaOperationWait(config, op.(*containerBeta.Operation), project, location, activity, timeoutMinutes)
 	default:
 		panic("Attempted to wait on an Operation of unknown type.")
 	}
@@ -1,11 +1,6 @@
 package google
 
 import (
-	"fmt"
-	"log"
-	"time"
-
-	"github.com/hashicorp/terraform/helper/resource"
 	"google.golang.org/api/dataproc/v1"
 )
 
@@ -14,46 +9,40 @@ type DataprocClusterOperationWaiter struct {
 	Op      *dataproc.Operation
 }
 
-func (w *DataprocClusterOperationWaiter) Conf() *resource.StateChangeConf {
-	return &resource.StateChangeConf{
-		Pending: []string{"false"},
-		Target:  []string{"true"},
-		Refresh: w.RefreshFunc(),
+func (w *DataprocClusterOperationWaiter) QueryOp() error {
+	op, err := w.Service.Projects.Regions.Operations.Get(w.Op.Name).Do()
+	if err != nil {
+		return err
 	}
+
+	w.Op = op
+	return nil
 }
 
-func (w *DataprocClusterOperationWaiter) RefreshFunc() resource.StateRefreshFunc {
-	return func() (interface{}, string, error) {
-		op, err := w.Service.Projects.Regions.Operations.Get(w.Op.Name).Do()
+func (w *DataprocClusterOperationWaiter) IsDone() bool {
+	return w.Op.Done
+}
 
-		if err != nil {
-			return nil, "", err
-		}
+func (w *DataprocClusterOperationWaiter) OpError() error {
+	if w.Op.Error != nil {
+		return OperationStatusError{Code: w.Op.Error.Code, Message: w.Op.Error.Message}
+	}
+	return nil
+}
 
-		log.Printf("[DEBUG] Got %v while polling for operation %s's 'done' status", op.Done, w.Op.Name)
+func (w *DataprocClusterOperationWaiter) State() string {
+	return doneState(w.Op.Done)
+}
 
-		return op, fmt.Sprint(op.Done), nil
-	}
+func (w *DataprocClusterOperationWaiter) OpName() string {
+	return w.Op.Name
 }
 
-func dataprocClusterOperationWait(config *Config, op *dataproc.Operation, activity string, timeoutMinutes, minTimeoutSeconds int) error {
+func dataprocClusterOperationWait(config *Config, op *dataproc.Operation, activity string, timeoutMinutes int) error {
 	w := &DataprocClusterOperationWaiter{
 		Service: config.clientDataproc,
 		Op:      op,
 	}
 
-	state := w.Conf()
-	state.Timeout = time.Duration(timeoutMinutes) * time.Minute
-	state.MinTimeout = time.Duration(minTimeoutSeconds) * time.Second
-	opRaw, err := state.WaitForState()
-	if err != nil {
-		return fmt.Errorf("Error waiting for %s: %s", activity, err)
-	}
-
-	op = opRaw.(*dataproc.Operation)
-	if op.Error != nil {
-		return fmt.Errorf("Error code %v, message: %s", op.Error.Code, op.Error.Message)
-	}
-
-	return nil
+	return operationWaitTime(config, w, activity, timeoutMinutes)
 }
@@ -2,95 +2,121 @@ package google
 
 import (
 	"fmt"
-	"time"
-
 	"net/http"
 
-	"github.com/hashicorp/terraform/helper/resource"
 	"google.golang.org/api/dataproc/v1"
 	"google.golang.org/api/googleapi"
 )
 
+// DataprocJobOperationWaiter polls a job until it reaches a final state. A
+// job that ends in ERROR or ATTEMPT_FAILURE is still considered done; callers
+// inspect the job's status themselves.
 type DataprocJobOperationWaiter struct {
 	Service   *dataproc.Service
 	Region    string
 	ProjectId string
 	JobId     string
+
+	job *dataproc.Job
+}
+
+func isNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	ae, ok := err.(*googleapi.Error)
+	return ok && ae.Code == http.StatusNotFound
 }
 
-func (w *DataprocJobOperationWaiter) ConfForDelete() *resource.StateChangeConf {
-	return &resource.StateChangeConf{
-		Pending: []string{"EXISTS"},
-		Target:  []string{"DELETED"},
-		Refresh: w.RefreshFuncForDelete(),
+func (w *DataprocJobOperationWaiter) QueryOp() error {
+	job, err := w.Service.Projects.Regions.Jobs.Get(w.ProjectId, w.Region, w.JobId).Do()
+	if err != nil {
+		return err
 	}
+
+	w.job = job
+	return nil
 }
 
-func (w *DataprocJobOperationWaiter) Conf() *resource.StateChangeConf {
+func (w *DataprocJobOperationWaiter) IsDone() bool {
 	// For more info on each of the states please see
 	// https://cloud.google.com/dataproc/docs/reference/rest/v1/projects.regions.jobs#JobStatus
-	return &resource.StateChangeConf{
-		Pending: []string{"PENDING", "CANCEL_PENDING", "CANCEL_STARTED", "SETUP_DONE", "RUNNING"},
-		Target:  []string{"CANCELLED", "DONE", "ATTEMPT_FAILURE", "ERROR"},
-		Refresh: w.RefreshFunc(),
+	switch w.State() {
+	case "CANCELLED", "DONE", "ATTEMPT_FAILURE", "ERROR":
+		return true
 	}
+	return false
 }
 
-func isNotFound(err error) bool {
-	if err == nil {
-		return false
+func (w *DataprocJobOperationWaiter) OpError() error {
+	return nil
+}
+
+func (w *DataprocJobOperationWaiter) State() string {
+	if w.job == nil || w.job.Status == nil {
+		return ""
 	}
-	ae, ok := err.(*googleapi.Error)
-	return ok && ae.Code == http.StatusNotFound
+	return w.job.Status.State
 }
 
-func (w *DataprocJobOperationWaiter) RefreshFuncForDelete() resource.StateRefreshFunc {
-	return func() (interface{}, string, error) {
-		_, err := w.Service.Projects.Regions.Jobs.Get(w.ProjectId, w.Region, w.JobId).Do()
+func (w *DataprocJobOperationWaiter) OpName() string {
+	return fmt.Sprintf("job %s", w.JobId)
+}
 
-		if err != nil {
-			if isNotFound(err) {
-				return "NA", "DELETED", nil
-			}
-			return nil, "", err
-		}
+// DataprocJobDeleteWaiter polls a job until it no longer exists.
+type DataprocJobDeleteWaiter struct {
+	Service   *dataproc.Service
+	Region    string
+	ProjectId string
+	JobId     string
 
-		return "JOB", "EXISTS", err
+	deleted bool
+}
+
+func (w *DataprocJobDeleteWaiter) QueryOp() error {
+	_, err := w.Service.Projects.Regions.Jobs.Get(w.ProjectId, w.Region, w.JobId).Do()
+	if err != nil {
+		if isNotFound(err) {
+			w.deleted = true
+			return nil
+		}
+		return err
 	}
+
+	return nil
 }
 
-func (w *DataprocJobOperationWaiter) RefreshFunc() resource.StateRefreshFunc {
-	return func() (interface{}, string, error) {
-		job, err := w.Service.Projects.Regions.Jobs.Get(w.ProjectId, w.Region, w.JobId).Do()
+func (w *DataprocJobDeleteWaiter) IsDone() bool {
+	return w.deleted
+}
 
-		if err != nil {
-			return nil, "", err
-		}
+func (w *DataprocJobDeleteWaiter) OpError() error {
+	return nil
+}
 
-		return job, job.Status.State, err
+func (w *DataprocJobDeleteWaiter) State() string {
+	if w.deleted {
+		return "DELETED"
 	}
+	return "EXISTS"
 }
 
-func dataprocDeleteOperationWait(config *Config, region, projectId, jobId string, activity string, timeoutMinutes, minTimeoutSeconds int) error {
-	w := &DataprocJobOperationWaiter{
+func (w *DataprocJobDeleteWaiter) OpName() string {
+	return fmt.Sprintf("deletion of job %s", w.JobId)
+}
+
+func dataprocDeleteOperationWait(config *Config, region, projectId, jobId string, activity string, timeoutMinutes int) error {
+	w := &DataprocJobDeleteWaiter{
 		Service:   config.clientDataproc,
 		Region:    region,
 		ProjectId: projectId,
 		JobId:     jobId,
 	}
 
-	state := w.ConfForDelete()
-	state.Timeout = time.Duration(timeoutMinutes) * time.Minute
-	state.MinTimeout = time.Duration(minTimeoutSeconds) * time.Second
-	_, err := state.WaitForState()
-	if err != nil {
-		return fmt.Errorf("Error waiting for %s: %s", activity, err)
-	}
-
-	return nil
+	return operationWaitTime(config, w, activity, timeoutMinutes)
 }
 
-func dataprocJobOperationWait(config *Config, region, projectId, jobId string, activity string, timeoutMinutes, minTimeoutSeconds int) error {
+func dataprocJobOperationWait(config *Config, region, projectId, jobId string, activity string, timeoutMinutes int) error {
 	w := &DataprocJobOperationWaiter{
 		Service:   config.clientDataproc,
 		Region:    region,
@@ -98,13 +124,5 @@ func dataprocJobOperationWait(config *Config, region, projectId, jobId string, a
 		JobId:     jobId,
 	}
 
-	state := w.Conf()
-	state.Timeout = time.Duration(timeoutMinutes) * time.Minute
-	state.MinTimeout = time.Duration(minTimeoutSeconds) * time.Second
-	_, err := state.WaitForState()
-	if err != nil {
-		return fmt.Errorf("Error waiting for operation %s: %s", activity, err)
-	}
-
-	return nil
+	return operationWaitTime(config, w, activity, timeoutMinutes)
 }
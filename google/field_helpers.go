@@ -62,6 +62,18 @@ func ParseInstanceGroupFieldValue(instanceGroup string, d TerraformResourceData,
 	return parseZonalFieldValue("instanceGroups", instanceGroup, "project", "zone", d, config, false)
 }
 
+func ParseNetworkEndpointGroupFieldValue(networkEndpointGroup string, d TerraformResourceData, config *Config) (*ZonalFieldValue, error) {
+	return parseZonalFieldValue("networkEndpointGroups", networkEndpointGroup, "project", "zone", d, config, false)
+}
+
+func ParseNodeTemplateFieldValue(nodeTemplate string, d TerraformResourceData, config *Config) (*RegionalFieldValue, error) {
+	return parseRegionalFieldValue("nodeTemplates", nodeTemplate, "project", "region", "zone", d, config, false)
+}
+
+func ParseResourcePolicyFieldValue(resourcePolicy string, d TerraformResourceData, config *Config) (*RegionalFieldValue, error) {
+	return parseRegionalFieldValue("resourcePolicies", resourcePolicy, "project", "region", "zone", d, config, false)
+}
+
 func ParseSecurityPolicyFieldValue(securityPolicy string, d TerraformResourceData, config *Config) (*GlobalFieldValue, error) {
 	return parseGlobalFieldValue("securityPolicies", securityPolicy, "project", d, config, true)
 }
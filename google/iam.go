@@ -1,6 +1,7 @@
 package google
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -36,6 +37,10 @@ type ResourceIamUpdater interface {
 type newResourceIamUpdaterFunc func(d *schema.ResourceData, config *Config) (ResourceIamUpdater, error)
 type iamPolicyModifyFunc func(p *cloudresourcemanager.Policy) error
 
+// errIamPolicyUnmodified is returned by an iamPolicyModifyFunc that left the
+// policy as it was, so that it isn't written back.
+var errIamPolicyUnmodified = errors.New("IAM policy not modified")
+
 // This method parses identifiers specific to the resource (d.GetId()) into the ResourceData
 // object, so that it can be given to the resource's Read method.  Externally, this is wrapped
 // into schema.StateFunc functions - one each for a _member, a _binding, and a _policy.  Any
@@ -62,6 +67,10 @@ func iamPolicyReadModifyWrite(updater ResourceIamUpdater, modify iamPolicyModify
 		log.Printf("[DEBUG]: Retrieved policy for %s: %+v\n", updater.DescribeResource(), p)
 
 		err = modify(p)
+		if err == errIamPolicyUnmodified {
+			log.Printf("[DEBUG]: No changes to the policy for %s\n", updater.DescribeResource())
+			return nil
+		}
 		if err != nil {
 			return err
 		}
@@ -87,7 +96,7 @@ func iamPolicyReadModifyWrite(updater ResourceIamUpdater, modify iamPolicyModify
 				// our change has been made.  'modify(p) == p' is our check for whether this has been
 				// correctly applied.
 				err = modify(modified_p)
-				if err != nil {
+				if err != nil && err != errIamPolicyUnmodified {
 					return err
 				}
 				if modified_p == new_p {
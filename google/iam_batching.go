@@ -0,0 +1,131 @@
+package google
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"google.golang.org/api/cloudresourcemanager/v1"
+)
+
+const defaultIamBatchSendAfter = 3 * time.Second
+
+// iamPolicyBatcher merges the policy modifications queued for the same
+// resource within a short window into a single read-modify-write, so that
+// many google_*_iam_member and google_*_iam_binding resources on one parent
+// don't each need their own get/set cycle.
+type iamPolicyBatcher struct {
+	sendAfter time.Duration
+
+	mu      sync.Mutex
+	batches map[string]*iamPolicyBatch
+}
+
+// An iamPolicyBatch holds the modifications waiting to be sent for the
+// resource identified by its updater's mutex key.
+type iamPolicyBatch struct {
+	updater  ResourceIamUpdater
+	requests []*iamPolicyBatchRequest
+}
+
+type iamPolicyBatchRequest struct {
+	modify iamPolicyModifyFunc
+	// err records a failure of modify itself in the latest pass over the
+	// policy, which only affects this request; the rest of the batch is
+	// still applied.
+	err  error
+	done chan error
+}
+
+func newIamPolicyBatcher(sendAfter time.Duration) *iamPolicyBatcher {
+	return &iamPolicyBatcher{
+		sendAfter: sendAfter,
+		batches:   make(map[string]*iamPolicyBatch),
+	}
+}
+
+// iamPolicyBatchedReadModifyWrite applies modify to the IAM policy of the
+// resource behind updater, batching it with other modifications to the same
+// resource if batching is enabled.
+func iamPolicyBatchedReadModifyWrite(config *Config, updater ResourceIamUpdater, modify iamPolicyModifyFunc) error {
+	if config.iamBatcher == nil {
+		return iamPolicyReadModifyWrite(updater, modify)
+	}
+	return config.iamBatcher.readModifyWrite(updater, modify)
+}
+
+// readModifyWrite queues modify and blocks until the batch it joined has
+// been sent, returning the error attributable to this modification.
+func (b *iamPolicyBatcher) readModifyWrite(updater ResourceIamUpdater, modify iamPolicyModifyFunc) error {
+	req := &iamPolicyBatchRequest{
+		modify: modify,
+		done:   make(chan error, 1),
+	}
+	key := updater.GetMutexKey()
+
+	b.mu.Lock()
+	batch, ok := b.batches[key]
+	if !ok {
+		batch = &iamPolicyBatch{updater: updater}
+		b.batches[key] = batch
+		time.AfterFunc(b.sendAfter, func() { b.send(key) })
+	}
+	batch.requests = append(batch.requests, req)
+	b.mu.Unlock()
+
+	return <-req.done
+}
+
+func (b *iamPolicyBatcher) send(key string) {
+	b.mu.Lock()
+	batch := b.batches[key]
+	delete(b.batches, key)
+	b.mu.Unlock()
+
+	log.Printf("[DEBUG]: Sending %d batched IAM policy changes for %s", len(batch.requests), batch.updater.DescribeResource())
+	err := iamPolicyReadModifyWrite(batch.updater, func(p *cloudresourcemanager.Policy) error {
+		// The policy is read again after a conflict, so errors from an
+		// earlier pass may no longer apply.
+		modified := false
+		for _, req := range batch.requests {
+			req.err = nil
+			// Apply each modification to a copy so that one failing part way
+			// through doesn't leave the others with a half-edited policy.
+			candidate, err := copyIamPolicy(p)
+			if err != nil {
+				return err
+			}
+			if err := req.modify(candidate); err != nil {
+				req.err = err
+				continue
+			}
+			*p = *candidate
+			modified = true
+		}
+		if !modified {
+			return errIamPolicyUnmodified
+		}
+		return nil
+	})
+	if err != nil && len(batch.requests) > 1 {
+		err = errwrap.Wrapf(fmt.Sprintf("Error applying batch of %d IAM policy changes for %s: {{err}}", len(batch.requests), batch.updater.DescribeResource()), err)
+	}
+
+	for _, req := range batch.requests {
+		if req.err != nil {
+			req.done <- req.err
+		} else {
+			req.done <- err
+		}
+	}
+}
+
+func copyIamPolicy(p *cloudresourcemanager.Policy) (*cloudresourcemanager.Policy, error) {
+	out := &cloudresourcemanager.Policy{}
+	if err := Convert(p, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
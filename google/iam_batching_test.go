@@ -0,0 +1,196 @@
+package google
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// fakeIamUpdater keeps a policy in memory and counts the calls made to it.
+type fakeIamUpdater struct {
+	mu     sync.Mutex
+	policy *cloudresourcemanager.Policy
+	gets   int
+	sets   int
+	setErr error
+	// conflicts is the number of set calls that fail with a conflict before
+	// one succeeds.
+	conflicts int
+}
+
+func (u *fakeIamUpdater) GetResourceIamPolicy() (*cloudresourcemanager.Policy, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.gets++
+	return copyIamPolicy(u.policy)
+}
+
+func (u *fakeIamUpdater) SetResourceIamPolicy(policy *cloudresourcemanager.Policy) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.sets++
+	if u.setErr != nil {
+		return u.setErr
+	}
+	if u.conflicts > 0 {
+		u.conflicts--
+		return &googleapi.Error{Code: 409, Message: "concurrent policy changes"}
+	}
+	u.policy = policy
+	return nil
+}
+
+func (u *fakeIamUpdater) GetMutexKey() string {
+	return "iam-fake-resource"
+}
+
+func (u *fakeIamUpdater) GetResourceId() string {
+	return "resource"
+}
+
+func (u *fakeIamUpdater) DescribeResource() string {
+	return `fake "resource"`
+}
+
+func addIamMember(role, member string) iamPolicyModifyFunc {
+	return func(p *cloudresourcemanager.Policy) error {
+		p.Bindings = mergeBindings(append(p.Bindings, &cloudresourcemanager.Binding{
+			Role:    role,
+			Members: []string{member},
+		}))
+		return nil
+	}
+}
+
+func runBatchedModifications(config *Config, updater ResourceIamUpdater, modifications []iamPolicyModifyFunc) []error {
+	errs := make([]error, len(modifications))
+	var wg sync.WaitGroup
+	for i, modify := range modifications {
+		wg.Add(1)
+		go func(i int, modify iamPolicyModifyFunc) {
+			defer wg.Done()
+			errs[i] = iamPolicyBatchedReadModifyWrite(config, updater, modify)
+		}(i, modify)
+	}
+	wg.Wait()
+	return errs
+}
+
+func TestIamPolicyBatcher(t *testing.T) {
+	t.Parallel()
+
+	updater := &fakeIamUpdater{policy: &cloudresourcemanager.Policy{}}
+	config := &Config{iamBatcher: newIamPolicyBatcher(50 * time.Millisecond)}
+
+	var modifications []iamPolicyModifyFunc
+	for i := 0; i < 5; i++ {
+		modifications = append(modifications, addIamMember("roles/viewer", fmt.Sprintf("user:user%d@example.com", i)))
+	}
+	modifications = append(modifications, func(p *cloudresourcemanager.Policy) error {
+		// Partially modify the policy before failing; none of this should
+		// be sent.
+		p.Bindings = nil
+		return errors.New("bad modification")
+	})
+
+	errs := runBatchedModifications(config, updater, modifications)
+	for i, err := range errs[:5] {
+		if err != nil {
+			t.Errorf("modification %d: unexpected error %s", i, err)
+		}
+	}
+	if errs[5] == nil || errs[5].Error() != "bad modification" {
+		t.Errorf("expected the failing modification to get its own error, got %v", errs[5])
+	}
+
+	if updater.sets != 1 {
+		t.Errorf("expected a single set call, got %d", updater.sets)
+	}
+	members := rolesToMembersMap(updater.policy.Bindings)["roles/viewer"]
+	if len(members) != 5 {
+		t.Errorf("expected all 5 members to be added, got %v", members)
+	}
+}
+
+func TestIamPolicyBatcher_setError(t *testing.T) {
+	t.Parallel()
+
+	updater := &fakeIamUpdater{
+		policy: &cloudresourcemanager.Policy{},
+		setErr: errors.New("permission denied"),
+	}
+	config := &Config{iamBatcher: newIamPolicyBatcher(50 * time.Millisecond)}
+
+	errs := runBatchedModifications(config, updater, []iamPolicyModifyFunc{
+		addIamMember("roles/viewer", "user:a@example.com"),
+		addIamMember("roles/editor", "user:b@example.com"),
+	})
+	for i, err := range errs {
+		if err == nil {
+			t.Errorf("modification %d: expected error, but got nil", i)
+		}
+	}
+	if updater.sets != 1 {
+		t.Errorf("expected a single set call, got %d", updater.sets)
+	}
+}
+
+func TestIamPolicyBatcher_noModification(t *testing.T) {
+	t.Parallel()
+
+	updater := &fakeIamUpdater{policy: &cloudresourcemanager.Policy{}}
+	config := &Config{iamBatcher: newIamPolicyBatcher(50 * time.Millisecond)}
+
+	fail := func(p *cloudresourcemanager.Policy) error {
+		return errors.New("bad modification")
+	}
+	errs := runBatchedModifications(config, updater, []iamPolicyModifyFunc{fail, fail})
+	for i, err := range errs {
+		if err == nil || err.Error() != "bad modification" {
+			t.Errorf("modification %d: expected its own error, got %v", i, err)
+		}
+	}
+	if updater.sets != 0 {
+		t.Errorf("expected no set calls, got %d", updater.sets)
+	}
+}
+
+func TestIamPolicyBatcher_conflict(t *testing.T) {
+	t.Parallel()
+
+	updater := &fakeIamUpdater{
+		policy:    &cloudresourcemanager.Policy{},
+		conflicts: 1,
+	}
+	config := &Config{iamBatcher: newIamPolicyBatcher(50 * time.Millisecond)}
+
+	// Fails against the policy read before the conflict only.
+	calls := 0
+	failOnce := func(p *cloudresourcemanager.Policy) error {
+		calls++
+		if calls == 1 {
+			return errors.New("stale policy")
+		}
+		return addIamMember("roles/editor", "user:b@example.com")(p)
+	}
+	errs := runBatchedModifications(config, updater, []iamPolicyModifyFunc{
+		addIamMember("roles/viewer", "user:a@example.com"),
+		failOnce,
+	})
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("modification %d: unexpected error %s", i, err)
+		}
+	}
+	if updater.sets != 2 {
+		t.Errorf("expected a set call before and after the conflict, got %d", updater.sets)
+	}
+	if members := rolesToMembersMap(updater.policy.Bindings)["roles/editor"]; len(members) != 1 {
+		t.Errorf("expected the modification to be applied after the conflict, got %v", members)
+	}
+}
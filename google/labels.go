@@ -0,0 +1,97 @@
+package google
+
+import (
+	"reflect"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// Resources that support the provider's default_labels keep the labels set
+// in their own configuration in a "labels" field, and every label applied to
+// the resource, defaults included, in a computed "effective_labels" field.
+// Default labels are only ever written to effective_labels, so they never
+// show up as a diff on the labels the user configured.
+
+func effectiveLabelsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeMap,
+		Computed: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+}
+
+// forceNewEffectiveLabelsSchema is effectiveLabelsSchema for resources whose
+// labels can't be changed in place, so that changing the provider's default
+// labels replaces them just like changing their own labels does.
+func forceNewEffectiveLabelsSchema() *schema.Schema {
+	s := effectiveLabelsSchema()
+	s.ForceNew = true
+	return s
+}
+
+// mergeLabels returns the default labels overlaid with labels; labels set on
+// the resource win over defaults with the same key.
+func mergeLabels(defaults, labels map[string]string) map[string]string {
+	merged := make(map[string]string, len(defaults)+len(labels))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	return merged
+}
+
+// expandEffectiveLabels returns the labels to send to the API for a resource:
+// the ones in its labelsKey field merged with the provider's default labels.
+func expandEffectiveLabels(d *schema.ResourceData, config *Config, labelsKey string) map[string]string {
+	return mergeLabels(config.DefaultLabels, expandStringMap(d, labelsKey))
+}
+
+// effectiveLabelsHaveChanged reports whether the labels sent to the API need
+// updating, either because the resource's labels or the provider's default
+// labels changed.
+func effectiveLabelsHaveChanged(d *schema.ResourceData, labelsKey string) bool {
+	return d.HasChange(labelsKey) || d.HasChange("effective_labels")
+}
+
+// setEffectiveLabels stores the labels read from the API. Labels that are
+// only there because of a default are left out of labelsKey, unless the
+// resource sets them too.
+func setEffectiveLabels(d *schema.ResourceData, config *Config, labelsKey string, apiLabels map[string]string) error {
+	configured := expandStringMap(d, labelsKey)
+	labels := make(map[string]string, len(apiLabels))
+	for k, v := range apiLabels {
+		if dv, ok := config.DefaultLabels[k]; ok && dv == v {
+			if _, ok := configured[k]; !ok {
+				continue
+			}
+		}
+		labels[k] = v
+	}
+
+	if err := d.Set(labelsKey, labels); err != nil {
+		return err
+	}
+	return d.Set("effective_labels", apiLabels)
+}
+
+// customizeDiffEffectiveLabels plans the value of effective_labels. While
+// the resource's labels are unchanged it can be worked out from state, which
+// lets changes to the provider's default labels show up in the plan; when
+// they change, they may not be known until apply.
+func customizeDiffEffectiveLabels(labelsKey string) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, meta interface{}) error {
+		if d.HasChange(labelsKey) {
+			return d.SetNewComputed("effective_labels")
+		}
+
+		config := meta.(*Config)
+		labels := mergeLabels(config.DefaultLabels, convertStringMap(d.Get(labelsKey).(map[string]interface{})))
+		old := convertStringMap(d.Get("effective_labels").(map[string]interface{}))
+		if reflect.DeepEqual(old, labels) {
+			return nil
+		}
+		return d.SetNew("effective_labels", labels)
+	}
+}
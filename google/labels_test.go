@@ -0,0 +1,88 @@
+package google
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestExpandEffectiveLabels(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceStorageBucket().Schema, map[string]interface{}{
+		"name": "bucket",
+		"labels": map[string]interface{}{
+			"env":  "prod",
+			"team": "storage",
+		},
+	})
+	config := &Config{
+		DefaultLabels: map[string]string{
+			"env":         "dev",
+			"cost-center": "1234",
+		},
+	}
+
+	expected := map[string]string{
+		"env":         "prod",
+		"team":        "storage",
+		"cost-center": "1234",
+	}
+	if v := expandEffectiveLabels(d, config, "labels"); !reflect.DeepEqual(v, expected) {
+		t.Errorf("expected %v, got %v", expected, v)
+	}
+}
+
+func TestSetEffectiveLabels(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceStorageBucket().Schema, map[string]interface{}{
+		"name": "bucket",
+		"labels": map[string]interface{}{
+			"team": "storage",
+			"env":  "dev",
+		},
+	})
+	config := &Config{
+		DefaultLabels: map[string]string{
+			"env":         "dev",
+			"cost-center": "1234",
+			"owner":       "platform",
+		},
+	}
+
+	apiLabels := map[string]string{
+		"team":        "storage",
+		"env":         "dev",
+		"cost-center": "1234",
+		"owner":       "someone-else",
+	}
+	if err := setEffectiveLabels(d, config, "labels", apiLabels); err != nil {
+		t.Fatalf("error: %s", err)
+	}
+
+	// Defaults the resource doesn't set itself are left out of labels, unless
+	// their value was changed outside of Terraform.
+	expected := map[string]string{
+		"team":  "storage",
+		"env":   "dev",
+		"owner": "someone-else",
+	}
+	if v := expandStringMap(d, "labels"); !reflect.DeepEqual(v, expected) {
+		t.Errorf("expected labels %v, got %v", expected, v)
+	}
+	if v := expandStringMap(d, "effective_labels"); !reflect.DeepEqual(v, apiLabels) {
+		t.Errorf("expected effective_labels %v, got %v", apiLabels, v)
+	}
+}
+
+func TestLabelledResourcesHaveEffectiveLabels(t *testing.T) {
+	for name, r := range Provider().(*schema.Provider).ResourcesMap {
+		if s, ok := r.Schema["labels"]; !ok || s.Type != schema.TypeMap {
+			continue
+		}
+		if _, ok := r.Schema["effective_labels"]; !ok {
+			t.Errorf("%s: expected an effective_labels field for the provider's default labels", name)
+		}
+		if r.CustomizeDiff == nil {
+			t.Errorf("%s: expected a CustomizeDiff planning effective_labels", name)
+		}
+	}
+}
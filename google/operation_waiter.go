@@ -0,0 +1,110 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	operationMinPollInterval = 2 * time.Second
+	operationMaxPollInterval = 15 * time.Second
+)
+
+// The OperationWaiter interface is implemented for each API whose long-running
+// operations are polled with operationWaitTime.
+//
+// Implementations should keep track of the latest copy of the operation.
+type OperationWaiter interface {
+	// Fetch the latest state of the operation from the API.
+	QueryOp() error
+
+	// Whether the operation has finished, successfully or not.
+	IsDone() bool
+
+	// The error a finished operation failed with, or nil if it succeeded.
+	OpError() error
+
+	// Short description of the operation's progress, used in log messages.
+	State() string
+
+	// Name of the operation, used in log and error messages.
+	OpName() string
+}
+
+// OperationStatusError is returned for operations that finished with a
+// google.rpc.Status error, which is how most APIs report failures.
+type OperationStatusError struct {
+	Code    int64
+	Message string
+}
+
+func (e OperationStatusError) Error() string {
+	return fmt.Sprintf("Error code %v, message: %s", e.Code, e.Message)
+}
+
+// operationWaitTime polls w until the operation finishes, timeoutMinutes
+// pass, or Terraform is interrupted.
+func operationWaitTime(config *Config, w OperationWaiter, activity string, timeoutMinutes int) error {
+	return operationWaitTimeContext(config.stopContext(), w, activity, timeoutMinutes)
+}
+
+// operationWaitTimeContext is operationWaitTime for callers that only have a
+// context rather than the provider Config.
+func operationWaitTimeContext(ctx context.Context, w OperationWaiter, activity string, timeoutMinutes int) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeoutMinutes)*time.Minute)
+	defer cancel()
+
+	return waitForOperation(ctx, w, activity, operationMinPollInterval, operationMaxPollInterval)
+}
+
+// waitForOperation polls w, backing off from minInterval to maxInterval,
+// until the operation finishes or ctx is done. Transient errors while
+// polling are logged and the operation is polled again.
+func waitForOperation(ctx context.Context, w OperationWaiter, activity string, minInterval, maxInterval time.Duration) error {
+	start := time.Now()
+	interval := minInterval
+	var lastState string
+
+	for {
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("Error waiting for %s: timeout while waiting for operation %s to finish (last state: %q)", activity, w.OpName(), lastState)
+			}
+			return fmt.Errorf("Error waiting for %s: interrupted; operation %s may still be running (last state: %q)", activity, w.OpName(), lastState)
+		case <-time.After(interval):
+		}
+
+		if interval = interval * 3 / 2; interval > maxInterval {
+			interval = maxInterval
+		}
+
+		if err := w.QueryOp(); err != nil {
+			if isRetryableError(err) {
+				log.Printf("[DEBUG] Transient error polling operation %s for %s, retrying: %s", w.OpName(), activity, err)
+				continue
+			}
+			return fmt.Errorf("Error waiting for %s: %s", activity, err)
+		}
+
+		if state := w.State(); state != lastState {
+			log.Printf("[DEBUG] %s: operation %s is %s after %s", activity, w.OpName(), state, time.Since(start).Truncate(time.Second))
+			lastState = state
+		}
+
+		if w.IsDone() {
+			return w.OpError()
+		}
+	}
+}
+
+// doneState describes the progress of operations that only report whether
+// they are done.
+func doneState(done bool) string {
+	if done {
+		return "DONE"
+	}
+	return "RUNNING"
+}
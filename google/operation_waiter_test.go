@@ -0,0 +1,115 @@
+package google
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// fakeOperationWaiter finishes after a fixed number of polls, optionally
+// failing some of the polls along the way.
+type fakeOperationWaiter struct {
+	pollsUntilDone int
+	pollErrs       []error
+	opErr          error
+
+	polls int
+}
+
+func (w *fakeOperationWaiter) QueryOp() error {
+	w.polls++
+	if len(w.pollErrs) > 0 {
+		err := w.pollErrs[0]
+		w.pollErrs = w.pollErrs[1:]
+		return err
+	}
+	return nil
+}
+
+func (w *fakeOperationWaiter) IsDone() bool {
+	return w.polls >= w.pollsUntilDone
+}
+
+func (w *fakeOperationWaiter) OpError() error {
+	return w.opErr
+}
+
+func (w *fakeOperationWaiter) State() string {
+	return doneState(w.IsDone())
+}
+
+func (w *fakeOperationWaiter) OpName() string {
+	return "operation-fake"
+}
+
+func TestWaitForOperation(t *testing.T) {
+	opErr := OperationStatusError{Code: 3, Message: "invalid argument"}
+	cases := map[string]struct {
+		Waiter        *fakeOperationWaiter
+		ExpectedErr   string
+		ExpectedPolls int
+	}{
+		"succeeds": {
+			Waiter:        &fakeOperationWaiter{pollsUntilDone: 3},
+			ExpectedPolls: 3,
+		},
+		"returns the operation error": {
+			Waiter:        &fakeOperationWaiter{pollsUntilDone: 2, opErr: opErr},
+			ExpectedErr:   opErr.Error(),
+			ExpectedPolls: 2,
+		},
+		"retries transient poll errors": {
+			Waiter: &fakeOperationWaiter{
+				pollsUntilDone: 2,
+				pollErrs:       []error{&googleapi.Error{Code: 503}, &googleapi.Error{Code: 429}},
+			},
+			ExpectedPolls: 3,
+		},
+		"stops on other poll errors": {
+			Waiter: &fakeOperationWaiter{
+				pollsUntilDone: 5,
+				pollErrs:       []error{&googleapi.Error{Code: 403, Message: "denied"}},
+			},
+			ExpectedErr:   "Error waiting for test: googleapi: Error 403: denied",
+			ExpectedPolls: 1,
+		},
+	}
+
+	for tn, tc := range cases {
+		err := waitForOperation(context.Background(), tc.Waiter, "test", time.Millisecond, 2*time.Millisecond)
+		if tc.ExpectedErr == "" && err != nil {
+			t.Errorf("bad: %s; unexpected error %s", tn, err)
+		}
+		if tc.ExpectedErr != "" && (err == nil || err.Error() != tc.ExpectedErr) {
+			t.Errorf("bad: %s; expected error %q, got %v", tn, tc.ExpectedErr, err)
+		}
+		if tc.Waiter.polls != tc.ExpectedPolls {
+			t.Errorf("bad: %s; expected %d polls, got %d", tn, tc.ExpectedPolls, tc.Waiter.polls)
+		}
+	}
+}
+
+func TestWaitForOperation_timeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	w := &fakeOperationWaiter{pollsUntilDone: 1000}
+	err := waitForOperation(ctx, w, "test", time.Millisecond, time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+}
+
+func TestWaitForOperation_cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	w := &fakeOperationWaiter{pollsUntilDone: 1000}
+	err := waitForOperation(ctx, w, "test", time.Millisecond, time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "interrupted") {
+		t.Fatalf("expected an interrupted error, got %v", err)
+	}
+}
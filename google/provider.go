@@ -1,12 +1,17 @@
 package google
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/mutexkv"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/hashicorp/terraform/terraform"
 )
 
@@ -15,8 +20,8 @@ var mutexKV = mutexkv.NewMutexKV()
 
 // Provider returns a terraform.ResourceProvider.
 func Provider() terraform.ResourceProvider {
-	return &schema.Provider{
-		Schema: map[string]*schema.Schema{
+	provider := &schema.Provider{
+		Schema: mergeSchemas(map[string]*schema.Schema{
 			"credentials": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
@@ -28,6 +33,15 @@ func Provider() terraform.ResourceProvider {
 				ValidateFunc: validateCredentials,
 			},
 
+			"access_token": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{
+					"GOOGLE_OAUTH_ACCESS_TOKEN",
+				}, nil),
+				ConflictsWith: []string{"credentials"},
+			},
+
 			"project": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
@@ -39,6 +53,22 @@ func Provider() terraform.ResourceProvider {
 				}, nil),
 			},
 
+			"billing_project": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{
+					"GOOGLE_BILLING_PROJECT",
+				}, nil),
+			},
+
+			"user_project_override": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{
+					"USER_PROJECT_OVERRIDE",
+				}, false),
+			},
+
 			"region": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
@@ -58,7 +88,102 @@ func Provider() terraform.ResourceProvider {
 					"CLOUDSDK_COMPUTE_ZONE",
 				}, nil),
 			},
-		},
+
+			"impersonate_service_account": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{
+					"GOOGLE_IMPERSONATE_SERVICE_ACCOUNT",
+				}, nil),
+			},
+
+			"impersonate_service_account_delegates": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"scopes": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"default_labels": &schema.Schema{
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"request_max_attempts": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultRequestMaxAttempts,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+
+			"request_min_backoff": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultRequestMinBackoff.String(),
+				ValidateFunc: validateDuration,
+			},
+
+			"request_max_backoff": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultRequestMaxBackoff.String(),
+				ValidateFunc: validateDuration,
+			},
+
+			"request_rate_limit": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"service": &schema.Schema{
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(rateLimitServices(), false),
+						},
+
+						"requests_per_second": &schema.Schema{
+							Type:         schema.TypeFloat,
+							Required:     true,
+							ValidateFunc: validatePositiveFloat,
+						},
+
+						"burst": &schema.Schema{
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+					},
+				},
+			},
+
+			"batching": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"enable_batching": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+
+						"send_after": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      defaultIamBatchSendAfter.String(),
+							ValidateFunc: validateDuration,
+						},
+					},
+				},
+			},
+		}, customEndpointsSchema()),
 
 		DataSourcesMap: map[string]*schema.Resource{
 			"google_active_folder":                   dataSourceGoogleActiveFolder(),
@@ -118,16 +243,24 @@ func Provider() terraform.ResourceProvider {
 			"google_compute_instance_group":                resourceComputeInstanceGroup(),
 			"google_compute_instance_group_manager":        resourceComputeInstanceGroupManager(),
 			"google_compute_instance_template":             resourceComputeInstanceTemplate(),
+			"google_compute_managed_ssl_certificate":       resourceComputeManagedSslCertificate(),
 			"google_compute_network":                       resourceComputeNetwork(),
+			"google_compute_network_endpoint":              resourceComputeNetworkEndpoint(),
+			"google_compute_network_endpoint_group":        resourceComputeNetworkEndpointGroup(),
 			"google_compute_network_peering":               resourceComputeNetworkPeering(),
+			"google_compute_node_group":                    resourceComputeNodeGroup(),
+			"google_compute_node_template":                 resourceComputeNodeTemplate(),
 			"google_compute_project_metadata":              resourceComputeProjectMetadata(),
+			"google_compute_per_instance_config":           resourceComputePerInstanceConfig(),
 			"google_compute_project_metadata_item":         resourceComputeProjectMetadataItem(),
+			"google_compute_resource_policy":               resourceComputeResourcePolicy(),
 			"google_compute_region_autoscaler":             resourceComputeRegionAutoscaler(),
 			"google_compute_region_backend_service":        resourceComputeRegionBackendService(),
 			"google_compute_region_instance_group_manager": resourceComputeRegionInstanceGroupManager(),
 			"google_compute_route":                         resourceComputeRoute(),
 			"google_compute_router":                        resourceComputeRouter(),
 			"google_compute_router_interface":              resourceComputeRouterInterface(),
+			"google_compute_router_nat":                    resourceComputeRouterNat(),
 			"google_compute_router_peer":                   resourceComputeRouterPeer(),
 			"google_compute_security_policy":               resourceComputeSecurityPolicy(),
 			"google_compute_shared_vpc_host_project":       resourceComputeSharedVpcHostProject(),
@@ -217,18 +350,69 @@ func Provider() terraform.ResourceProvider {
 			"google_storage_default_object_acl": resourceStorageDefaultObjectAcl(),
 			"google_storage_notification":       resourceStorageNotification(),
 		},
+	}
 
-		ConfigureFunc: providerConfigure,
+	provider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		return providerConfigure(d, provider.StopContext())
 	}
+
+	return provider
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+func providerConfigure(d *schema.ResourceData, stopCtx context.Context) (interface{}, error) {
 	credentials := d.Get("credentials").(string)
 	config := Config{
+		StopContext: stopCtx,
 		Credentials: credentials,
+		AccessToken: d.Get("access_token").(string),
 		Project:     d.Get("project").(string),
 		Region:      d.Get("region").(string),
 		Zone:        d.Get("zone").(string),
+
+		ImpersonateServiceAccount:          d.Get("impersonate_service_account").(string),
+		ImpersonateServiceAccountDelegates: convertStringArr(d.Get("impersonate_service_account_delegates").([]interface{})),
+
+		BillingProject:      d.Get("billing_project").(string),
+		UserProjectOverride: d.Get("user_project_override").(bool),
+	}
+
+	config.RequestMaxAttempts = d.Get("request_max_attempts").(int)
+	// Both durations have already been checked by validateDuration.
+	config.RequestMinBackoff, _ = time.ParseDuration(d.Get("request_min_backoff").(string))
+	config.RequestMaxBackoff, _ = time.ParseDuration(d.Get("request_max_backoff").(string))
+	if config.RequestMinBackoff > config.RequestMaxBackoff {
+		return nil, fmt.Errorf("request_min_backoff (%s) must not be greater than request_max_backoff (%s)", config.RequestMinBackoff, config.RequestMaxBackoff)
+	}
+
+	config.RequestRateLimits = make(map[string]RequestRateLimit)
+	for _, raw := range d.Get("request_rate_limit").([]interface{}) {
+		limit := raw.(map[string]interface{})
+		service := limit["service"].(string)
+		if _, ok := config.RequestRateLimits[service]; ok {
+			return nil, fmt.Errorf("request_rate_limit for %q is set more than once", service)
+		}
+		config.RequestRateLimits[service] = RequestRateLimit{
+			RequestsPerSecond: limit["requests_per_second"].(float64),
+			Burst:             limit["burst"].(int),
+		}
+	}
+
+	config.IamBatching = true
+	config.IamBatchSendAfter = defaultIamBatchSendAfter
+	if v, ok := d.GetOk("batching"); ok {
+		batching := extractFirstMapConfig(v.([]interface{}))
+		config.IamBatching = batching["enable_batching"].(bool)
+		config.IamBatchSendAfter, _ = time.ParseDuration(batching["send_after"].(string))
+	}
+
+	config.Scopes = convertStringArr(d.Get("scopes").([]interface{}))
+	config.DefaultLabels = convertStringMap(d.Get("default_labels").(map[string]interface{}))
+
+	config.CustomEndpoints = make(map[string]string)
+	for _, service := range customEndpointServices {
+		if v, ok := d.GetOk(service + "_custom_endpoint"); ok {
+			config.CustomEndpoints[service] = v.(string)
+		}
 	}
 
 	if err := config.loadAndValidate(); err != nil {
@@ -238,6 +422,30 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	return &config, nil
 }
 
+func customEndpointsSchema() map[string]*schema.Schema {
+	s := make(map[string]*schema.Schema, len(customEndpointServices))
+	for _, service := range customEndpointServices {
+		s[service+"_custom_endpoint"] = &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+			DefaultFunc: schema.MultiEnvDefaultFunc([]string{
+				"GOOGLE_" + strings.ToUpper(service) + "_CUSTOM_ENDPOINT",
+			}, nil),
+			ValidateFunc: validateCustomEndpoint,
+		}
+	}
+	return s
+}
+
+func validateCustomEndpoint(v interface{}, k string) (warnings []string, errors []error) {
+	value := v.(string)
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errors = append(errors, fmt.Errorf("%q must be an absolute http(s) URL, got %q", k, value))
+	}
+	return
+}
+
 func validateCredentials(v interface{}, k string) (warnings []string, errors []error) {
 	if v == nil || v.(string) == "" {
 		return
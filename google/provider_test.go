@@ -19,6 +19,7 @@ var credsEnvVars = []string{
 	"GOOGLE_CLOUD_KEYFILE_JSON",
 	"GCLOUD_KEYFILE_JSON",
 	"GOOGLE_USE_DEFAULT_CREDENTIALS",
+	"GOOGLE_OAUTH_ACCESS_TOKEN",
 }
 
 var projectEnvVars = []string{
@@ -0,0 +1,167 @@
+package google
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestRateLimit caps the rate of requests sent to a single API.
+type RequestRateLimit struct {
+	RequestsPerSecond float64
+	// Burst is how many requests may be sent at once after a quiet period.
+	// Defaults to RequestsPerSecond, rounded up, when unset.
+	Burst int
+}
+
+// Names of the APIs a request_rate_limit can be set for, keyed by the first
+// path segment for APIs served from www.googleapis.com and by the hostname
+// prefix for the others. They match the API's *_custom_endpoint name; the
+// beta versions of an API share its quota, and so its limit.
+var (
+	pathBasedApiServices = map[string]string{
+		"bigquery": "bigquery",
+		"compute":  "compute",
+		"dns":      "dns",
+		"sql":      "sqladmin",
+		"storage":  "storage",
+	}
+
+	hostBasedApiServices = map[string]string{
+		"cloudbilling":         "cloud_billing",
+		"cloudfunctions":       "cloudfunctions",
+		"cloudiot":             "cloudiot",
+		"cloudkms":             "kms",
+		"cloudresourcemanager": "resource_manager",
+		"container":            "container",
+		"dataflow":             "dataflow",
+		"dataproc":             "dataproc",
+		"iam":                  "iam",
+		"iamcredentials":       "iam_credentials",
+		"logging":              "logging",
+		"pubsub":               "pubsub",
+		"runtimeconfig":        "runtimeconfig",
+		"servicemanagement":    "service_management",
+		"sourcerepo":           "source_repo",
+		"spanner":              "spanner",
+	}
+)
+
+// rateLimitServices returns the names request_rate_limit accepts.
+func rateLimitServices() []string {
+	services := make([]string, 0, len(pathBasedApiServices)+len(hostBasedApiServices))
+	for _, name := range pathBasedApiServices {
+		services = append(services, name)
+	}
+	for _, name := range hostBasedApiServices {
+		services = append(services, name)
+	}
+	sort.Strings(services)
+	return services
+}
+
+// apiServiceName returns the name of the API a request URL belongs to, such
+// as "compute" or "resource_manager", or "" if it isn't a known API.
+func apiServiceName(u *url.URL) string {
+	segments := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 2)
+	if name, ok := pathBasedApiServices[segments[0]]; ok {
+		return name
+	}
+	return hostBasedApiServices[strings.SplitN(u.Hostname(), ".", 2)[0]]
+}
+
+// rateLimitTransport is an http.RoundTripper that holds requests back until
+// the token bucket for their API has capacity.
+type rateLimitTransport struct {
+	internal http.RoundTripper
+	buckets  map[string]*tokenBucket
+}
+
+func newRateLimitTransport(internal http.RoundTripper, limits map[string]RequestRateLimit) *rateLimitTransport {
+	buckets := make(map[string]*tokenBucket, len(limits))
+	for service, limit := range limits {
+		buckets[service] = newTokenBucket(limit.RequestsPerSecond, limit.Burst)
+	}
+	return &rateLimitTransport{
+		internal: internal,
+		buckets:  buckets,
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	service := apiServiceName(req.URL)
+	if bucket, ok := t.buckets[service]; ok {
+		wait := bucket.reserve()
+		if wait > 0 {
+			log.Printf("[DEBUG] Delaying %s %s by %s to stay within the %s rate limit", req.Method, req.URL, wait, service)
+			select {
+			case <-req.Context().Done():
+				bucket.cancel()
+				return nil, req.Context().Err()
+			case <-time.After(wait):
+			}
+		}
+	}
+
+	return t.internal.RoundTrip(req)
+}
+
+// tokenBucket refills at rate tokens per second up to burst tokens. Callers
+// that find it empty reserve a token ahead of time and wait until it would
+// have been refilled, so waiters are served in the order they arrived.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	now func() time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = int(math.Ceil(rate))
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}
+}
+
+// reserve takes a token from the bucket and returns how long the caller
+// must wait before using it.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if !b.last.IsZero() {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns a token taken by reserve that will not be used.
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(b.burst, b.tokens+1)
+}
@@ -0,0 +1,122 @@
+package google
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestApiServiceName(t *testing.T) {
+	cases := map[string]string{
+		"https://www.googleapis.com/compute/v1/projects/p/global/firewalls":   "compute",
+		"https://www.googleapis.com/compute/beta/projects/p/global/firewalls": "compute",
+		"https://www.googleapis.com/sql/v1beta4/projects/p/instances":         "sqladmin",
+		"https://www.googleapis.com/storage/v1/b/bucket":                      "storage",
+		"https://cloudresourcemanager.googleapis.com/v1/projects/p":           "resource_manager",
+		"https://cloudkms.googleapis.com/v1/projects/p/locations":             "kms",
+		"https://iam.googleapis.com/v1/projects/p/serviceAccounts":            "iam",
+		"https://private.googleapis.com/compute/v1/projects/p/zones":          "compute",
+		"http://localhost:8080/v1/projects/p:getIamPolicy":                    "",
+	}
+
+	for raw, expected := range cases {
+		u, err := url.Parse(raw)
+		if err != nil {
+			t.Fatalf("error parsing %q: %s", raw, err)
+		}
+		if v := apiServiceName(u); v != expected {
+			t.Errorf("expected %q for %q, got %q", expected, raw, v)
+		}
+	}
+}
+
+func TestRateLimitServicesAreCustomEndpointServices(t *testing.T) {
+	endpoints := make(map[string]bool)
+	for _, service := range customEndpointServices {
+		endpoints[service] = true
+	}
+	for _, service := range rateLimitServices() {
+		if !endpoints[service] {
+			t.Errorf("expected %q to be the name of a custom endpoint", service)
+		}
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := newTokenBucket(2, 3)
+	b.now = func() time.Time { return now }
+
+	// The full burst is available straight away.
+	for i := 0; i < 3; i++ {
+		if wait := b.reserve(); wait != 0 {
+			t.Fatalf("request %d: expected no wait, got %s", i, wait)
+		}
+	}
+
+	// Further requests queue up behind each other at the refill rate.
+	if wait := b.reserve(); wait != 500*time.Millisecond {
+		t.Fatalf("expected 500ms wait, got %s", wait)
+	}
+	if wait := b.reserve(); wait != time.Second {
+		t.Fatalf("expected 1s wait, got %s", wait)
+	}
+
+	// A cancelled reservation gives its token back to the next caller.
+	b.cancel()
+	if wait := b.reserve(); wait != time.Second {
+		t.Fatalf("expected 1s wait after cancel, got %s", wait)
+	}
+
+	// After a long quiet period the bucket refills, but only up to burst.
+	now = now.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		if wait := b.reserve(); wait != 0 {
+			t.Fatalf("request %d after refill: expected no wait, got %s", i, wait)
+		}
+	}
+	if wait := b.reserve(); wait == 0 {
+		t.Fatalf("expected the bucket to be capped at its burst size")
+	}
+}
+
+func TestTokenBucket_defaultBurst(t *testing.T) {
+	if b := newTokenBucket(2.5, 0); b.burst != 3 {
+		t.Errorf("expected burst of 3, got %g", b.burst)
+	}
+	if b := newTokenBucket(0.1, 0); b.burst != 1 {
+		t.Errorf("expected burst of 1, got %g", b.burst)
+	}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	transport := newRateLimitTransport(http.DefaultTransport, map[string]RequestRateLimit{
+		"storage": {RequestsPerSecond: 0.001, Burst: 1},
+	})
+	client := &http.Client{Transport: transport}
+
+	if _, err := client.Get(server.URL + "/storage/v1/b"); err != nil {
+		t.Fatalf("error: %s", err)
+	}
+
+	// The second request would have to wait far longer than the deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequest("GET", server.URL+"/storage/v1/b", nil)
+	if _, err := client.Do(req.WithContext(ctx)); err == nil {
+		t.Fatalf("expected the rate limited request to be cancelled")
+	}
+
+	if requests != 1 {
+		t.Fatalf("expected 1 request to reach the server, got %d", requests)
+	}
+}
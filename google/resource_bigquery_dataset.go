@@ -20,6 +20,7 @@ func resourceBigQueryDataset() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: customizeDiffEffectiveLabels("labels"),
 		Schema: map[string]*schema.Schema{
 			// DatasetId: [Required] A unique ID for this dataset, without the
 			// project name. The ID must contain only letters (a-z, A-Z), numbers
@@ -108,6 +109,10 @@ func resourceBigQueryDataset() *schema.Resource {
 				Elem:     schema.TypeString,
 			},
 
+			// EffectiveLabels: The labels applied to the dataset, including
+			// the provider's default labels.
+			"effective_labels": effectiveLabelsSchema(),
+
 			// SelfLink: [Output-only] A URL that can be used to access the resource
 			// again. You can use this URL in Get or Update requests to the
 			// resource.
@@ -170,13 +175,7 @@ func resourceDataset(d *schema.ResourceData, meta interface{}) (*bigquery.Datase
 		dataset.DefaultTableExpirationMs = int64(v.(int))
 	}
 
-	if v, ok := d.GetOk("labels"); ok {
-		labels := map[string]string{}
-
-		for k, v := range v.(map[string]interface{}) {
-			labels[k] = v.(string)
-		}
-
+	if labels := expandEffectiveLabels(d, config, "labels"); len(labels) > 0 {
 		dataset.Labels = labels
 	}
 
@@ -230,7 +229,9 @@ func resourceBigQueryDatasetRead(d *schema.ResourceData, meta interface{}) error
 
 	d.Set("project", projectID)
 	d.Set("etag", res.Etag)
-	d.Set("labels", res.Labels)
+	if err := setEffectiveLabels(d, config, "labels", res.Labels); err != nil {
+		return err
+	}
 	d.Set("self_link", res.SelfLink)
 	d.Set("description", res.Description)
 	d.Set("friendly_name", res.FriendlyName)
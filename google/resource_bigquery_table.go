@@ -21,6 +21,7 @@ func resourceBigQueryTable() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: customizeDiffEffectiveLabels("labels"),
 		Schema: map[string]*schema.Schema{
 			// TableId: [Required] The ID of the table. The ID must contain only
 			// letters (a-z, A-Z), numbers (0-9), or underscores (_). The maximum
@@ -81,6 +82,10 @@ func resourceBigQueryTable() *schema.Resource {
 				Elem:     schema.TypeString,
 			},
 
+			// EffectiveLabels: The labels applied to the table, including
+			// the provider's default labels.
+			"effective_labels": effectiveLabelsSchema(),
+
 			// Schema: [Optional] Describes the schema of this table.
 			"schema": {
 				Type:         schema.TypeString,
@@ -254,15 +259,7 @@ func resourceTable(d *schema.ResourceData, meta interface{}) (*bigquery.Table, e
 		table.FriendlyName = v.(string)
 	}
 
-	if v, ok := d.GetOk("labels"); ok {
-		labels := map[string]string{}
-
-		for k, v := range v.(map[string]interface{}) {
-			labels[k] = v.(string)
-		}
-
-		table.Labels = labels
-	}
+	table.Labels = expandEffectiveLabels(d, config, "labels")
 
 	if v, ok := d.GetOk("schema"); ok {
 		schema, err := expandSchema(v)
@@ -328,7 +325,9 @@ func resourceBigQueryTableRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("description", res.Description)
 	d.Set("expiration_time", res.ExpirationTime)
 	d.Set("friendly_name", res.FriendlyName)
-	d.Set("labels", res.Labels)
+	if err := setEffectiveLabels(d, config, "labels", res.Labels); err != nil {
+		return err
+	}
 	d.Set("creation_time", res.CreationTime)
 	d.Set("etag", res.Etag)
 	d.Set("last_modified_time", res.LastModifiedTime)
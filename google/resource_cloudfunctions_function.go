@@ -86,6 +86,8 @@ func resourceCloudFunctionsFunction() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		CustomizeDiff: customizeDiffEffectiveLabels("labels"),
+
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(5 * time.Minute),
 			Read:   schema.DefaultTimeout(5 * time.Minute),
@@ -170,6 +172,8 @@ func resourceCloudFunctionsFunction() *schema.Resource {
 				Optional: true,
 			},
 
+			"effective_labels": effectiveLabelsSchema(),
+
 			"trigger_bucket": {
 				Type:          schema.TypeString,
 				Optional:      true,
@@ -300,9 +304,7 @@ func resourceCloudFunctionsCreate(d *schema.ResourceData, meta interface{}) erro
 			"You must specify a trigger when deploying a new function.")
 	}
 
-	if _, ok := d.GetOk("labels"); ok {
-		function.Labels = expandLabels(d)
-	}
+	function.Labels = expandEffectiveLabels(d, config, "labels")
 
 	log.Printf("[DEBUG] Creating cloud function: %s", function.Name)
 	op, err := config.clientCloudFunctions.Projects.Locations.Functions.Create(
@@ -314,7 +316,7 @@ func resourceCloudFunctionsCreate(d *schema.ResourceData, meta interface{}) erro
 	// Name of function should be unique
 	d.SetId(cloudFuncId.terraformId())
 
-	err = cloudFunctionsOperationWait(config.clientCloudFunctions, op, "Creating CloudFunctions Function")
+	err = cloudFunctionsOperationWait(config, op, "Creating CloudFunctions Function")
 	if err != nil {
 		return err
 	}
@@ -345,7 +347,9 @@ func resourceCloudFunctionsRead(d *schema.ResourceData, meta interface{}) error
 		return err
 	}
 	d.Set("timeout", timeout)
-	d.Set("labels", function.Labels)
+	if err := setEffectiveLabels(d, config, "labels", function.Labels); err != nil {
+		return err
+	}
 	if function.SourceArchiveUrl != "" {
 		sourceArr := strings.Split(function.SourceArchiveUrl, "/")
 		d.Set("source_archive_bucket", sourceArr[2])
@@ -404,8 +408,8 @@ func resourceCloudFunctionsUpdate(d *schema.ResourceData, meta interface{}) erro
 		updateMaskArr = append(updateMaskArr, "timeout")
 	}
 
-	if d.HasChange("labels") {
-		function.Labels = expandLabels(d)
+	if effectiveLabelsHaveChanged(d, "labels") {
+		function.Labels = expandEffectiveLabels(d, config, "labels")
 		updateMaskArr = append(updateMaskArr, "labels")
 	}
 
@@ -419,7 +423,7 @@ func resourceCloudFunctionsUpdate(d *schema.ResourceData, meta interface{}) erro
 			return fmt.Errorf("Error while updating cloudfunction configuration: %s", err)
 		}
 
-		err = cloudFunctionsOperationWait(config.clientCloudFunctions, op,
+		err = cloudFunctionsOperationWait(config, op,
 			"Updating CloudFunctions Function")
 		if err != nil {
 			return err
@@ -442,7 +446,7 @@ func resourceCloudFunctionsDestroy(d *schema.ResourceData, meta interface{}) err
 	if err != nil {
 		return err
 	}
-	err = cloudFunctionsOperationWait(config.clientCloudFunctions, op, "Deleting CloudFunctions Function")
+	err = cloudFunctionsOperationWait(config, op, "Deleting CloudFunctions Function")
 	if err != nil {
 		return err
 	}
@@ -145,7 +145,7 @@ func resourceComputeAddressCreate(d *schema.ResourceData, meta interface{}) erro
 		Name:    v0BetaAddress.Name,
 	}.canonicalId())
 
-	err = computeSharedOperationWait(config.clientCompute, op, project, "Creating Address")
+	err = computeSharedOperationWaitConfig(config, op, project, "Creating Address")
 	if err != nil {
 		return err
 	}
@@ -229,7 +229,7 @@ func resourceComputeAddressDelete(d *schema.ResourceData, meta interface{}) erro
 		}
 	}
 
-	err = computeSharedOperationWait(config.clientCompute, op, addressId.Project, "Deleting Address")
+	err = computeSharedOperationWaitConfig(config, op, addressId.Project, "Deleting Address")
 	if err != nil {
 		return err
 	}
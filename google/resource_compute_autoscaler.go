@@ -4,11 +4,17 @@ import (
 	"fmt"
 	"log"
 
+	computeBeta "google.golang.org/api/compute/v0.beta"
 	"google.golang.org/api/compute/v1"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 )
 
+// Autoscalers are created, updated and read as raw JSON against the beta API,
+// for their modes and scale-in controls. The parts of the policy the beta client does know about are
+// still built and flattened through its types.
+
 var autoscalingPolicy *schema.Schema = &schema.Schema{
 	Type:     schema.TypeList,
 	Required: true,
@@ -31,6 +37,50 @@ var autoscalingPolicy *schema.Schema = &schema.Schema{
 				Default:  60,
 			},
 
+			"mode": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ON",
+				ValidateFunc: validation.StringInSlice([]string{"ON", "OFF", "ONLY_UP"}, false),
+			},
+
+			"scale_in_control": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"max_scaled_in_replicas": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"fixed": &schema.Schema{
+										Type:          schema.TypeInt,
+										Optional:      true,
+										ConflictsWith: []string{"autoscaling_policy.0.scale_in_control.0.max_scaled_in_replicas.0.percent"},
+									},
+
+									"percent": &schema.Schema{
+										Type:          schema.TypeInt,
+										Optional:      true,
+										ValidateFunc:  validation.IntBetween(0, 100),
+										ConflictsWith: []string{"autoscaling_policy.0.scale_in_control.0.max_scaled_in_replicas.0.fixed"},
+									},
+								},
+							},
+						},
+
+						"time_window_sec": &schema.Schema{
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  600,
+						},
+					},
+				},
+			},
+
 			"cpu_utilization": &schema.Schema{
 				Type:     schema.TypeList,
 				Optional: true,
@@ -56,12 +106,22 @@ var autoscalingPolicy *schema.Schema = &schema.Schema{
 						},
 						"target": &schema.Schema{
 							Type:     schema.TypeFloat,
-							Required: true,
+							Optional: true,
 						},
 
 						"type": &schema.Schema{
 							Type:     schema.TypeString,
-							Required: true,
+							Optional: true,
+						},
+
+						"filter": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+
+						"single_instance_assignment": &schema.Schema{
+							Type:     schema.TypeFloat,
+							Optional: true,
 						},
 					},
 				},
@@ -135,9 +195,15 @@ func resourceComputeAutoscaler() *schema.Resource {
 	}
 }
 
-func buildAutoscaler(d *schema.ResourceData) (*compute.Autoscaler, error) {
+// autoscalersUrl returns the beta API URL of the autoscalers collection in a
+// location given as "zones/<zone>" or "regions/<region>".
+func autoscalersUrl(project, location string) string {
+	return fmt.Sprintf("https://www.googleapis.com/compute/beta/projects/%s/%s/autoscalers", project, location)
+}
+
+func buildAutoscaler(d *schema.ResourceData) (map[string]interface{}, error) {
 	// Build the parameter
-	scaler := &compute.Autoscaler{
+	scaler := &computeBeta.Autoscaler{
 		Name:   d.Get("name").(string),
 		Target: d.Get("target").(string),
 	}
@@ -151,7 +217,7 @@ func buildAutoscaler(d *schema.ResourceData) (*compute.Autoscaler, error) {
 	// "optional object", so instead we have "list of maximum size 1".
 	prefix := "autoscaling_policy.0."
 
-	scaler.AutoscalingPolicy = &compute.AutoscalingPolicy{
+	scaler.AutoscalingPolicy = &computeBeta.AutoscalingPolicy{
 		MaxNumReplicas:    int64(d.Get(prefix + "max_replicas").(int)),
 		MinNumReplicas:    int64(d.Get(prefix + "min_replicas").(int)),
 		CoolDownPeriodSec: int64(d.Get(prefix + "cooldown_period").(int)),
@@ -160,20 +226,35 @@ func buildAutoscaler(d *schema.ResourceData) (*compute.Autoscaler, error) {
 	// This list is MaxItems = 1 as well - you can only have 0 or 1 cpu utilization target per autoscaler.
 	if _, ok := d.GetOk(prefix + "cpu_utilization"); ok {
 		if d.Get(prefix+"cpu_utilization.0.target").(float64) != 0 {
-			scaler.AutoscalingPolicy.CpuUtilization = &compute.AutoscalingPolicyCpuUtilization{
+			scaler.AutoscalingPolicy.CpuUtilization = &computeBeta.AutoscalingPolicyCpuUtilization{
 				UtilizationTarget: d.Get(prefix + "cpu_utilization.0.target").(float64),
 			}
 		}
 	}
-	var customMetrics []*compute.AutoscalingPolicyCustomMetricUtilization
+	var customMetrics []*computeBeta.AutoscalingPolicyCustomMetricUtilization
 	if metricCount, ok := d.GetOk(prefix + "metric.#"); ok {
 		for m := 0; m < metricCount.(int); m++ {
-			if d.Get(fmt.Sprintf("%smetric.%d.name", prefix, m)) != "" {
-				customMetrics = append(customMetrics, &compute.AutoscalingPolicyCustomMetricUtilization{
-					Metric:                d.Get(fmt.Sprintf("%smetric.%d.name", prefix, m)).(string),
-					UtilizationTarget:     d.Get(fmt.Sprintf("%smetric.%d.target", prefix, m)).(float64),
-					UtilizationTargetType: d.Get(fmt.Sprintf("%smetric.%d.type", prefix, m)).(string),
-				})
+			metricPrefix := fmt.Sprintf("%smetric.%d.", prefix, m)
+			if d.Get(metricPrefix+"name") != "" {
+				metric := &computeBeta.AutoscalingPolicyCustomMetricUtilization{
+					Metric:                   d.Get(metricPrefix + "name").(string),
+					UtilizationTarget:        d.Get(metricPrefix + "target").(float64),
+					UtilizationTargetType:    d.Get(metricPrefix + "type").(string),
+					Filter:                   d.Get(metricPrefix + "filter").(string),
+					SingleInstanceAssignment: d.Get(metricPrefix + "single_instance_assignment").(float64),
+				}
+
+				// A per-group metric is spread over the instances either by a
+				// utilization target or by how much each instance can take on,
+				// but never both.
+				if metric.SingleInstanceAssignment != 0 && (metric.UtilizationTarget != 0 || metric.UtilizationTargetType != "") {
+					return nil, fmt.Errorf("metric %q can't set target or type together with single_instance_assignment", metric.Metric)
+				}
+				if metric.SingleInstanceAssignment == 0 && (metric.UtilizationTarget == 0 || metric.UtilizationTargetType == "") {
+					return nil, fmt.Errorf("metric %q must set either target and type, or single_instance_assignment", metric.Metric)
+				}
+
+				customMetrics = append(customMetrics, metric)
 			}
 		}
 	}
@@ -184,13 +265,64 @@ func buildAutoscaler(d *schema.ResourceData) (*compute.Autoscaler, error) {
 			if lbuCount != 1 {
 				return nil, fmt.Errorf("The autoscaling_policy must have exactly one load_balancing_utilization, found %d.", lbuCount)
 			}
-			scaler.AutoscalingPolicy.LoadBalancingUtilization = &compute.AutoscalingPolicyLoadBalancingUtilization{
+			scaler.AutoscalingPolicy.LoadBalancingUtilization = &computeBeta.AutoscalingPolicyLoadBalancingUtilization{
 				UtilizationTarget: d.Get(prefix + "load_balancing_utilization.0.target").(float64),
 			}
 		}
 	}
 
-	return scaler, nil
+	body := make(map[string]interface{})
+	if err := Convert(scaler, &body); err != nil {
+		return nil, err
+	}
+
+	policy := body["autoscalingPolicy"].(map[string]interface{})
+	policy["mode"] = d.Get(prefix + "mode").(string)
+	if v, ok := d.GetOk(prefix + "scale_in_control"); ok && v.([]interface{})[0] != nil {
+		policy["scaleInControl"] = expandAutoscalerScaleInControl(v.([]interface{})[0].(map[string]interface{}))
+	}
+
+	return body, nil
+}
+
+func expandAutoscalerScaleInControl(configured map[string]interface{}) map[string]interface{} {
+	scaleInControl := map[string]interface{}{}
+	if v := configured["time_window_sec"].(int); v > 0 {
+		scaleInControl["timeWindowSec"] = v
+	}
+
+	if v := configured["max_scaled_in_replicas"].([]interface{}); len(v) > 0 && v[0] != nil {
+		replicas := v[0].(map[string]interface{})
+
+		// Only one of percent and fixed can be set.
+		if percent := replicas["percent"].(int); percent > 0 {
+			scaleInControl["maxScaledInReplicas"] = map[string]interface{}{"percent": percent}
+		} else {
+			scaleInControl["maxScaledInReplicas"] = map[string]interface{}{"fixed": replicas["fixed"].(int)}
+		}
+	}
+
+	return scaleInControl
+}
+
+func flattenAutoscalerScaleInControl(raw interface{}) []map[string]interface{} {
+	scaleInControl, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := map[string]interface{}{
+		"time_window_sec": scaleInControl["timeWindowSec"],
+	}
+	if replicas, ok := scaleInControl["maxScaledInReplicas"].(map[string]interface{}); ok {
+		if percent, ok := replicas["percent"]; ok {
+			result["max_scaled_in_replicas"] = []map[string]interface{}{{"percent": percent}}
+		} else {
+			result["max_scaled_in_replicas"] = []map[string]interface{}{{"fixed": replicas["fixed"]}}
+		}
+	}
+
+	return []map[string]interface{}{result}
 }
 
 func resourceComputeAutoscalerCreate(d *schema.ResourceData, meta interface{}) error {
@@ -219,16 +351,20 @@ func resourceComputeAutoscalerCreate(d *schema.ResourceData, meta interface{}) e
 		return err
 	}
 
-	op, err := config.clientCompute.Autoscalers.Insert(
-		project, zone.Name, scaler).Do()
+	res, err := Post(config, autoscalersUrl(project, "zones/"+zone.Name), scaler)
 	if err != nil {
 		return fmt.Errorf("Error creating Autoscaler: %s", err)
 	}
 
 	// It probably maybe worked, so store the ID now
-	d.SetId(scaler.Name)
+	d.SetId(d.Get("name").(string))
+
+	op := &compute.Operation{}
+	if err := Convert(res, op); err != nil {
+		return err
+	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Creating Autoscaler")
+	err = computeOperationWaitConfig(config, op, project, "Creating Autoscaler")
 	if err != nil {
 		return err
 	}
@@ -236,12 +372,20 @@ func resourceComputeAutoscalerCreate(d *schema.ResourceData, meta interface{}) e
 	return resourceComputeAutoscalerRead(d, meta)
 }
 
-func flattenAutoscalingPolicy(policy *compute.AutoscalingPolicy) []map[string]interface{} {
+// flattenAutoscalingPolicy flattens the autoscalingPolicy of an autoscaler
+// read as raw JSON.
+func flattenAutoscalingPolicy(rawPolicy map[string]interface{}) ([]map[string]interface{}, error) {
+	policy := &computeBeta.AutoscalingPolicy{}
+	if err := Convert(rawPolicy, policy); err != nil {
+		return nil, err
+	}
+
 	result := make([]map[string]interface{}, 0, 1)
 	policyMap := make(map[string]interface{})
 	policyMap["max_replicas"] = policy.MaxNumReplicas
 	policyMap["min_replicas"] = policy.MinNumReplicas
 	policyMap["cooldown_period"] = policy.CoolDownPeriodSec
+	policyMap["mode"] = rawPolicy["mode"]
 	if policy.CpuUtilization != nil {
 		cpuUtils := make([]map[string]interface{}, 0, 1)
 		cpuUtil := make(map[string]interface{})
@@ -263,12 +407,33 @@ func flattenAutoscalingPolicy(policy *compute.AutoscalingPolicy) []map[string]in
 			metricUtil["target"] = customMetricUtilization.UtilizationTarget
 			metricUtil["name"] = customMetricUtilization.Metric
 			metricUtil["type"] = customMetricUtilization.UtilizationTargetType
+			metricUtil["filter"] = customMetricUtilization.Filter
+			metricUtil["single_instance_assignment"] = customMetricUtilization.SingleInstanceAssignment
 			metricUtils = append(metricUtils, metricUtil)
 		}
 		policyMap["metric"] = metricUtils
 	}
+	policyMap["scale_in_control"] = flattenAutoscalerScaleInControl(rawPolicy["scaleInControl"])
 	result = append(result, policyMap)
-	return result
+	return result, nil
+}
+
+// setAutoscaler stores an autoscaler read as raw JSON.
+func setAutoscaler(d *schema.ResourceData, scaler map[string]interface{}) error {
+	d.Set("self_link", ConvertSelfLinkToV1(scaler["selfLink"].(string)))
+	d.Set("name", scaler["name"])
+	d.Set("target", ConvertSelfLinkToV1(scaler["target"].(string)))
+	d.Set("description", scaler["description"])
+	if rawPolicy, ok := scaler["autoscalingPolicy"].(map[string]interface{}); ok {
+		policy, err := flattenAutoscalingPolicy(rawPolicy)
+		if err != nil {
+			return err
+		}
+		if err := d.Set("autoscaling_policy", policy); err != nil {
+			return fmt.Errorf("Error reading autoscaling_policy: %s", err)
+		}
+	}
+	return nil
 }
 
 func resourceComputeAutoscalerRead(d *schema.ResourceData, meta interface{}) error {
@@ -288,43 +453,32 @@ func resourceComputeAutoscalerRead(d *schema.ResourceData, meta interface{}) err
 		return config.clientCompute.Autoscalers.Get(project, zone, d.Id()).Do()
 	}
 
-	var scaler *compute.Autoscaler
-	var e error
-	if zone, _ := getZone(d, config); zone != "" {
-		scaler, e = config.clientCompute.Autoscalers.Get(project, zone, d.Id()).Do()
-		if e != nil {
-			return handleNotFoundError(e, d, fmt.Sprintf("Autoscaler %q", d.Id()))
-		}
-	} else {
+	zone, _ := getZone(d, config)
+	if zone == "" {
 		// If the resource was imported, the only info we have is the ID. Try to find the resource
 		// by searching in the region of the project.
-		var resource interface{}
-		resource, e = getZonalResourceFromRegion(getAutoscaler, region, config.clientCompute, project)
+		resource, err := getZonalResourceFromRegion(getAutoscaler, region, config.clientCompute, project)
+		if err != nil {
+			return err
+		}
 
-		if e != nil {
-			return e
+		if resource == nil {
+			log.Printf("[WARN] Removing Autoscaler %q because it's gone", d.Get("name").(string))
+			d.SetId("")
+			return nil
 		}
 
-		scaler = resource.(*compute.Autoscaler)
+		zone = GetResourceNameFromSelfLink(resource.(*compute.Autoscaler).Zone)
 	}
 
-	if scaler == nil {
-		log.Printf("[WARN] Removing Autoscaler %q because it's gone", d.Get("name").(string))
-		d.SetId("")
-		return nil
+	scaler, err := Get(config, fmt.Sprintf("%s/%s", autoscalersUrl(project, "zones/"+zone), d.Id()))
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("Autoscaler %q", d.Id()))
 	}
 
 	d.Set("project", project)
-	d.Set("self_link", scaler.SelfLink)
-	d.Set("name", scaler.Name)
-	d.Set("target", scaler.Target)
-	d.Set("zone", GetResourceNameFromSelfLink(scaler.Zone))
-	d.Set("description", scaler.Description)
-	if scaler.AutoscalingPolicy != nil {
-		d.Set("autoscaling_policy", flattenAutoscalingPolicy(scaler.AutoscalingPolicy))
-	}
-
-	return nil
+	d.Set("zone", zone)
+	return setAutoscaler(d, scaler)
 }
 
 func resourceComputeAutoscalerUpdate(d *schema.ResourceData, meta interface{}) error {
@@ -345,16 +499,20 @@ func resourceComputeAutoscalerUpdate(d *schema.ResourceData, meta interface{}) e
 		return err
 	}
 
-	op, err := config.clientCompute.Autoscalers.Update(
-		project, zone, scaler).Do()
+	res, err := Put(config, fmt.Sprintf("%s?autoscaler=%s", autoscalersUrl(project, "zones/"+zone), d.Id()), scaler)
 	if err != nil {
 		return fmt.Errorf("Error updating Autoscaler: %s", err)
 	}
 
 	// It probably maybe worked, so store the ID now
-	d.SetId(scaler.Name)
+	d.SetId(d.Get("name").(string))
+
+	op := &compute.Operation{}
+	if err := Convert(res, op); err != nil {
+		return err
+	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Updating Autoscaler")
+	err = computeOperationWaitConfig(config, op, project, "Updating Autoscaler")
 	if err != nil {
 		return err
 	}
@@ -380,7 +538,7 @@ func resourceComputeAutoscalerDelete(d *schema.ResourceData, meta interface{}) e
 		return fmt.Errorf("Error deleting autoscaler: %s", err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Deleting Autoscaler")
+	err = computeOperationWaitConfig(config, op, project, "Deleting Autoscaler")
 	if err != nil {
 		return err
 	}
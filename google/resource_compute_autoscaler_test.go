@@ -103,6 +103,36 @@ func TestAccComputeAutoscaler_multicondition(t *testing.T) {
 	})
 }
 
+func TestAccComputeAutoscaler_scaleInControl(t *testing.T) {
+	t.Parallel()
+
+	var it_name = fmt.Sprintf("autoscaler-test-%s", acctest.RandString(10))
+	var tp_name = fmt.Sprintf("autoscaler-test-%s", acctest.RandString(10))
+	var igm_name = fmt.Sprintf("autoscaler-test-%s", acctest.RandString(10))
+	var autoscaler_name = fmt.Sprintf("autoscaler-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeAutoscalerDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeAutoscaler_scaleInControl(it_name, tp_name, igm_name, autoscaler_name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("google_compute_autoscaler.foobar", "autoscaling_policy.0.mode", "ONLY_UP"),
+					resource.TestCheckResourceAttr("google_compute_autoscaler.foobar", "autoscaling_policy.0.scale_in_control.0.max_scaled_in_replicas.0.percent", "10"),
+					resource.TestCheckResourceAttr("google_compute_autoscaler.foobar", "autoscaling_policy.0.metric.0.single_instance_assignment", "65535"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_autoscaler.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func testAccCheckComputeAutoscalerDestroy(s *terraform.State) error {
 	config := testAccProvider.Meta().(*Config)
 
@@ -327,3 +357,31 @@ resource "google_compute_autoscaler" "foobar" {
 }
 `, autoscaler_name)
 }
+
+func testAccComputeAutoscaler_scaleInControl(it_name, tp_name, igm_name, autoscaler_name string) string {
+	return testAccComputeAutoscaler_scaffolding(it_name, tp_name, igm_name) + fmt.Sprintf(`
+resource "google_compute_autoscaler" "foobar" {
+	description = "Resource created for Terraform acceptance testing"
+	name = "%s"
+	zone = "us-central1-a"
+	target = "${google_compute_instance_group_manager.foobar.self_link}"
+	autoscaling_policy = {
+		max_replicas = 10
+		min_replicas = 1
+		cooldown_period = 60
+		mode = "ONLY_UP"
+		metric {
+			name = "pubsub.googleapis.com/subscription/num_undelivered_messages"
+			filter = "resource.type = pubsub_subscription AND resource.label.subscription_id = our-subscription"
+			single_instance_assignment = 65535
+		}
+		scale_in_control {
+			max_scaled_in_replicas {
+				percent = 10
+			}
+			time_window_sec = 300
+		}
+	}
+}
+`, autoscaler_name)
+}
@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/hashicorp/errwrap"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	computeBeta "google.golang.org/api/compute/v0.beta"
 	compute "google.golang.org/api/compute/v1"
 )
@@ -80,9 +82,10 @@ func resourceComputeBackendService() *schema.Resource {
 							DiffSuppressFunc: compareSelfLinkRelativePaths,
 						},
 						"balancing_mode": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-							Default:  "UTILIZATION",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "UTILIZATION",
+							ValidateFunc: validation.StringInSlice([]string{"UTILIZATION", "RATE", "CONNECTION"}, false),
 						},
 						"capacity_scaler": &schema.Schema{
 							Type:     schema.TypeFloat,
@@ -110,9 +113,10 @@ func resourceComputeBackendService() *schema.Resource {
 							Optional: true,
 						},
 						"max_utilization": &schema.Schema{
-							Type:     schema.TypeFloat,
-							Optional: true,
-							Default:  0.8,
+							Type:             schema.TypeFloat,
+							Optional:         true,
+							Default:          0.8,
+							DiffSuppressFunc: suppressNetworkEndpointGroupMaxUtilization,
 						},
 					},
 				},
@@ -233,6 +237,8 @@ func resourceComputeBackendService() *schema.Resource {
 				Default:  300,
 			},
 		},
+
+		CustomizeDiff: customizeDiffBackendServiceBalancingModes,
 	}
 }
 
@@ -250,8 +256,18 @@ func resourceComputeBackendServiceCreate(d *schema.ResourceData, meta interface{
 	}
 
 	log.Printf("[DEBUG] Creating new Backend Service: %#v", service)
-	op, err := config.clientCompute.BackendServices.Insert(
-		project, service).Do()
+	var op interface{}
+	if backendServiceHasNetworkEndpointGroup(d.Get("backend")) {
+		betaService := &computeBeta.BackendService{}
+		if err := Convert(service, betaService); err != nil {
+			return err
+		}
+		op, err = config.clientComputeBeta.BackendServices.Insert(
+			project, betaService).Do()
+	} else {
+		op, err = config.clientCompute.BackendServices.Insert(
+			project, service).Do()
+	}
 	if err != nil {
 		return fmt.Errorf("Error creating backend service: %s", err)
 	}
@@ -262,7 +278,7 @@ func resourceComputeBackendServiceCreate(d *schema.ResourceData, meta interface{
 	d.SetId(service.Name)
 
 	// Wait for the operation to complete
-	waitErr := computeOperationWait(config.clientCompute, op, project, "Creating Backend Service")
+	waitErr := computeSharedOperationWaitConfig(config, op, project, "Creating Backend Service")
 	if waitErr != nil {
 		// The resource didn't actually create
 		d.SetId("")
@@ -278,7 +294,7 @@ func resourceComputeBackendServiceCreate(d *schema.ResourceData, meta interface{
 		if err != nil {
 			return errwrap.Wrapf("Error setting Backend Service security policy: {{err}}", err)
 		}
-		waitErr := computeSharedOperationWait(config.clientCompute, op, project, "Adding Backend Service Security Policy")
+		waitErr := computeSharedOperationWaitConfig(config, op, project, "Adding Backend Service Security Policy")
 		if waitErr != nil {
 			return waitErr
 		}
@@ -289,6 +305,9 @@ func resourceComputeBackendServiceCreate(d *schema.ResourceData, meta interface{
 
 func resourceComputeBackendServiceRead(d *schema.ResourceData, meta interface{}) error {
 	computeApiVersion := getComputeApiVersion(d, BackendServiceBaseApiVersion, BackendServiceVersionedFeatures)
+	if backendServiceHasNetworkEndpointGroup(d.Get("backend")) {
+		computeApiVersion = v0beta
+	}
 	config := meta.(*Config)
 
 	project, err := getProject(d, config)
@@ -325,7 +344,7 @@ func resourceComputeBackendServiceRead(d *schema.ResourceData, meta interface{})
 	d.Set("session_affinity", service.SessionAffinity)
 	d.Set("timeout_sec", service.TimeoutSec)
 	d.Set("fingerprint", service.Fingerprint)
-	d.Set("self_link", service.SelfLink)
+	d.Set("self_link", ConvertSelfLinkToV1(service.SelfLink))
 	d.Set("backend", flattenBackends(service.Backends))
 	d.Set("connection_draining_timeout_sec", service.ConnectionDraining.DrainingTimeoutSec)
 	d.Set("iap", flattenIap(service.Iap))
@@ -354,13 +373,24 @@ func resourceComputeBackendServiceUpdate(d *schema.ResourceData, meta interface{
 	}
 
 	log.Printf("[DEBUG] Updating existing Backend Service %q: %#v", d.Id(), service)
-	op, err := config.clientCompute.BackendServices.Update(
-		project, d.Id(), service).Do()
+	var op interface{}
+	oldBackends, newBackends := d.GetChange("backend")
+	if backendServiceHasNetworkEndpointGroup(oldBackends) || backendServiceHasNetworkEndpointGroup(newBackends) {
+		betaService := &computeBeta.BackendService{}
+		if err := Convert(service, betaService); err != nil {
+			return err
+		}
+		op, err = config.clientComputeBeta.BackendServices.Update(
+			project, d.Id(), betaService).Do()
+	} else {
+		op, err = config.clientCompute.BackendServices.Update(
+			project, d.Id(), service).Do()
+	}
 	if err != nil {
 		return fmt.Errorf("Error updating backend service: %s", err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Updating Backend Service")
+	err = computeSharedOperationWaitConfig(config, op, project, "Updating Backend Service")
 	if err != nil {
 		return err
 	}
@@ -377,7 +407,7 @@ func resourceComputeBackendServiceUpdate(d *schema.ResourceData, meta interface{
 		if err != nil {
 			return err
 		}
-		waitErr := computeSharedOperationWait(config.clientCompute, op, project, "Adding Backend Service Security Policy")
+		waitErr := computeSharedOperationWaitConfig(config, op, project, "Adding Backend Service Security Policy")
 		if waitErr != nil {
 			return waitErr
 		}
@@ -401,7 +431,7 @@ func resourceComputeBackendServiceDelete(d *schema.ResourceData, meta interface{
 		return fmt.Errorf("Error deleting backend service: %s", err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Deleting Backend Service")
+	err = computeOperationWaitConfig(config, op, project, "Deleting Backend Service")
 	if err != nil {
 		return err
 	}
@@ -454,6 +484,7 @@ func expandBackends(configured []interface{}) ([]*compute.Backend, error) {
 		b := compute.Backend{
 			Group: g.(string),
 		}
+		isNeg := isNetworkEndpointGroupLink(b.Group)
 
 		if v, ok := data["balancing_mode"]; ok {
 			b.BalancingMode = v.(string)
@@ -489,7 +520,8 @@ func expandBackends(configured []interface{}) ([]*compute.Backend, error) {
 				b.NullFields = append(b.NullFields, "MaxConnectionsPerInstance")
 			}
 		}
-		if v, ok := data["max_utilization"]; ok {
+		// Network endpoint groups don't support a utilization limit.
+		if v, ok := data["max_utilization"]; ok && !isNeg {
 			b.MaxUtilization = v.(float64)
 			b.ForceSendFields = append(b.ForceSendFields, "MaxUtilization")
 		}
@@ -521,6 +553,78 @@ func flattenBackends(backends []*computeBeta.Backend) []map[string]interface{} {
 	return result
 }
 
+// backendServiceHasNetworkEndpointGroup reports whether any of a set of
+// backends is a network endpoint group. Those are only in the beta API, so
+// backend services with one are managed through it.
+func backendServiceHasNetworkEndpointGroup(backends interface{}) bool {
+	for _, v := range backends.(*schema.Set).List() {
+		if isNetworkEndpointGroupLink(v.(map[string]interface{})["group"].(string)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Network endpoint groups don't support a utilization limit, so it isn't sent
+// for them and the API returns none.
+func suppressNetworkEndpointGroupMaxUtilization(k, old, new string, d *schema.ResourceData) bool {
+	group := d.Get(strings.TrimSuffix(k, "max_utilization") + "group").(string)
+	return isNetworkEndpointGroupLink(group)
+}
+
+// customizeDiffBackendServiceBalancingModes rejects backends whose balancing
+// mode doesn't fit their limits or group, which the API would only refuse at
+// apply time.
+func customizeDiffBackendServiceBalancingModes(d *schema.ResourceDiff, meta interface{}) error {
+	protocol := d.Get("protocol").(string)
+	for _, v := range d.Get("backend").(*schema.Set).List() {
+		if err := validateBackendServiceBalancingMode(v.(map[string]interface{}), protocol); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateBackendServiceBalancingMode(backend map[string]interface{}, protocol string) error {
+	group := backend["group"].(string)
+	mode := backend["balancing_mode"].(string)
+	maxRate := backend["max_rate"].(int) != 0
+	maxRatePerInstance := backend["max_rate_per_instance"].(float64) != 0
+	maxConnections := backend["max_connections"].(int) != 0
+	maxConnectionsPerInstance := backend["max_connections_per_instance"].(int) != 0
+
+	if isNetworkEndpointGroupLink(group) {
+		if mode != "RATE" && mode != "CONNECTION" {
+			return fmt.Errorf("Backend %q is a network endpoint group, which only supports the RATE and CONNECTION balancing modes, not %s", group, mode)
+		}
+		if maxRatePerInstance || maxConnectionsPerInstance {
+			return fmt.Errorf("Backend %q is a network endpoint group, which doesn't support max_rate_per_instance or max_connections_per_instance. Use max_rate or max_connections instead", group)
+		}
+	}
+
+	switch mode {
+	case "RATE":
+		if maxConnections || maxConnectionsPerInstance {
+			return fmt.Errorf("Backend %q uses the RATE balancing mode, which doesn't support max_connections or max_connections_per_instance", group)
+		}
+		if !maxRate && !maxRatePerInstance {
+			return fmt.Errorf("Backend %q uses the RATE balancing mode, which requires max_rate or max_rate_per_instance", group)
+		}
+	case "CONNECTION":
+		if maxRate || maxRatePerInstance {
+			return fmt.Errorf("Backend %q uses the CONNECTION balancing mode, which doesn't support max_rate or max_rate_per_instance", group)
+		}
+		if !maxConnections && !maxConnectionsPerInstance {
+			return fmt.Errorf("Backend %q uses the CONNECTION balancing mode, which requires max_connections or max_connections_per_instance", group)
+		}
+		if protocol == "HTTP" || protocol == "HTTPS" || protocol == "HTTP2" {
+			return fmt.Errorf("Backend %q uses the CONNECTION balancing mode, which isn't supported with the %s protocol", group, protocol)
+		}
+	}
+
+	return nil
+}
+
 func expandBackendService(d *schema.ResourceData) (*compute.BackendService, error) {
 	hc := d.Get("health_checks").(*schema.Set).List()
 	healthChecks := make([]string, 0, len(hc))
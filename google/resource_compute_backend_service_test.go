@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
@@ -588,6 +589,170 @@ func TestAccComputeBackendService_withMaxConnectionsPerInstance(t *testing.T) {
 	}
 }
 
+func TestAccComputeBackendService_withNetworkEndpointGroupBackend(t *testing.T) {
+	t.Parallel()
+
+	serviceName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	negName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	instanceName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	checkName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeBackendServiceDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeBackendService_withNetworkEndpointGroupBackend(
+					serviceName, negName, instanceName, checkName, 100),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"google_compute_backend_service.foobar", "backend.#", "1"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_backend_service.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			resource.TestStep{
+				Config: testAccComputeBackendService_withNetworkEndpointGroupBackend(
+					serviceName, negName, instanceName, checkName, 200),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_backend_service.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestValidateBackendServiceBalancingMode(t *testing.T) {
+	instanceGroup := "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/instanceGroups/my-group"
+	neg := "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/networkEndpointGroups/my-neg"
+
+	cases := map[string]struct {
+		Group                     string
+		Mode                      string
+		Protocol                  string
+		MaxRate                   int
+		MaxRatePerInstance        float64
+		MaxConnections            int
+		MaxConnectionsPerInstance int
+		ExpectError               bool
+	}{
+		"instance group utilization": {Group: instanceGroup, Mode: "UTILIZATION"},
+		"instance group rate":        {Group: instanceGroup, Mode: "RATE", MaxRatePerInstance: 10},
+		"instance group connection":  {Group: instanceGroup, Mode: "CONNECTION", Protocol: "TCP", MaxConnections: 10},
+		"rate without a limit":       {Group: instanceGroup, Mode: "RATE", ExpectError: true},
+		"rate with connections":      {Group: instanceGroup, Mode: "RATE", MaxRate: 10, MaxConnections: 10, ExpectError: true},
+		"connection with rate":       {Group: instanceGroup, Mode: "CONNECTION", MaxConnections: 10, MaxRate: 10, ExpectError: true},
+		"connection over http":       {Group: instanceGroup, Mode: "CONNECTION", Protocol: "HTTP", MaxConnections: 10, ExpectError: true},
+		"neg rate":                   {Group: neg, Mode: "RATE", MaxRate: 100},
+		"neg connection":             {Group: neg, Mode: "CONNECTION", Protocol: "SSL", MaxConnections: 100},
+		"neg utilization":            {Group: neg, Mode: "UTILIZATION", ExpectError: true},
+		"neg rate per instance":      {Group: neg, Mode: "RATE", MaxRatePerInstance: 10, ExpectError: true},
+	}
+
+	for tn, tc := range cases {
+		backend := map[string]interface{}{
+			"group":                        tc.Group,
+			"balancing_mode":               tc.Mode,
+			"max_rate":                     tc.MaxRate,
+			"max_rate_per_instance":        tc.MaxRatePerInstance,
+			"max_connections":              tc.MaxConnections,
+			"max_connections_per_instance": tc.MaxConnectionsPerInstance,
+		}
+
+		err := validateBackendServiceBalancingMode(backend, tc.Protocol)
+		if (err != nil) != tc.ExpectError {
+			t.Errorf("%s: expected error: %t, got %v", tn, tc.ExpectError, err)
+		}
+	}
+}
+
+func TestComputeBackendServiceDiff_networkEndpointGroupMaxUtilization(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		Group      string
+		ExpectDiff bool
+	}{
+		"network endpoint group": {
+			Group: "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/networkEndpointGroups/my-neg",
+		},
+		"instance group": {
+			Group:      "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/instanceGroups/my-group",
+			ExpectDiff: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		backend := map[string]interface{}{
+			"group":                        tc.Group,
+			"balancing_mode":               "RATE",
+			"capacity_scaler":              1.0,
+			"description":                  "",
+			"max_rate":                     100,
+			"max_rate_per_instance":        0.0,
+			"max_connections":              0,
+			"max_connections_per_instance": 0,
+		}
+		oldPrefix := fmt.Sprintf("backend.%d.", resourceGoogleComputeBackendServiceBackendHash(backend))
+		backend["max_rate"] = 200
+		prefix := fmt.Sprintf("backend.%d.", resourceGoogleComputeBackendServiceBackendHash(backend))
+
+		state := &terraform.InstanceState{
+			ID: "service-1",
+			Attributes: map[string]string{
+				"name":                                     "service-1",
+				"health_checks.#":                          "1",
+				"health_checks.1":                          "https://www.googleapis.com/compute/v1/projects/my-project/global/healthChecks/check",
+				"backend.#":                                "1",
+				oldPrefix + "group":                        tc.Group,
+				oldPrefix + "balancing_mode":               "RATE",
+				oldPrefix + "capacity_scaler":              "1",
+				oldPrefix + "description":                  "",
+				oldPrefix + "max_rate":                     "100",
+				oldPrefix + "max_rate_per_instance":        "0",
+				oldPrefix + "max_connections":              "0",
+				oldPrefix + "max_connections_per_instance": "0",
+				oldPrefix + "max_utilization":              "0",
+			},
+		}
+
+		rc, err := config.NewRawConfig(map[string]interface{}{
+			"name":          "service-1",
+			"health_checks": []interface{}{"https://www.googleapis.com/compute/v1/projects/my-project/global/healthChecks/check"},
+			"backend": []interface{}{
+				map[string]interface{}{
+					"group":          tc.Group,
+					"balancing_mode": "RATE",
+					"max_rate":       200,
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("%s: bad config: %s", tn, err)
+		}
+
+		diff, err := resourceComputeBackendService().Diff(state, terraform.NewResourceConfig(rc), &Config{})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tn, err)
+			continue
+		}
+
+		utilizationDiff := false
+		if diff != nil {
+			_, utilizationDiff = diff.GetAttribute(prefix + "max_utilization")
+		}
+		if utilizationDiff != tc.ExpectDiff {
+			t.Errorf("%s: expected a diff for max_utilization to be %t, got %t", tn, tc.ExpectDiff, utilizationDiff)
+		}
+	}
+}
+
 func testAccComputeBackendService_basic(serviceName, checkName string) string {
 	return fmt.Sprintf(`
 resource "google_compute_backend_service" "foobar" {
@@ -955,3 +1120,60 @@ resource "google_compute_health_check" "default" {
 }
 `, serviceName, maxConnectionsPerInstance, igName, itName, checkName)
 }
+
+func testAccComputeBackendService_withNetworkEndpointGroupBackend(serviceName, negName, instanceName, checkName string, maxRate int) string {
+	return fmt.Sprintf(`
+resource "google_compute_backend_service" "foobar" {
+  name          = "%s"
+  health_checks = ["${google_compute_health_check.default.self_link}"]
+
+  backend {
+    group          = "${google_compute_network_endpoint_group.neg.self_link}"
+    balancing_mode = "RATE"
+    max_rate       = %d
+  }
+
+  depends_on = ["google_compute_network_endpoint.endpoint"]
+}
+
+resource "google_compute_network_endpoint_group" "neg" {
+  name         = "%s"
+  network      = "default"
+  default_port = 90
+  zone         = "us-central1-a"
+}
+
+resource "google_compute_network_endpoint" "endpoint" {
+  network_endpoint_group = "${google_compute_network_endpoint_group.neg.name}"
+  zone                   = "us-central1-a"
+
+  instance   = "${google_compute_instance.endpoint.name}"
+  ip_address = "${google_compute_instance.endpoint.network_interface.0.network_ip}"
+  port       = 90
+}
+
+resource "google_compute_instance" "endpoint" {
+  name         = "%s"
+  machine_type = "n1-standard-1"
+  zone         = "us-central1-a"
+
+  boot_disk {
+    initialize_params {
+      image = "debian-cloud/debian-9"
+    }
+  }
+
+  network_interface {
+    network = "default"
+  }
+}
+
+resource "google_compute_health_check" "default" {
+  name = "%s"
+
+  http_health_check {
+    port = 90
+  }
+}
+`, serviceName, maxRate, negName, instanceName, checkName)
+}
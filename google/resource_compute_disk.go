@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform/helper/customdiff"
 	"github.com/hashicorp/terraform/helper/schema"
 	"google.golang.org/api/compute/v1"
 	"google.golang.org/api/googleapi"
@@ -20,6 +22,14 @@ var (
 	computeDiskUserRegex = regexp.MustCompile(computeDiskUserRegexString)
 )
 
+var DiskBaseApiVersion = v1
+var DiskVersionedFeatures = []Feature{
+	Feature{Version: v0beta, Item: "disk_encryption_key.0.kms_key_self_link"},
+	Feature{Version: v0beta, Item: "source_image_encryption_key.0.kms_key_self_link"},
+	Feature{Version: v0beta, Item: "physical_block_size_bytes"},
+	Feature{Version: v0beta, Item: "resource_policies"},
+}
+
 func resourceComputeDisk() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceComputeDiskCreate,
@@ -51,12 +61,19 @@ func resourceComputeDisk() *schema.Resource {
 			},
 
 			"disk_encryption_key_raw": &schema.Schema{
-				Type:      schema.TypeString,
-				Optional:  true,
-				ForceNew:  true,
-				Sensitive: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"disk_encryption_key"},
 			},
 
+			"disk_encryption_key": func() *schema.Schema {
+				s := customerEncryptionKeySchema()
+				s.ConflictsWith = []string{"disk_encryption_key_raw"}
+				return s
+			}(),
+
 			"disk_encryption_key_sha256": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
@@ -69,6 +86,15 @@ func resourceComputeDisk() *schema.Resource {
 				DiffSuppressFunc: diskImageDiffSuppress,
 			},
 
+			"source_image_encryption_key": customerEncryptionKeySchema(),
+
+			"physical_block_size_bytes": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
 			"project": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
@@ -82,6 +108,11 @@ func resourceComputeDisk() *schema.Resource {
 				Computed: true,
 			},
 
+			"recreate_on_size_decrease": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
 			"self_link": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
@@ -113,11 +144,28 @@ func resourceComputeDisk() *schema.Resource {
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
 
+			"effective_labels": effectiveLabelsSchema(),
+
 			"label_fingerprint": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+
+			"resource_policies": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					DiffSuppressFunc: compareSelfLinkOrResourceName,
+				},
+			},
 		},
+
+		CustomizeDiff: customdiff.All(
+			customizeDiffDiskSizeDecrease,
+			customizeDiffDiskUsers,
+			customizeDiffEffectiveLabels("labels"),
+		),
 	}
 }
 
@@ -198,12 +246,41 @@ func resourceComputeDiskCreate(d *schema.ResourceData, meta interface{}) error {
 		disk.DiskEncryptionKey.RawKey = v.(string)
 	}
 
-	if _, ok := d.GetOk("labels"); ok {
-		disk.Labels = expandLabels(d)
+	disk.Labels = expandEffectiveLabels(d, config, "labels")
+
+	rawFields := make(map[string]interface{})
+	diskEncryptionKey, err := expandCustomerEncryptionKey(d, "disk_encryption_key", config)
+	if err != nil {
+		return err
+	}
+	if diskEncryptionKey != nil {
+		rawFields["diskEncryptionKey"] = diskEncryptionKey
+	}
+	sourceImageEncryptionKey, err := expandCustomerEncryptionKey(d, "source_image_encryption_key", config)
+	if err != nil {
+		return err
+	}
+	if sourceImageEncryptionKey != nil {
+		rawFields["sourceImageEncryptionKey"] = sourceImageEncryptionKey
+	}
+	if v, ok := d.GetOk("physical_block_size_bytes"); ok {
+		rawFields["physicalBlockSizeBytes"] = strconv.Itoa(v.(int))
+	}
+	if v, ok := d.GetOk("resource_policies"); ok {
+		rawFields["resourcePolicies"], err = expandDiskResourcePolicies(d, config, v.([]interface{}))
+		if err != nil {
+			return err
+		}
 	}
 
-	op, err := config.clientCompute.Disks.Insert(
-		project, z, disk).Do()
+	var op *compute.Operation
+	if len(rawFields) > 0 {
+		url := fmt.Sprintf("https://www.googleapis.com/compute/beta/projects/%s/zones/%s/disks", project, z)
+		op, err = postWithRawFields(config, url, disk, rawFields)
+	} else {
+		op, err = config.clientCompute.Disks.Insert(
+			project, z, disk).Do()
+	}
 	if err != nil {
 		return fmt.Errorf("Error creating disk: %s", err)
 	}
@@ -211,11 +288,12 @@ func resourceComputeDiskCreate(d *schema.ResourceData, meta interface{}) error {
 	// It probably maybe worked, so store the ID now
 	d.SetId(disk.Name)
 
-	err = computeOperationWaitTime(config.clientCompute, op, project, "Creating Disk", int(d.Timeout(schema.TimeoutCreate).Minutes()))
+	err = computeOperationWaitTimeConfig(config, op, project, "Creating Disk", int(d.Timeout(schema.TimeoutCreate).Minutes()))
 	if err != nil {
 		d.SetId("")
 		return err
 	}
+
 	return resourceComputeDiskRead(d, meta)
 }
 
@@ -242,15 +320,15 @@ func resourceComputeDiskUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 		d.SetPartial("size")
 
-		err = computeOperationWaitTime(config.clientCompute, op, project, "Resizing Disk", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+		err = computeOperationWaitTimeConfig(config, op, project, "Resizing Disk", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
 		if err != nil {
 			return err
 		}
 	}
 
-	if d.HasChange("labels") {
+	if effectiveLabelsHaveChanged(d, "labels") {
 		zslr := compute.ZoneSetLabelsRequest{
-			Labels:           expandLabels(d),
+			Labels:           expandEffectiveLabels(d, config, "labels"),
 			LabelFingerprint: d.Get("label_fingerprint").(string),
 		}
 		op, err := config.clientCompute.Disks.SetLabels(
@@ -259,12 +337,34 @@ func resourceComputeDiskUpdate(d *schema.ResourceData, meta interface{}) error {
 			return fmt.Errorf("Error when setting labels: %s", err)
 		}
 		d.SetPartial("labels")
+		d.SetPartial("effective_labels")
 
-		err = computeOperationWaitTime(config.clientCompute, op, project, "Setting labels on disk", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+		err = computeOperationWaitTimeConfig(config, op, project, "Setting labels on disk", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
 		if err != nil {
 			return err
 		}
 	}
+
+	if d.HasChange("resource_policies") {
+		o, n := d.GetChange("resource_policies")
+		removed, added := diffResourcePolicies(o.([]interface{}), n.([]interface{}))
+
+		// A disk can't have two snapshot schedules at once, so detach the old
+		// policies before attaching the new ones.
+		if len(removed) > 0 {
+			err = updateDiskResourcePolicies(d, config, project, z, "removeResourcePolicies", removed)
+			if err != nil {
+				return err
+			}
+		}
+		if len(added) > 0 {
+			err = updateDiskResourcePolicies(d, config, project, z, "addResourcePolicies", added)
+			if err != nil {
+				return err
+			}
+		}
+		d.SetPartial("resource_policies")
+	}
 	d.Partial(false)
 
 	return resourceComputeDiskRead(d, meta)
@@ -288,13 +388,25 @@ func resourceComputeDiskRead(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	var disk *compute.Disk
-	if zone, _ := getZone(d, config); zone != "" {
+	var rawDisk map[string]interface{}
+	zone, _ := getZone(d, config)
+	switch {
+	case zone != "" && getComputeApiVersion(d, DiskBaseApiVersion, DiskVersionedFeatures) == v0beta:
+		rawDisk, err = Get(config, diskBetaUrl(project, zone, d.Id()))
+		if err != nil {
+			return handleNotFoundError(err, d, fmt.Sprintf("Disk %q", d.Get("name").(string)))
+		}
+		disk = &compute.Disk{}
+		if err := Convert(rawDisk, disk); err != nil {
+			return err
+		}
+	case zone != "":
 		disk, err = config.clientCompute.Disks.Get(
 			project, zone, d.Id()).Do()
 		if err != nil {
 			return handleNotFoundError(err, d, fmt.Sprintf("Disk %q", d.Get("name").(string)))
 		}
-	} else {
+	default:
 		// If the resource was imported, the only info we have is the ID. Try to find the resource
 		// by searching in the region of the project.
 		var resource interface{}
@@ -306,23 +418,50 @@ func resourceComputeDiskRead(d *schema.ResourceData, meta interface{}) error {
 
 		disk = resource.(*compute.Disk)
 	}
+	if rawDisk == nil {
+		rawDisk = map[string]interface{}{
+			"diskEncryptionKey":        rawCustomerEncryptionKey(disk.DiskEncryptionKey),
+			"sourceImageEncryptionKey": rawCustomerEncryptionKey(disk.SourceImageEncryptionKey),
+		}
+	}
 
 	d.Set("name", disk.Name)
-	d.Set("self_link", disk.SelfLink)
+	d.Set("self_link", ConvertSelfLinkToV1(disk.SelfLink))
 	d.Set("type", GetResourceNameFromSelfLink(disk.Type))
 	d.Set("zone", GetResourceNameFromSelfLink(disk.Zone))
 	d.Set("size", disk.SizeGb)
-	d.Set("users", disk.Users)
+	users := make([]string, 0, len(disk.Users))
+	for _, user := range disk.Users {
+		users = append(users, ConvertSelfLinkToV1(user))
+	}
+	d.Set("users", users)
 	if disk.DiskEncryptionKey != nil && disk.DiskEncryptionKey.Sha256 != "" {
 		d.Set("disk_encryption_key_sha256", disk.DiskEncryptionKey.Sha256)
 	}
 
-	d.Set("image", disk.SourceImage)
-	d.Set("snapshot", disk.SourceSnapshot)
-	d.Set("labels", disk.Labels)
+	d.Set("image", ConvertSelfLinkToV1(disk.SourceImage))
+	d.Set("snapshot", ConvertSelfLinkToV1(disk.SourceSnapshot))
+	if err := setEffectiveLabels(d, config, "labels", disk.Labels); err != nil {
+		return err
+	}
 	d.Set("label_fingerprint", disk.LabelFingerprint)
 	d.Set("project", project)
 
+	d.Set("disk_encryption_key", flattenCustomerEncryptionKey(d, "disk_encryption_key", rawDisk["diskEncryptionKey"], config))
+	d.Set("source_image_encryption_key", flattenCustomerEncryptionKey(d, "source_image_encryption_key", rawDisk["sourceImageEncryptionKey"], config))
+	if size, ok := rawDisk["physicalBlockSizeBytes"].(string); ok {
+		if v, err := strconv.Atoi(size); err == nil {
+			d.Set("physical_block_size_bytes", v)
+		}
+	}
+	resourcePolicies := []string{}
+	if policies, ok := rawDisk["resourcePolicies"].([]interface{}); ok {
+		for _, policy := range policies {
+			resourcePolicies = append(resourcePolicies, ConvertSelfLinkToV1(policy.(string)))
+		}
+	}
+	d.Set("resource_policies", resourcePolicies)
+
 	return nil
 }
 
@@ -376,7 +515,7 @@ func resourceComputeDiskDelete(d *schema.ResourceData, meta interface{}) error {
 				return fmt.Errorf("Error detaching disk %s from instance %s/%s/%s: %s", call.deviceName, call.project,
 					call.zone, call.instance, err.Error())
 			}
-			err = computeOperationWait(config.clientCompute, op, call.project,
+			err = computeOperationWaitConfig(config, op, call.project,
 				fmt.Sprintf("Detaching disk from %s/%s/%s", call.project, call.zone, call.instance))
 			if err != nil {
 				if opErr, ok := err.(ComputeOperationError); ok && len(opErr.Errors) == 1 && opErr.Errors[0].Code == "RESOURCE_NOT_FOUND" {
@@ -401,7 +540,7 @@ func resourceComputeDiskDelete(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Error deleting disk: %s", err)
 	}
 
-	err = computeOperationWaitTime(config.clientCompute, op, project, "Deleting Disk", int(d.Timeout(schema.TimeoutDelete).Minutes()))
+	err = computeOperationWaitTimeConfig(config, op, project, "Deleting Disk", int(d.Timeout(schema.TimeoutDelete).Minutes()))
 	if err != nil {
 		return err
 	}
@@ -588,3 +727,167 @@ func suppressWindowsFamilyDiff(imageName, familyName string) bool {
 
 	return false
 }
+
+// customizeDiffDiskSizeDecrease rejects plans that shrink a disk, which the
+// API would only refuse at apply time. With recreate_on_size_decrease set, the
+// disk is replaced instead. A disk that is replaced anyway can take any size.
+func customizeDiffDiskSizeDecrease(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	o, n := d.GetChange("size")
+	if n.(int) == 0 || n.(int) >= o.(int) || diskForceNewFieldChanged(d) {
+		return nil
+	}
+
+	if d.Get("recreate_on_size_decrease").(bool) {
+		return d.ForceNew("size")
+	}
+	return fmt.Errorf("Disk %q can't be shrunk from %d to %d GB. Set recreate_on_size_decrease to replace it with a new, empty disk instead.", d.Id(), o.(int), n.(int))
+}
+
+// customizeDiffDiskUsers makes the instances a disk is attached to part of
+// the plan when the disk is about to be replaced, as replacing it detaches it
+// from all of them. They show up in the plan as being removed from users.
+func customizeDiffDiskUsers(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	users := d.Get("users").([]interface{})
+	if len(users) == 0 || !diskRequiresReplacement(d) {
+		return nil
+	}
+
+	log.Printf("[WARN] Replacing disk %q will detach it from %v", d.Id(), users)
+	return d.SetNew("users", []interface{}{})
+}
+
+func diskRequiresReplacement(d *schema.ResourceDiff) bool {
+	if diskForceNewFieldChanged(d) {
+		return true
+	}
+
+	o, n := d.GetChange("size")
+	return n.(int) != 0 && n.(int) < o.(int) && d.Get("recreate_on_size_decrease").(bool)
+}
+
+// diskForceNewFieldChanged reports whether a ForceNew field of the disk
+// changes, and so replaces it.
+func diskForceNewFieldChanged(d *schema.ResourceDiff) bool {
+	for k, s := range resourceComputeDisk().Schema {
+		if s.ForceNew && fieldChanged(d, k, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldChanged reports whether the field at key with schema s has a diff.
+// Unlike ResourceDiff.HasChange, which compares state with the raw config,
+// it takes DiffSuppressFunc into account, so that e.g. an image given by
+// family doesn't count as a change from the self link read into state.
+// DiffSuppressFuncs are called without a ResourceData, so the ones of the
+// fields it is used on mustn't rely on it.
+func fieldChanged(d *schema.ResourceDiff, key string, s *schema.Schema) bool {
+	if !d.HasChange(key) {
+		return false
+	}
+
+	switch s.Type {
+	case schema.TypeString:
+		if s.DiffSuppressFunc != nil {
+			o, n := d.GetChange(key)
+			return !s.DiffSuppressFunc(key, o.(string), n.(string), nil)
+		}
+	case schema.TypeList:
+		elem, ok := s.Elem.(*schema.Resource)
+		if !ok {
+			return true
+		}
+		o, n := d.GetChange(key)
+		if len(o.([]interface{})) != len(n.([]interface{})) {
+			return true
+		}
+		for i := range n.([]interface{}) {
+			for k, es := range elem.Schema {
+				// Computed-only fields are never in the config, so they'd
+				// always look changed.
+				if es.Computed && !es.Optional {
+					continue
+				}
+				if fieldChanged(d, fmt.Sprintf("%s.%d.%s", key, i, k), es) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func diskBetaUrl(project, zone, name string) string {
+	return fmt.Sprintf("https://www.googleapis.com/compute/beta/projects/%s/zones/%s/disks/%s", project, zone, name)
+}
+
+// updateDiskResourcePolicies attaches or detaches resource policies. method is
+// either addResourcePolicies or removeResourcePolicies.
+func updateDiskResourcePolicies(d *schema.ResourceData, config *Config, project, zone, method string, policies []interface{}) error {
+	links, err := expandDiskResourcePolicies(d, config, policies)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Calling %s on disk %s with %v", method, d.Id(), links)
+	res, err := Post(config, diskBetaUrl(project, zone, d.Id())+"/"+method, map[string]interface{}{
+		"resourcePolicies": links,
+	})
+	if err != nil {
+		return fmt.Errorf("Error updating resource policies of disk %s: %s", d.Id(), err)
+	}
+
+	op := &compute.Operation{}
+	if err := Convert(res, op); err != nil {
+		return err
+	}
+
+	return computeOperationWaitTimeConfig(config, op, project, "Updating resource policies of disk", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+}
+
+func expandDiskResourcePolicies(d *schema.ResourceData, config *Config, policies []interface{}) ([]string, error) {
+	links := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		f, err := ParseResourcePolicyFieldValue(policy.(string), d, config)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value for resource_policies: %s", err)
+		}
+		links = append(links, f.RelativeLink())
+	}
+	return links, nil
+}
+
+// diffResourcePolicies returns the policies in o that aren't in n and the
+// policies in n that aren't in o, comparing them by name.
+func diffResourcePolicies(o, n []interface{}) (removed, added []interface{}) {
+	names := func(policies []interface{}) map[string]bool {
+		m := make(map[string]bool)
+		for _, policy := range policies {
+			m[GetResourceNameFromSelfLink(policy.(string))] = true
+		}
+		return m
+	}
+
+	oldNames, newNames := names(o), names(n)
+	for _, policy := range o {
+		if !newNames[GetResourceNameFromSelfLink(policy.(string))] {
+			removed = append(removed, policy)
+		}
+	}
+	for _, policy := range n {
+		if !oldNames[GetResourceNameFromSelfLink(policy.(string))] {
+			added = append(added, policy)
+		}
+	}
+	return removed, added
+}
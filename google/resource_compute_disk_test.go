@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
@@ -171,6 +172,95 @@ func TestDiskImageDiffSuppress(t *testing.T) {
 	}
 }
 
+func TestComputeDiskDiff_users(t *testing.T) {
+	t.Parallel()
+
+	state := &terraform.InstanceState{
+		ID: "disk-1",
+		Attributes: map[string]string{
+			"name":                      "disk-1",
+			"zone":                      "us-central1-a",
+			"image":                     "https://www.googleapis.com/compute/v1/projects/debian-cloud/global/images/debian-9-stretch-v20180105",
+			"type":                      "pd-standard",
+			"size":                      "10",
+			"recreate_on_size_decrease": "false",
+			"users.#":                   "1",
+			"users.0":                   "https://www.googleapis.com/compute/v1/projects/p/zones/us-central1-a/instances/instance-1",
+			"labels.%":                  "0",
+			"effective_labels.%":        "0",
+		},
+	}
+
+	cases := map[string]struct {
+		Config          map[string]interface{}
+		ExpectReplace   bool
+		ExpectUsersDiff bool
+		ExpectError     bool
+	}{
+		"image family matching the state": {
+			Config: map[string]interface{}{
+				"name":  "disk-1",
+				"image": "debian-cloud/debian-9",
+			},
+		},
+		"different image": {
+			Config: map[string]interface{}{
+				"name":  "disk-1",
+				"image": "debian-cloud/debian-8",
+			},
+			ExpectReplace:   true,
+			ExpectUsersDiff: true,
+		},
+		"shrinking a disk that is replaced anyway": {
+			Config: map[string]interface{}{
+				"name":  "disk-1",
+				"image": "debian-cloud/debian-8",
+				"size":  5,
+			},
+			ExpectReplace:   true,
+			ExpectUsersDiff: true,
+		},
+		"shrinking a disk": {
+			Config: map[string]interface{}{
+				"name":  "disk-1",
+				"image": "debian-cloud/debian-9",
+				"size":  5,
+			},
+			ExpectError: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		rc, err := config.NewRawConfig(tc.Config)
+		if err != nil {
+			t.Fatalf("%s: bad config: %s", tn, err)
+		}
+		diff, err := resourceComputeDisk().Diff(state, terraform.NewResourceConfig(rc), &Config{})
+		if tc.ExpectError {
+			if err == nil {
+				t.Errorf("%s: expected an error", tn)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tn, err)
+			continue
+		}
+
+		replace := diff != nil && diff.RequiresNew()
+		if replace != tc.ExpectReplace {
+			t.Errorf("%s: expected replacement to be %t, got %t", tn, tc.ExpectReplace, replace)
+		}
+		usersDiff := false
+		if diff != nil {
+			_, usersDiff = diff.GetAttribute("users.0")
+		}
+		if usersDiff != tc.ExpectUsersDiff {
+			t.Errorf("%s: expected a diff for users to be %t, got %t", tn, tc.ExpectUsersDiff, usersDiff)
+		}
+	}
+}
+
 // Test that all the naming pattern for public images are supported.
 func TestAccComputeDisk_imageDiffSuppressPublicVendorsFamilyNames(t *testing.T) {
 	t.Parallel()
@@ -279,6 +369,115 @@ func TestAccComputeDisk_update(t *testing.T) {
 	})
 }
 
+func TestAccComputeDisk_sizeDecrease(t *testing.T) {
+	t.Parallel()
+
+	diskName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	var disk compute.Disk
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeDiskDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeDisk_size(diskName, 50, false),
+				Check: testAccCheckComputeDiskExists(
+					"google_compute_disk.foobar", &disk),
+			},
+			{
+				Config:      testAccComputeDisk_size(diskName, 20, false),
+				ExpectError: regexp.MustCompile("can't be shrunk from 50 to 20 GB"),
+			},
+			{
+				Config: testAccComputeDisk_size(diskName, 20, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeDiskExists(
+						"google_compute_disk.foobar", &disk),
+					resource.TestCheckResourceAttr("google_compute_disk.foobar", "size", "20"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccComputeDisk_physicalBlockSize(t *testing.T) {
+	t.Parallel()
+
+	diskName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	var disk compute.Disk
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeDiskDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeDisk_physicalBlockSize(diskName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeDiskExists(
+						"google_compute_disk.foobar", &disk),
+					resource.TestCheckResourceAttr("google_compute_disk.foobar", "physical_block_size_bytes", "16384"),
+				),
+			},
+			{
+				ResourceName:      "google_compute_disk.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Beta fields are only read once they are in the config.
+				ImportStateVerifyIgnore: []string{"physical_block_size_bytes"},
+			},
+		},
+	})
+}
+
+func TestAccComputeDisk_resourcePolicies(t *testing.T) {
+	t.Parallel()
+
+	diskName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	policyName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	var disk compute.Disk
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeDiskDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeDisk_resourcePolicies(diskName, policyName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeDiskExists(
+						"google_compute_disk.foobar", &disk),
+					resource.TestCheckResourceAttr("google_compute_disk.foobar", "resource_policies.#", "0"),
+				),
+			},
+			{
+				Config: testAccComputeDisk_resourcePolicies(diskName, policyName, "${google_compute_resource_policy.foobar.self_link}"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeDiskExists(
+						"google_compute_disk.foobar", &disk),
+					resource.TestCheckResourceAttr("google_compute_disk.foobar", "resource_policies.#", "1"),
+				),
+			},
+			{
+				ResourceName:      "google_compute_disk.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Beta fields are only read once they are in the config.
+				ImportStateVerifyIgnore: []string{"resource_policies"},
+			},
+			{
+				Config: testAccComputeDisk_resourcePolicies(diskName, policyName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeDiskExists(
+						"google_compute_disk.foobar", &disk),
+					resource.TestCheckResourceAttr("google_compute_disk.foobar", "resource_policies.#", "0"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccComputeDisk_fromSnapshot(t *testing.T) {
 	t.Parallel()
 
@@ -336,6 +535,39 @@ func TestAccComputeDisk_encryption(t *testing.T) {
 	})
 }
 
+func TestAccComputeDisk_encryptionKms(t *testing.T) {
+	t.Parallel()
+
+	diskName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	keyRingName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	cryptoKeyName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	var disk compute.Disk
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeDiskDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeDisk_encryptionKms(keyRingName, cryptoKeyName, diskName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeDiskExists(
+						"google_compute_disk.foobar", &disk),
+					resource.TestCheckResourceAttrPair("google_compute_disk.foobar", "disk_encryption_key.0.kms_key_self_link",
+						"google_kms_crypto_key.foobar", "id"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_disk.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Beta fields are only read once they are in the config.
+				ImportStateVerifyIgnore: []string{"disk_encryption_key"},
+			},
+		},
+	})
+}
+
 func TestAccComputeDisk_deleteDetach(t *testing.T) {
 	t.Parallel()
 
@@ -662,6 +894,23 @@ resource "google_compute_disk" "foobar" {
 }`, diskName)
 }
 
+func testAccComputeDisk_encryptionKms(keyRingName, cryptoKeyName, diskName string) string {
+	return testAccComputeKmsKey(keyRingName, cryptoKeyName) + fmt.Sprintf(`
+resource "google_compute_disk" "foobar" {
+	name = "%s"
+	image = "debian-8-jessie-v20160803"
+	size = 50
+	type = "pd-ssd"
+	zone = "us-central1-a"
+
+	disk_encryption_key {
+		kms_key_self_link = "${google_kms_crypto_key.foobar.id}"
+	}
+
+	depends_on = ["google_kms_crypto_key_iam_member.foobar"]
+}`, diskName)
+}
+
 func testAccComputeDisk_deleteDetach(instanceName, diskName string) string {
 	return fmt.Sprintf(`
 resource "google_compute_disk" "foo" {
@@ -729,3 +978,55 @@ resource "google_compute_instance_group_manager" "manager" {
   target_size        = 1
 }`, diskName, mgrName)
 }
+
+func testAccComputeDisk_resourcePolicies(diskName, policyName, policy string) string {
+	var policies string
+	if policy != "" {
+		policies = fmt.Sprintf("resource_policies = [\"%s\"]", policy)
+	}
+
+	return fmt.Sprintf(`
+resource "google_compute_resource_policy" "foobar" {
+	name = "%s"
+	region = "us-central1"
+	snapshot_schedule_policy {
+		schedule {
+			daily_schedule {
+				days_in_cycle = 1
+				start_time = "04:00"
+			}
+		}
+	}
+}
+
+resource "google_compute_disk" "foobar" {
+	name = "%s"
+	image = "debian-8-jessie-v20160803"
+	size = 50
+	type = "pd-ssd"
+	zone = "us-central1-a"
+	%s
+}`, policyName, diskName, policies)
+}
+
+func testAccComputeDisk_size(diskName string, size int, recreate bool) string {
+	return fmt.Sprintf(`
+resource "google_compute_disk" "foobar" {
+	name = "%s"
+	size = %d
+	type = "pd-standard"
+	zone = "us-central1-a"
+	recreate_on_size_decrease = %t
+}`, diskName, size, recreate)
+}
+
+func testAccComputeDisk_physicalBlockSize(diskName string) string {
+	return fmt.Sprintf(`
+resource "google_compute_disk" "foobar" {
+	name = "%s"
+	size = 10
+	type = "pd-ssd"
+	zone = "us-central1-a"
+	physical_block_size_bytes = 16384
+}`, diskName)
+}
@@ -14,7 +14,7 @@ import (
 )
 
 var FirewallBaseApiVersion = v1
-var FirewallVersionedFeatures = []Feature{}
+var FirewallVersionedFeatures = []Feature{{Version: v0beta, Item: "disabled", DefaultValue: false}}
 
 func resourceComputeFirewall() *schema.Resource {
 	return &schema.Resource{
@@ -169,10 +169,26 @@ func resourceComputeFirewall() *schema.Resource {
 				ForceNew:      true,
 				ConflictsWith: []string{"source_tags", "target_tags"},
 			},
+
+			"disabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"enable_logging": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 		},
 	}
 }
 
+func firewallBetaUrl(project, name string) string {
+	return fmt.Sprintf("https://www.googleapis.com/compute/beta/projects/%s/global/firewalls/%s", project, name)
+}
+
 func resourceComputeFirewallRuleHash(v interface{}) int {
 	var buf bytes.Buffer
 	m := v.(map[string]interface{})
@@ -207,8 +223,14 @@ func resourceComputeFirewallCreate(d *schema.ResourceData, meta interface{}) err
 	}
 
 	var op interface{}
-	switch computeApiVersion {
-	case v1:
+	switch {
+	case d.Get("enable_logging").(bool):
+		url := fmt.Sprintf("https://www.googleapis.com/compute/beta/projects/%s/global/firewalls", project)
+		op, err = postWithRawFields(config, url, firewall, map[string]interface{}{"enableLogging": true})
+		if err != nil {
+			return fmt.Errorf("Error creating firewall: %s", err)
+		}
+	case computeApiVersion == v1:
 		firewallV1 := &compute.Firewall{}
 		err = Convert(firewall, firewallV1)
 		if err != nil {
@@ -219,7 +241,7 @@ func resourceComputeFirewallCreate(d *schema.ResourceData, meta interface{}) err
 		if err != nil {
 			return fmt.Errorf("Error creating firewall: %s", err)
 		}
-	case v0beta:
+	case computeApiVersion == v0beta:
 		firewallV0Beta := &computeBeta.Firewall{}
 		err = Convert(firewall, firewallV0Beta)
 		if err != nil {
@@ -235,7 +257,7 @@ func resourceComputeFirewallCreate(d *schema.ResourceData, meta interface{}) err
 	// It probably maybe worked, so store the ID now
 	d.SetId(firewall.Name)
 
-	err = computeSharedOperationWait(config.clientCompute, op, project, "Creating Firewall")
+	err = computeSharedOperationWaitConfig(config, op, project, "Creating Firewall")
 	if err != nil {
 		return err
 	}
@@ -277,8 +299,19 @@ func resourceComputeFirewallRead(d *schema.ResourceData, meta interface{}) error
 	}
 
 	firewall := &computeBeta.Firewall{}
-	switch computeApiVersion {
-	case v1:
+	var rawFirewall map[string]interface{}
+	switch {
+	case d.Get("enable_logging").(bool):
+		rawFirewall, err = Get(config, firewallBetaUrl(project, d.Id()))
+		if err != nil {
+			return handleNotFoundError(err, d, fmt.Sprintf("Firewall %q", d.Get("name").(string)))
+		}
+
+		err = Convert(rawFirewall, firewall)
+		if err != nil {
+			return err
+		}
+	case computeApiVersion == v1:
 		firewallV1, err := config.clientCompute.Firewalls.Get(project, d.Id()).Do()
 		if err != nil {
 			return handleNotFoundError(err, d, fmt.Sprintf("Firewall %q", d.Get("name").(string)))
@@ -288,7 +321,7 @@ func resourceComputeFirewallRead(d *schema.ResourceData, meta interface{}) error
 		if err != nil {
 			return err
 		}
-	case v0beta:
+	case computeApiVersion == v0beta:
 		firewallV0Beta, err := config.clientComputeBeta.Firewalls.Get(project, d.Id()).Do()
 		if err != nil {
 			return handleNotFoundError(err, d, fmt.Sprintf("Firewall %q", d.Get("name").(string)))
@@ -315,6 +348,8 @@ func resourceComputeFirewallRead(d *schema.ResourceData, meta interface{}) error
 	d.Set("priority", int(firewall.Priority))
 	d.Set("source_service_accounts", firewall.SourceServiceAccounts)
 	d.Set("target_service_accounts", firewall.TargetServiceAccounts)
+	d.Set("disabled", firewall.Disabled)
+	d.Set("enable_logging", rawFirewall["enableLogging"] == true)
 	return nil
 }
 
@@ -329,40 +364,67 @@ func resourceComputeFirewallUpdate(d *schema.ResourceData, meta interface{}) err
 
 	d.Partial(true)
 
-	firewall, err := resourceFirewall(d, meta)
-	if err != nil {
-		return err
-	}
-
-	var op interface{}
-	switch computeApiVersion {
-	case v1:
-		firewallV1 := &compute.Firewall{}
-		err = Convert(firewall, firewallV1)
+	updated := false
+	if d.HasChange("allow") || d.HasChange("description") || d.HasChange("source_ranges") ||
+		d.HasChange("source_tags") || d.HasChange("target_tags") || d.HasChange("disabled") {
+		firewall, err := resourceFirewall(d, meta)
 		if err != nil {
 			return err
 		}
 
-		op, err = config.clientCompute.Firewalls.Update(project, d.Id(), firewallV1).Do()
+		var op interface{}
+		switch computeApiVersion {
+		case v1:
+			firewallV1 := &compute.Firewall{}
+			err = Convert(firewall, firewallV1)
+			if err != nil {
+				return err
+			}
+
+			op, err = config.clientCompute.Firewalls.Update(project, d.Id(), firewallV1).Do()
+			if err != nil {
+				return fmt.Errorf("Error updating firewall: %s", err)
+			}
+		case v0beta:
+			firewallV0Beta := &computeBeta.Firewall{}
+			err = Convert(firewall, firewallV0Beta)
+			if err != nil {
+				return err
+			}
+
+			op, err = config.clientComputeBeta.Firewalls.Update(project, d.Id(), firewallV0Beta).Do()
+			if err != nil {
+				return fmt.Errorf("Error updating firewall: %s", err)
+			}
+		}
+
+		err = computeSharedOperationWaitConfig(config, op, project, "Updating Firewall")
 		if err != nil {
-			return fmt.Errorf("Error updating firewall: %s", err)
+			return err
 		}
-	case v0beta:
-		firewallV0Beta := &computeBeta.Firewall{}
-		err = Convert(firewall, firewallV0Beta)
+		updated = true
+	}
+
+	// A full update resets enable_logging, so it's patched back after one.
+	enableLogging := d.Get("enable_logging").(bool)
+	if d.HasChange("enable_logging") || updated && enableLogging {
+		body := map[string]interface{}{"enableLogging": enableLogging}
+		res, err := Patch(config, firewallBetaUrl(project, d.Id()), body, []string{"enableLogging"}, nil)
 		if err != nil {
+			return fmt.Errorf("Error updating firewall %q: %s", d.Id(), err)
+		}
+
+		op := &compute.Operation{}
+		if err := Convert(res, op); err != nil {
 			return err
 		}
 
-		op, err = config.clientComputeBeta.Firewalls.Update(project, d.Id(), firewallV0Beta).Do()
+		err = computeSharedOperationWaitConfig(config, op, project, "Updating Firewall")
 		if err != nil {
-			return fmt.Errorf("Error updating firewall: %s", err)
+			return err
 		}
-	}
 
-	err = computeSharedOperationWait(config.clientCompute, op, project, "Updating Firewall")
-	if err != nil {
-		return err
+		d.SetPartial("enable_logging")
 	}
 
 	d.Partial(false)
@@ -394,7 +456,7 @@ func resourceComputeFirewallDelete(d *schema.ResourceData, meta interface{}) err
 		}
 	}
 
-	err = computeSharedOperationWait(config.clientCompute, op, project, "Deleting Firewall")
+	err = computeSharedOperationWaitConfig(config, op, project, "Deleting Firewall")
 	if err != nil {
 		return err
 	}
@@ -487,5 +549,6 @@ func resourceFirewall(d *schema.ResourceData, meta interface{}) (*computeBeta.Fi
 		Priority:              int64(d.Get("priority").(int)),
 		SourceServiceAccounts: convertStringSet(d.Get("source_service_accounts").(*schema.Set)),
 		TargetServiceAccounts: convertStringSet(d.Get("target_service_accounts").(*schema.Set)),
+		Disabled:              d.Get("disabled").(bool),
 	}, nil
 }
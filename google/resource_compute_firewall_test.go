@@ -107,6 +107,63 @@ func TestAccComputeFirewall_priority(t *testing.T) {
 	})
 }
 
+func TestAccComputeFirewall_disabledAndLogging(t *testing.T) {
+	t.Parallel()
+
+	var firewall compute.Firewall
+	networkName := fmt.Sprintf("firewall-test-%s", acctest.RandString(10))
+	firewallName := fmt.Sprintf("firewall-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeFirewallDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeFirewall_disabledAndLogging(networkName, firewallName, "Disabled for incident response", true, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeFirewallExists(
+						"google_compute_firewall.foobar", &firewall),
+					resource.TestCheckResourceAttr("google_compute_firewall.foobar", "disabled", "true"),
+					resource.TestCheckResourceAttr("google_compute_firewall.foobar", "enable_logging", "true"),
+				),
+			},
+			{
+				ResourceName:      "google_compute_firewall.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Beta fields are only read once they are in the config.
+				ImportStateVerifyIgnore: []string{"disabled", "enable_logging"},
+			},
+			{
+				// Updating other fields too shouldn't reset the flags.
+				Config: testAccComputeFirewall_disabledAndLogging(networkName, firewallName, "Logged", false, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeFirewallExists(
+						"google_compute_firewall.foobar", &firewall),
+					resource.TestCheckResourceAttr("google_compute_firewall.foobar", "disabled", "false"),
+					resource.TestCheckResourceAttr("google_compute_firewall.foobar", "enable_logging", "true"),
+				),
+			},
+			{
+				ResourceName:      "google_compute_firewall.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Beta fields are only read once they are in the config.
+				ImportStateVerifyIgnore: []string{"disabled", "enable_logging"},
+			},
+			{
+				Config: testAccComputeFirewall_disabledAndLogging(networkName, firewallName, "Logged", false, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeFirewallExists(
+						"google_compute_firewall.foobar", &firewall),
+					resource.TestCheckResourceAttr("google_compute_firewall.foobar", "enable_logging", "false"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccComputeFirewall_noSource(t *testing.T) {
 	t.Parallel()
 
@@ -452,6 +509,29 @@ func testAccComputeFirewall_priority(network, firewall string, priority int) str
 	}`, network, firewall, priority)
 }
 
+func testAccComputeFirewall_disabledAndLogging(network, firewall, description string, disabled, enableLogging bool) string {
+	return fmt.Sprintf(`
+	resource "google_compute_network" "foobar" {
+		name = "%s"
+		auto_create_subnetworks = false
+		ipv4_range = "10.0.0.0/16"
+	}
+
+	resource "google_compute_firewall" "foobar" {
+		name = "firewall-test-%s"
+		description = "%s"
+		network = "${google_compute_network.foobar.name}"
+		source_tags = ["foo"]
+
+		allow {
+			protocol = "icmp"
+		}
+
+		disabled = %t
+		enable_logging = %t
+	}`, network, firewall, description, disabled, enableLogging)
+}
+
 func testAccComputeFirewall_noSource(network, firewall string) string {
 	return fmt.Sprintf(`
 	resource "google_compute_network" "foobar" {
@@ -169,7 +169,7 @@ func resourceComputeForwardingRuleCreate(d *schema.ResourceData, meta interface{
 	// It probably maybe worked, so store the ID now
 	d.SetId(frule.Name)
 
-	err = computeOperationWait(config.clientCompute, op, project, "Creating Fowarding Rule")
+	err = computeOperationWaitConfig(config, op, project, "Creating Fowarding Rule")
 	if err != nil {
 		return err
 	}
@@ -201,7 +201,7 @@ func resourceComputeForwardingRuleUpdate(d *schema.ResourceData, meta interface{
 			return fmt.Errorf("Error updating target: %s", err)
 		}
 
-		err = computeOperationWait(config.clientCompute, op, project, "Updating Forwarding Rule")
+		err = computeOperationWaitConfig(config, op, project, "Updating Forwarding Rule")
 		if err != nil {
 			return err
 		}
@@ -271,7 +271,7 @@ func resourceComputeForwardingRuleDelete(d *schema.ResourceData, meta interface{
 		return fmt.Errorf("Error deleting ForwardingRule: %s", err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Deleting Forwarding Rule")
+	err = computeOperationWaitConfig(config, op, project, "Deleting Forwarding Rule")
 	if err != nil {
 		return err
 	}
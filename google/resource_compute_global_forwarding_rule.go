@@ -14,6 +14,7 @@ import (
 var GlobalForwardingRuleBaseApiVersion = v1
 var GlobalForwardingRuleVersionedFeatures = []Feature{
 	{Version: v0beta, Item: "labels"},
+	{Version: v0beta, Item: "effective_labels"},
 }
 
 func resourceComputeGlobalForwardingRule() *schema.Resource {
@@ -27,6 +28,8 @@ func resourceComputeGlobalForwardingRule() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		CustomizeDiff: customizeDiffEffectiveLabels("labels"),
+
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -67,6 +70,8 @@ func resourceComputeGlobalForwardingRule() *schema.Resource {
 				Set:      schema.HashString,
 			},
 
+			"effective_labels": effectiveLabelsSchema(),
+
 			"label_fingerprint": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
@@ -156,14 +161,13 @@ func resourceComputeGlobalForwardingRuleCreate(d *schema.ResourceData, meta inte
 	// It probably maybe worked, so store the ID now
 	d.SetId(frule.Name)
 
-	err = computeSharedOperationWait(config.clientCompute, op, project, "Creating Global Fowarding Rule")
+	err = computeSharedOperationWaitConfig(config, op, project, "Creating Global Fowarding Rule")
 	if err != nil {
 		return err
 	}
 
 	// If we have labels to set, try to set those too
-	if _, ok := d.GetOk("labels"); ok {
-		labels := expandLabels(d)
+	if labels := expandEffectiveLabels(d, config, "labels"); len(labels) > 0 {
 		// Do a read to get the fingerprint value so we can update
 		fingerprint, err := resourceComputeGlobalForwardingRuleReadLabelFingerprint(config, computeApiVersion, project, frule.Name)
 		if err != nil {
@@ -222,15 +226,15 @@ func resourceComputeGlobalForwardingRuleUpdate(d *schema.ResourceData, meta inte
 			}
 		}
 
-		err = computeSharedOperationWait(config.clientCompute, op, project, "Updating Global Forwarding Rule")
+		err = computeSharedOperationWaitConfig(config, op, project, "Updating Global Forwarding Rule")
 		if err != nil {
 			return err
 		}
 
 		d.SetPartial("target")
 	}
-	if d.HasChange("labels") {
-		labels := expandLabels(d)
+	if effectiveLabelsHaveChanged(d, "labels") {
+		labels := expandEffectiveLabels(d, config, "labels")
 		fingerprint := d.Get("label_fingerprint").(string)
 
 		err = resourceComputeGlobalForwardingRuleSetLabels(config, computeApiVersion, project, d.Get("name").(string), labels, fingerprint)
@@ -239,6 +243,7 @@ func resourceComputeGlobalForwardingRuleUpdate(d *schema.ResourceData, meta inte
 		}
 
 		d.SetPartial("labels")
+		d.SetPartial("effective_labels")
 	}
 
 	d.Partial(false)
@@ -287,7 +292,9 @@ func resourceComputeGlobalForwardingRuleRead(d *schema.ResourceData, meta interf
 	d.Set("ip_protocol", frule.IPProtocol)
 	d.Set("ip_version", frule.IpVersion)
 	d.Set("self_link", ConvertSelfLinkToV1(frule.SelfLink))
-	d.Set("labels", frule.Labels)
+	if err := setEffectiveLabels(d, config, "labels", frule.Labels); err != nil {
+		return err
+	}
 	d.Set("label_fingerprint", frule.LabelFingerprint)
 	d.Set("project", project)
 
@@ -319,7 +326,7 @@ func resourceComputeGlobalForwardingRuleDelete(d *schema.ResourceData, meta inte
 		}
 	}
 
-	err = computeSharedOperationWait(config.clientCompute, op, project, "Deleting GlobalForwarding Rule")
+	err = computeSharedOperationWaitConfig(config, op, project, "Deleting GlobalForwarding Rule")
 	if err != nil {
 		return err
 	}
@@ -369,7 +376,7 @@ func resourceComputeGlobalForwardingRuleSetLabels(config *Config, computeApiVers
 			computeApiVersion)
 	}
 
-	err = computeSharedOperationWait(config.clientCompute, op, project, "Setting labels on Global Forwarding Rule")
+	err = computeSharedOperationWaitConfig(config, op, project, "Setting labels on Global Forwarding Rule")
 	if err != nil {
 		return err
 	}
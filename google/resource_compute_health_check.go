@@ -302,7 +302,7 @@ func resourceComputeHealthCheckCreate(d *schema.ResourceData, meta interface{})
 	// It probably maybe worked, so store the ID now
 	d.SetId(hchk.Name)
 
-	err = computeOperationWait(config.clientCompute, op, project, "Creating Health Check")
+	err = computeOperationWaitConfig(config, op, project, "Creating Health Check")
 	if err != nil {
 		return err
 	}
@@ -435,7 +435,7 @@ func resourceComputeHealthCheckUpdate(d *schema.ResourceData, meta interface{})
 	// It probably maybe worked, so store the ID now
 	d.SetId(hchk.Name)
 
-	err = computeOperationWait(config.clientCompute, op, project, "Updating Health Check")
+	err = computeOperationWaitConfig(config, op, project, "Updating Health Check")
 	if err != nil {
 		return err
 	}
@@ -488,7 +488,7 @@ func resourceComputeHealthCheckDelete(d *schema.ResourceData, meta interface{})
 		return fmt.Errorf("Error deleting HealthCheck: %s", err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Deleting Health Check")
+	err = computeOperationWaitConfig(config, op, project, "Deleting Health Check")
 	if err != nil {
 		return err
 	}
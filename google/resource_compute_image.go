@@ -11,6 +11,12 @@ import (
 
 const computeImageCreateTimeoutDefault = 4
 
+var ImageBaseApiVersion = v1
+var ImageVersionedFeatures = []Feature{
+	Feature{Version: v0beta, Item: "image_encryption_key.0.kms_key_self_link"},
+	Feature{Version: v0beta, Item: "source_disk_encryption_key.0.kms_key_self_link"},
+}
+
 func resourceComputeImage() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceComputeImageCreate,
@@ -21,6 +27,8 @@ func resourceComputeImage() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		CustomizeDiff: customizeDiffEffectiveLabels("labels"),
+
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(computeImageCreateTimeoutDefault * time.Minute),
 			Update: schema.DefaultTimeout(computeImageCreateTimeoutDefault * time.Minute),
@@ -61,6 +69,8 @@ func resourceComputeImage() *schema.Resource {
 				ForceNew: true,
 			},
 
+			"source_disk_encryption_key": customerEncryptionKeySchema(),
+
 			"raw_disk": &schema.Schema{
 				Type:     schema.TypeList,
 				Optional: true,
@@ -88,6 +98,8 @@ func resourceComputeImage() *schema.Resource {
 				},
 			},
 
+			"image_encryption_key": customerEncryptionKeySchema(),
+
 			"self_link": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
@@ -106,6 +118,8 @@ func resourceComputeImage() *schema.Resource {
 				Set:      schema.HashString,
 			},
 
+			"effective_labels": effectiveLabelsSchema(),
+
 			"label_fingerprint": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
@@ -154,9 +168,7 @@ func resourceComputeImageCreate(d *schema.ResourceData, meta interface{}) error
 		image.RawDisk = imageRawDisk
 	}
 
-	if _, ok := d.GetOk("labels"); ok {
-		image.Labels = expandLabels(d)
-	}
+	image.Labels = expandEffectiveLabels(d, config, "labels")
 
 	// Read create timeout
 	var createTimeout int
@@ -166,9 +178,31 @@ func resourceComputeImageCreate(d *schema.ResourceData, meta interface{}) error
 		createTimeout = int(d.Timeout(schema.TimeoutCreate).Minutes())
 	}
 
+	rawFields := make(map[string]interface{})
+	imageEncryptionKey, err := expandCustomerEncryptionKey(d, "image_encryption_key", config)
+	if err != nil {
+		return err
+	}
+	if imageEncryptionKey != nil {
+		rawFields["imageEncryptionKey"] = imageEncryptionKey
+	}
+	sourceDiskEncryptionKey, err := expandCustomerEncryptionKey(d, "source_disk_encryption_key", config)
+	if err != nil {
+		return err
+	}
+	if sourceDiskEncryptionKey != nil {
+		rawFields["sourceDiskEncryptionKey"] = sourceDiskEncryptionKey
+	}
+
 	// Insert the image
-	op, err := config.clientCompute.Images.Insert(
-		project, image).Do()
+	var op *compute.Operation
+	if len(rawFields) > 0 {
+		url := fmt.Sprintf("https://www.googleapis.com/compute/beta/projects/%s/global/images", project)
+		op, err = postWithRawFields(config, url, image, rawFields)
+	} else {
+		op, err = config.clientCompute.Images.Insert(
+			project, image).Do()
+	}
 	if err != nil {
 		return fmt.Errorf("Error creating image: %s", err)
 	}
@@ -176,7 +210,7 @@ func resourceComputeImageCreate(d *schema.ResourceData, meta interface{}) error
 	// Store the ID
 	d.SetId(image.Name)
 
-	err = computeOperationWaitTime(config.clientCompute, op, project, "Creating Image", createTimeout)
+	err = computeOperationWaitTimeConfig(config, op, project, "Creating Image", createTimeout)
 	if err != nil {
 		return err
 	}
@@ -192,14 +226,31 @@ func resourceComputeImageRead(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
-	image, err := config.clientCompute.Images.Get(
-		project, d.Id()).Do()
-	if err != nil {
-		return handleNotFoundError(err, d, fmt.Sprintf("Image %q", d.Get("name").(string)))
+	image := &compute.Image{}
+	var rawImage map[string]interface{}
+	switch getComputeApiVersion(d, ImageBaseApiVersion, ImageVersionedFeatures) {
+	case v1:
+		image, err = config.clientCompute.Images.Get(
+			project, d.Id()).Do()
+		if err != nil {
+			return handleNotFoundError(err, d, fmt.Sprintf("Image %q", d.Get("name").(string)))
+		}
+		rawImage = map[string]interface{}{
+			"imageEncryptionKey":      rawCustomerEncryptionKey(image.ImageEncryptionKey),
+			"sourceDiskEncryptionKey": rawCustomerEncryptionKey(image.SourceDiskEncryptionKey),
+		}
+	case v0beta:
+		rawImage, err = Get(config, imageBetaUrl(project, d.Id()))
+		if err != nil {
+			return handleNotFoundError(err, d, fmt.Sprintf("Image %q", d.Get("name").(string)))
+		}
+		if err := Convert(rawImage, image); err != nil {
+			return err
+		}
 	}
 
 	if image.SourceDisk != "" {
-		d.Set("source_disk", image.SourceDisk)
+		d.Set("source_disk", ConvertSelfLinkToV1(image.SourceDisk))
 	} else if image.RawDisk != nil {
 		// `raw_disk.*.source` is only used at image creation but is not returned when calling Get.
 		// `raw_disk.*.sha1` is not supported, the value is simply discarded by the server.
@@ -211,11 +262,16 @@ func resourceComputeImageRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("name", image.Name)
 	d.Set("description", image.Description)
 	d.Set("family", image.Family)
-	d.Set("self_link", image.SelfLink)
-	d.Set("labels", image.Labels)
+	d.Set("self_link", ConvertSelfLinkToV1(image.SelfLink))
+	if err := setEffectiveLabels(d, config, "labels", image.Labels); err != nil {
+		return err
+	}
 	d.Set("label_fingerprint", image.LabelFingerprint)
 	d.Set("project", project)
 
+	d.Set("image_encryption_key", flattenCustomerEncryptionKey(d, "image_encryption_key", rawImage["imageEncryptionKey"], config))
+	d.Set("source_disk_encryption_key", flattenCustomerEncryptionKey(d, "source_disk_encryption_key", rawImage["sourceDiskEncryptionKey"], config))
+
 	return nil
 }
 
@@ -230,8 +286,8 @@ func resourceComputeImageUpdate(d *schema.ResourceData, meta interface{}) error
 	// Technically we are only updating one attribute, but setting d.Partial here makes it easier to add updates later
 	d.Partial(true)
 
-	if d.HasChange("labels") {
-		labels := expandLabels(d)
+	if effectiveLabelsHaveChanged(d, "labels") {
+		labels := expandEffectiveLabels(d, config, "labels")
 		labelFingerprint := d.Get("label_fingerprint").(string)
 		setLabelsRequest := compute.GlobalSetLabelsRequest{
 			LabelFingerprint: labelFingerprint,
@@ -245,8 +301,10 @@ func resourceComputeImageUpdate(d *schema.ResourceData, meta interface{}) error
 		}
 
 		d.SetPartial("labels")
+		d.Set("effective_labels", labels)
+		d.SetPartial("effective_labels")
 
-		err = computeOperationWaitTime(config.clientCompute, op, project, "Setting labels", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+		err = computeOperationWaitTimeConfig(config, op, project, "Setting labels", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
 		if err != nil {
 			return err
 		}
@@ -263,6 +321,10 @@ func resourceComputeImageUpdate(d *schema.ResourceData, meta interface{}) error
 	return nil
 }
 
+func imageBetaUrl(project, name string) string {
+	return fmt.Sprintf("https://www.googleapis.com/compute/beta/projects/%s/global/images/%s", project, name)
+}
+
 func resourceComputeImageDelete(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 
@@ -279,7 +341,7 @@ func resourceComputeImageDelete(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("Error deleting image: %s", err)
 	}
 
-	err = computeOperationWaitTime(config.clientCompute, op, project, "Deleting image", int(d.Timeout(schema.TimeoutDelete).Minutes()))
+	err = computeOperationWaitTimeConfig(config, op, project, "Deleting image", int(d.Timeout(schema.TimeoutDelete).Minutes()))
 	if err != nil {
 		return err
 	}
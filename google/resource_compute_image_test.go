@@ -106,6 +106,41 @@ func TestAccComputeImage_basedondisk(t *testing.T) {
 	})
 }
 
+func TestAccComputeImage_encryption(t *testing.T) {
+	t.Parallel()
+
+	var image compute.Image
+
+	keyRingName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	cryptoKeyName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeImageDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeImage_encryption(keyRingName, cryptoKeyName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeImageExists(
+						"google_compute_image.foobar", &image),
+					testAccCheckComputeImageHasSourceDisk(&image),
+					resource.TestCheckResourceAttrPair("google_compute_image.foobar", "image_encryption_key.0.kms_key_self_link",
+						"google_kms_crypto_key.foobar", "id"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_image.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Raw keys aren't returned by the API, and Cloud KMS keys are
+				// only read once they are in the config.
+				ImportStateVerifyIgnore: []string{"source_disk_encryption_key", "image_encryption_key"},
+			},
+		},
+	})
+}
+
 func testAccCheckComputeImageDestroy(s *terraform.State) error {
 	config := testAccProvider.Meta().(*Config)
 
@@ -271,3 +306,28 @@ resource "google_compute_image" "foobar" {
 	source_disk = "${google_compute_disk.foobar.self_link}"
 }`, acctest.RandString(10), acctest.RandString(10))
 }
+
+func testAccComputeImage_encryption(keyRingName, cryptoKeyName string) string {
+	return testAccComputeKmsKey(keyRingName, cryptoKeyName) + fmt.Sprintf(`
+resource "google_compute_disk" "foobar" {
+	name = "disk-test-%s"
+	zone = "us-central1-a"
+	image = "debian-8-jessie-v20160803"
+	disk_encryption_key_raw = "SGVsbG8gZnJvbSBHb29nbGUgQ2xvdWQgUGxhdGZvcm0="
+}
+
+resource "google_compute_image" "foobar" {
+	name = "image-test-%s"
+	source_disk = "${google_compute_disk.foobar.self_link}"
+
+	source_disk_encryption_key {
+		raw_key = "SGVsbG8gZnJvbSBHb29nbGUgQ2xvdWQgUGxhdGZvcm0="
+	}
+
+	image_encryption_key {
+		kms_key_self_link = "${google_kms_crypto_key.foobar.id}"
+	}
+
+	depends_on = ["google_kms_crypto_key_iam_member.foobar"]
+}`, acctest.RandString(10), acctest.RandString(10))
+}
@@ -20,7 +20,12 @@ import (
 )
 
 var InstanceBaseApiVersion = v1
-var InstanceVersionedFeatures = []Feature{}
+var InstanceVersionedFeatures = []Feature{
+	Feature{Version: v0beta, Item: "shielded_instance_config"},
+	Feature{Version: v0beta, Item: "scheduling.*.node_affinities"},
+	Feature{Version: v0beta, Item: "boot_disk.*.kms_key_self_link"},
+	Feature{Version: v0beta, Item: "attached_disk.*.kms_key_self_link"},
+}
 
 func resourceComputeInstance() *schema.Resource {
 	return &schema.Resource{
@@ -67,10 +72,11 @@ func resourceComputeInstance() *schema.Resource {
 						},
 
 						"disk_encryption_key_raw": &schema.Schema{
-							Type:      schema.TypeString,
-							Optional:  true,
-							ForceNew:  true,
-							Sensitive: true,
+							Type:          schema.TypeString,
+							Optional:      true,
+							ForceNew:      true,
+							Sensitive:     true,
+							ConflictsWith: []string{"boot_disk.0.kms_key_self_link"},
 						},
 
 						"disk_encryption_key_sha256": &schema.Schema{
@@ -78,6 +84,14 @@ func resourceComputeInstance() *schema.Resource {
 							Computed: true,
 						},
 
+						"kms_key_self_link": &schema.Schema{
+							Type:          schema.TypeString,
+							Optional:      true,
+							ForceNew:      true,
+							ValidateFunc:  validateKmsCryptoKeyId,
+							ConflictsWith: []string{"boot_disk.0.disk_encryption_key_raw"},
+						},
+
 						"initialize_params": &schema.Schema{
 							Type:     schema.TypeList,
 							Optional: true,
@@ -241,6 +255,12 @@ func resourceComputeInstance() *schema.Resource {
 							Type:     schema.TypeString,
 							Computed: true,
 						},
+
+						"kms_key_self_link": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateKmsCryptoKeyId,
+						},
 					},
 				},
 			},
@@ -470,6 +490,8 @@ func resourceComputeInstance() *schema.Resource {
 							Default:  false,
 							ForceNew: true,
 						},
+
+						"node_affinities": nodeAffinitiesSchema(),
 					},
 				},
 			},
@@ -552,6 +574,10 @@ func resourceComputeInstance() *schema.Resource {
 				Set:      schema.HashString,
 			},
 
+			"effective_labels": effectiveLabelsSchema(),
+
+			"shielded_instance_config": shieldedInstanceConfigSchema(false),
+
 			"allow_stopping_for_update": &schema.Schema{
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -582,36 +608,49 @@ func resourceComputeInstance() *schema.Resource {
 				},
 				suppressEmptyGuestAcceleratorDiff,
 			),
+			customdiff.If(
+				func(d *schema.ResourceDiff, meta interface{}) bool {
+					return d.HasChange("attached_disk")
+				},
+				customizeDiffAttachedDiskKmsKeys,
+			),
+			customizeDiffEffectiveLabels("labels"),
 		),
 	}
 }
 
-func getInstance(config *Config, d *schema.ResourceData) (*computeBeta.Instance, error) {
+// getInstance returns the instance. Instances that use beta features are read
+// as raw JSON, which is returned as well. It is nil for other instances.
+func getInstance(config *Config, d *schema.ResourceData) (*computeBeta.Instance, map[string]interface{}, error) {
 	project, err := getProject(d, config)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	zone, err := getZone(d, config)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	instance := &computeBeta.Instance{}
+	var rawInstance map[string]interface{}
 	switch getComputeApiVersion(d, InstanceBaseApiVersion, InstanceVersionedFeatures) {
 	case v1:
 		instanceV1, err := config.clientCompute.Instances.Get(project, zone, d.Id()).Do()
 		if err != nil {
-			return nil, handleNotFoundError(err, d, fmt.Sprintf("Instance %s", d.Get("name").(string)))
+			return nil, nil, handleNotFoundError(err, d, fmt.Sprintf("Instance %s", d.Get("name").(string)))
 		}
 		if err := Convert(instanceV1, instance); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	case v0beta:
-		instance, err = config.clientComputeBeta.Instances.Get(project, zone, d.Id()).Do()
+		rawInstance, err = Get(config, instanceBetaUrl(project, zone, d.Id()))
 		if err != nil {
-			return nil, handleNotFoundError(err, d, fmt.Sprintf("Instance %s", d.Get("name").(string)))
+			return nil, nil, handleNotFoundError(err, d, fmt.Sprintf("Instance %s", d.Get("name").(string)))
+		}
+		if err := Convert(rawInstance, instance); err != nil {
+			return nil, nil, err
 		}
 	}
-	return instance, nil
+	return instance, rawInstance, nil
 }
 
 func getDisk(diskUri string, d *schema.ResourceData, config *Config) (*compute.Disk, error) {
@@ -668,6 +707,17 @@ func resourceComputeInstanceCreate(d *schema.ResourceData, meta interface{}) err
 	}
 	disks = append(disks, bootDisk)
 
+	// Cloud KMS keys are kept by disk index and added to the disks when the
+	// instance is inserted.
+	diskKmsKeyNames := make(map[int]string)
+	kmsKeyName, err := expandKmsKeyName(d.Get("boot_disk.0.kms_key_self_link"), config)
+	if err != nil {
+		return err
+	}
+	if kmsKeyName != "" {
+		diskKmsKeyNames[0] = kmsKeyName
+	}
+
 	if _, hasScratchDisk := d.GetOk("scratch_disk"); hasScratchDisk {
 		scratchDisks, err := expandScratchDisks(d, config, zone, project)
 		if err != nil {
@@ -685,6 +735,14 @@ func resourceComputeInstanceCreate(d *schema.ResourceData, meta interface{}) err
 			return err
 		}
 
+		kmsKeyName, err := expandKmsKeyName(diskConfig["kms_key_self_link"], config)
+		if err != nil {
+			return err
+		}
+		if kmsKeyName != "" {
+			diskKmsKeyNames[len(disks)] = kmsKeyName
+		}
+
 		disks = append(disks, disk)
 	}
 
@@ -736,7 +794,7 @@ func resourceComputeInstanceCreate(d *schema.ResourceData, meta interface{}) err
 		Name:               d.Get("name").(string),
 		NetworkInterfaces:  networkInterfaces,
 		Tags:               resourceInstanceTags(d),
-		Labels:             expandLabels(d),
+		Labels:             expandEffectiveLabels(d, config, "labels"),
 		ServiceAccounts:    expandServiceAccounts(d.Get("service_account").([]interface{})),
 		GuestAccelerators:  accels,
 		MinCpuPlatform:     d.Get("min_cpu_platform").(string),
@@ -754,7 +812,23 @@ func resourceComputeInstanceCreate(d *schema.ResourceData, meta interface{}) err
 		}
 		op, err = config.clientCompute.Instances.Insert(project, zone.Name, instanceV1).Do()
 	case v0beta:
-		op, err = config.clientComputeBeta.Instances.Insert(project, zone.Name, instance).Do()
+		rawFields := make(map[string]interface{})
+		if shieldedInstanceConfig := expandShieldedInstanceConfig(d); shieldedInstanceConfig != nil {
+			rawFields["shieldedInstanceConfig"] = shieldedInstanceConfig
+		}
+		if nodeAffinities := expandNodeAffinities(d); nodeAffinities != nil {
+			rawFields["scheduling.nodeAffinities"] = nodeAffinities
+		}
+		for i, kmsKeyName := range diskKmsKeyNames {
+			rawFields[fmt.Sprintf("disks.%d.diskEncryptionKey.kmsKeyName", i)] = kmsKeyName
+		}
+
+		if len(rawFields) > 0 {
+			url := fmt.Sprintf("https://www.googleapis.com/compute/beta/projects/%s/zones/%s/instances", project, zone.Name)
+			op, err = postWithRawFields(config, url, instance, rawFields)
+		} else {
+			op, err = config.clientComputeBeta.Instances.Insert(project, zone.Name, instance).Do()
+		}
 	}
 	if err != nil {
 		return fmt.Errorf("Error creating instance: %s", err)
@@ -764,7 +838,7 @@ func resourceComputeInstanceCreate(d *schema.ResourceData, meta interface{}) err
 	d.SetId(instance.Name)
 
 	// Wait for the operation to complete
-	waitErr := computeSharedOperationWaitTime(config.clientCompute, op, project, createTimeout, "instance to create")
+	waitErr := computeSharedOperationWaitTimeConfig(config, op, project, createTimeout, "instance to create")
 	if waitErr != nil {
 		// The resource didn't actually create
 		d.SetId("")
@@ -782,7 +856,7 @@ func resourceComputeInstanceRead(d *schema.ResourceData, meta interface{}) error
 		return err
 	}
 
-	instance, err := getInstance(config, d)
+	instance, rawInstance, err := getInstance(config, d)
 	if err != nil || instance == nil {
 		return err
 	}
@@ -849,8 +923,8 @@ func resourceComputeInstanceRead(d *schema.ResourceData, meta interface{}) error
 		d.Set("tags", convertStringArrToInterface(instance.Tags.Items))
 	}
 
-	if len(instance.Labels) > 0 {
-		d.Set("labels", instance.Labels)
+	if err := setEffectiveLabels(d, config, "labels", instance.Labels); err != nil {
+		return err
 	}
 
 	if instance.LabelFingerprint != "" {
@@ -873,11 +947,22 @@ func resourceComputeInstanceRead(d *schema.ResourceData, meta interface{}) error
 		attachedDiskSources[source.RelativeLink()] = i
 	}
 
+	diskKmsKeyNames := make(map[string]interface{})
+	if rawDisks, ok := rawInstance["disks"].([]interface{}); ok {
+		for _, raw := range rawDisks {
+			rawDisk, _ := raw.(map[string]interface{})
+			deviceName, _ := rawDisk["deviceName"].(string)
+			if key, ok := rawDisk["diskEncryptionKey"].(map[string]interface{}); ok {
+				diskKmsKeyNames[deviceName] = key["kmsKeyName"]
+			}
+		}
+	}
+
 	attachedDisks := make([]map[string]interface{}, d.Get("attached_disk.#").(int))
 	scratchDisks := []map[string]interface{}{}
 	for _, disk := range instance.Disks {
 		if disk.Boot {
-			d.Set("boot_disk", flattenBootDisk(d, disk, config))
+			d.Set("boot_disk", flattenBootDisk(d, disk, diskKmsKeyNames[disk.DeviceName], config))
 		} else if disk.Type == "SCRATCH" {
 			scratchDisks = append(scratchDisks, flattenScratchDisk(disk))
 		} else {
@@ -892,10 +977,13 @@ func resourceComputeInstanceRead(d *schema.ResourceData, meta interface{}) error
 				"mode":        disk.Mode,
 			}
 			if key := disk.DiskEncryptionKey; key != nil {
+				kmsKeySelfLink := ""
 				if inConfig {
 					di["disk_encryption_key_raw"] = d.Get(fmt.Sprintf("attached_disk.%d.disk_encryption_key_raw", adIndex))
+					kmsKeySelfLink = d.Get(fmt.Sprintf("attached_disk.%d.kms_key_self_link", adIndex)).(string)
 				}
 				di["disk_encryption_key_sha256"] = key.Sha256
+				di["kms_key_self_link"] = flattenKmsKeySelfLink(kmsKeySelfLink, diskKmsKeyNames[disk.DeviceName], config)
 			}
 			// We want the disks to remain in the order we set in the config, so if a disk
 			// is present in the config, make sure it's at the correct index. Otherwise, append it.
@@ -918,12 +1006,20 @@ func resourceComputeInstanceRead(d *schema.ResourceData, meta interface{}) error
 	d.Set("service_account", flattenServiceAccounts(instance.ServiceAccounts))
 	d.Set("attached_disk", ads)
 	d.Set("scratch_disk", scratchDisks)
-	d.Set("scheduling", flattenScheduling(instance.Scheduling))
 	d.Set("guest_accelerator", flattenGuestAccelerators(instance.GuestAccelerators))
 	d.Set("cpu_platform", instance.CpuPlatform)
 	d.Set("min_cpu_platform", instance.MinCpuPlatform)
 	d.Set("deletion_protection", instance.DeletionProtection)
 	d.Set("self_link", ConvertSelfLinkToV1(instance.SelfLink))
+
+	rawScheduling, _ := rawInstance["scheduling"].(map[string]interface{})
+	if err := d.Set("scheduling", flattenScheduling(instance.Scheduling, rawScheduling["nodeAffinities"])); err != nil {
+		return fmt.Errorf("Error setting scheduling: %s", err)
+	}
+	if err := d.Set("shielded_instance_config", flattenShieldedInstanceConfig(rawInstance["shieldedInstanceConfig"])); err != nil {
+		return fmt.Errorf("Error setting shielded_instance_config: %s", err)
+	}
+
 	d.Set("instance_id", fmt.Sprintf("%d", instance.Id))
 	d.Set("project", project)
 	d.Set("zone", GetResourceNameFromSelfLink(instance.Zone))
@@ -972,7 +1068,7 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 
 		updateMD := func() error {
 			// Reload the instance in the case of a fingerprint mismatch
-			instance, err = getInstance(config, d)
+			instance, _, err = getInstance(config, d)
 			if err != nil {
 				return err
 			}
@@ -997,7 +1093,7 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 				return fmt.Errorf("Error updating metadata: %s", err)
 			}
 
-			opErr := computeOperationWaitTime(config.clientCompute, op, project, "metadata to update", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+			opErr := computeOperationWaitTimeConfig(config, op, project, "metadata to update", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
 			if opErr != nil {
 				return opErr
 			}
@@ -1023,7 +1119,7 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 			return fmt.Errorf("Error updating tags: %s", err)
 		}
 
-		opErr := computeOperationWaitTime(config.clientCompute, op, project, "tags to update", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+		opErr := computeOperationWaitTimeConfig(config, op, project, "tags to update", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
 		if opErr != nil {
 			return opErr
 		}
@@ -1031,8 +1127,8 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 		d.SetPartial("tags")
 	}
 
-	if d.HasChange("labels") {
-		labels := expandLabels(d)
+	if effectiveLabelsHaveChanged(d, "labels") {
+		labels := expandEffectiveLabels(d, config, "labels")
 		labelFingerprint := d.Get("label_fingerprint").(string)
 		req := compute.InstancesSetLabelsRequest{Labels: labels, LabelFingerprint: labelFingerprint}
 
@@ -1041,12 +1137,13 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 			return fmt.Errorf("Error updating labels: %s", err)
 		}
 
-		opErr := computeOperationWaitTime(config.clientCompute, op, project, "labels to update", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+		opErr := computeOperationWaitTimeConfig(config, op, project, "labels to update", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
 		if opErr != nil {
 			return opErr
 		}
 
 		d.SetPartial("labels")
+		d.SetPartial("effective_labels")
 	}
 
 	if d.HasChange("scheduling") {
@@ -1064,14 +1161,32 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 		}
 		scheduling.ForceSendFields = []string{"AutomaticRestart", "Preemptible"}
 
-		op, err := config.clientCompute.Instances.SetScheduling(project,
-			zone, d.Id(), scheduling).Do()
+		var op *compute.Operation
+		if nodeAffinities := expandNodeAffinities(d); nodeAffinities != nil {
+			// setScheduling replaces the whole scheduling block, so node
+			// affinities have to be sent along through the beta API.
+			body := make(map[string]interface{})
+			if err := Convert(scheduling, &body); err != nil {
+				return err
+			}
+			body["nodeAffinities"] = nodeAffinities
+
+			var res map[string]interface{}
+			res, err = Post(config, instanceBetaUrl(project, zone, d.Id())+"/setScheduling", body)
+			if err == nil {
+				op = &compute.Operation{}
+				err = Convert(res, op)
+			}
+		} else {
+			op, err = config.clientCompute.Instances.SetScheduling(project,
+				zone, d.Id(), scheduling).Do()
+		}
 
 		if err != nil {
 			return fmt.Errorf("Error updating scheduling policy: %s", err)
 		}
 
-		opErr := computeOperationWaitTime(config.clientCompute, op, project, "scheduling policy update", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+		opErr := computeOperationWaitTimeConfig(config, op, project, "scheduling policy update", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
 		if opErr != nil {
 			return opErr
 		}
@@ -1111,7 +1226,7 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 				if err != nil {
 					return fmt.Errorf("Error deleting old access_config: %s", err)
 				}
-				opErr := computeOperationWaitTime(config.clientCompute, op, project, "old access_config to delete", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+				opErr := computeOperationWaitTimeConfig(config, op, project, "old access_config to delete", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
 				if opErr != nil {
 					return opErr
 				}
@@ -1134,7 +1249,7 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 				if err != nil {
 					return fmt.Errorf("Error adding new access_config: %s", err)
 				}
-				opErr := computeOperationWaitTime(config.clientCompute, op, project, "new access_config to add", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+				opErr := computeOperationWaitTimeConfig(config, op, project, "new access_config to add", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
 				if opErr != nil {
 					return opErr
 				}
@@ -1154,7 +1269,7 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 				if err != nil {
 					return errwrap.Wrapf("Error removing alias_ip_range: {{err}}", err)
 				}
-				opErr := computeSharedOperationWaitTime(config.clientCompute, op, project, int(d.Timeout(schema.TimeoutUpdate).Minutes()), "updaing alias ip ranges")
+				opErr := computeSharedOperationWaitTimeConfig(config, op, project, int(d.Timeout(schema.TimeoutUpdate).Minutes()), "updaing alias ip ranges")
 				if opErr != nil {
 					return opErr
 				}
@@ -1178,7 +1293,7 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 				if err != nil {
 					return errwrap.Wrapf("Error adding alias_ip_range: {{err}}", err)
 				}
-				opErr := computeSharedOperationWaitTime(config.clientCompute, op, project, int(d.Timeout(schema.TimeoutUpdate).Minutes()), "updaing alias ip ranges")
+				opErr := computeSharedOperationWaitTimeConfig(config, op, project, int(d.Timeout(schema.TimeoutUpdate).Minutes()), "updaing alias ip ranges")
 				if opErr != nil {
 					return opErr
 				}
@@ -1210,7 +1325,7 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 			if err != nil {
 				return err
 			}
-			hash, err := hashstructure.Hash(*computeDisk, nil)
+			hash, err := hashAttachedDisk(computeDisk, diskConfig, config)
 			if err != nil {
 				return err
 			}
@@ -1225,13 +1340,14 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 		// If a disk with a certain hash is only in the new config, it should be attached.
 		nDisks := map[uint64]struct{}{}
 		var attach []*compute.AttachedDisk
+		var attachKmsKeyNames []string
 		for _, disk := range n.([]interface{}) {
 			diskConfig := disk.(map[string]interface{})
 			computeDisk, err := expandAttachedDisk(diskConfig, d, config)
 			if err != nil {
 				return err
 			}
-			hash, err := hashstructure.Hash(*computeDisk, nil)
+			hash, err := hashAttachedDisk(computeDisk, diskConfig, config)
 			if err != nil {
 				return err
 			}
@@ -1243,7 +1359,12 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 				if err != nil {
 					return err
 				}
+				kmsKeyName, err := expandKmsKeyName(diskConfig["kms_key_self_link"], config)
+				if err != nil {
+					return err
+				}
 				attach = append(attach, computeDiskV1)
+				attachKmsKeyNames = append(attachKmsKeyNames, kmsKeyName)
 			}
 		}
 
@@ -1256,7 +1377,7 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 					return errwrap.Wrapf("Error detaching disk: %s", err)
 				}
 
-				opErr := computeOperationWaitTime(config.clientCompute, op, project, "detaching disk", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+				opErr := computeOperationWaitTimeConfig(config, op, project, "detaching disk", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
 				if opErr != nil {
 					return opErr
 				}
@@ -1265,13 +1386,20 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 		}
 
 		// Attach the new disks
-		for _, disk := range attach {
-			op, err := config.clientCompute.Instances.AttachDisk(project, zone, instance.Name, disk).Do()
+		for i, disk := range attach {
+			var op *compute.Operation
+			if kmsKeyName := attachKmsKeyNames[i]; kmsKeyName != "" {
+				op, err = postWithRawFields(config, instanceBetaUrl(project, zone, instance.Name)+"/attachDisk", disk, map[string]interface{}{
+					"diskEncryptionKey.kmsKeyName": kmsKeyName,
+				})
+			} else {
+				op, err = config.clientCompute.Instances.AttachDisk(project, zone, instance.Name, disk).Do()
+			}
 			if err != nil {
 				return errwrap.Wrapf("Error attaching disk : {{err}}", err)
 			}
 
-			opErr := computeOperationWaitTime(config.clientCompute, op, project, "attaching disk", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+			opErr := computeOperationWaitTimeConfig(config, op, project, "attaching disk", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
 			if opErr != nil {
 				return opErr
 			}
@@ -1305,7 +1433,7 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 			return fmt.Errorf("Error updating deletion protection flag: %s", err)
 		}
 
-		opErr := computeOperationWaitTime(config.clientCompute, op, project, "deletion protection to update", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+		opErr := computeOperationWaitTimeConfig(config, op, project, "deletion protection to update", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
 		if opErr != nil {
 			return opErr
 		}
@@ -1314,9 +1442,9 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 	}
 
 	// Attributes which can only be changed if the instance is stopped
-	if scopesChange || d.HasChange("service_account.0.email") || d.HasChange("machine_type") || d.HasChange("min_cpu_platform") {
+	if scopesChange || d.HasChange("service_account.0.email") || d.HasChange("machine_type") || d.HasChange("min_cpu_platform") || d.HasChange("shielded_instance_config") {
 		if !d.Get("allow_stopping_for_update").(bool) {
-			return fmt.Errorf("Changing the machine_type, min_cpu_platform, or service_account on an instance requires stopping it. " +
+			return fmt.Errorf("Changing the machine_type, min_cpu_platform, service_account, or shielded_instance_config on an instance requires stopping it. " +
 				"To acknowledge this, please set allow_stopping_for_update = true in your config.")
 		}
 		op, err := config.clientCompute.Instances.Stop(project, zone, instance.Name).Do()
@@ -1324,7 +1452,7 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 			return errwrap.Wrapf("Error stopping instance: {{err}}", err)
 		}
 
-		opErr := computeOperationWaitTime(config.clientCompute, op, project, "stopping instance", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+		opErr := computeOperationWaitTimeConfig(config, op, project, "stopping instance", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
 		if opErr != nil {
 			return opErr
 		}
@@ -1341,7 +1469,7 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 			if err != nil {
 				return err
 			}
-			opErr := computeOperationWaitTime(config.clientCompute, op, project, "updating machinetype", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+			opErr := computeOperationWaitTimeConfig(config, op, project, "updating machinetype", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
 			if opErr != nil {
 				return opErr
 			}
@@ -1363,7 +1491,7 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 			if err != nil {
 				return err
 			}
-			opErr := computeOperationWaitTime(config.clientCompute, op, project, "updating min cpu platform", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+			opErr := computeOperationWaitTimeConfig(config, op, project, "updating min cpu platform", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
 			if opErr != nil {
 				return opErr
 			}
@@ -1382,19 +1510,37 @@ func resourceComputeInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 			if err != nil {
 				return err
 			}
-			opErr := computeOperationWaitTime(config.clientCompute, op, project, "updating service account", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+			opErr := computeOperationWaitTimeConfig(config, op, project, "updating service account", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
 			if opErr != nil {
 				return opErr
 			}
 			d.SetPartial("service_account")
 		}
 
+		if d.HasChange("shielded_instance_config") {
+			// The flags are all false by default, so they have to be sent
+			// explicitly to turn one off.
+			res, err := Patch(config, instanceBetaUrl(project, zone, instance.Name)+"/updateShieldedInstanceConfig", expandShieldedInstanceConfig(d), shieldedInstanceConfigFields, nil)
+			if err != nil {
+				return fmt.Errorf("Error updating shielded_instance_config: %s", err)
+			}
+			op := &compute.Operation{}
+			if err := Convert(res, op); err != nil {
+				return err
+			}
+			opErr := computeOperationWaitTimeConfig(config, op, project, "updating shielded instance config", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+			if opErr != nil {
+				return opErr
+			}
+			d.SetPartial("shielded_instance_config")
+		}
+
 		op, err = config.clientCompute.Instances.Start(project, zone, instance.Name).Do()
 		if err != nil {
 			return errwrap.Wrapf("Error starting instance: {{err}}", err)
 		}
 
-		opErr = computeOperationWaitTime(config.clientCompute, op, project, "starting instance", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+		opErr = computeOperationWaitTimeConfig(config, op, project, "starting instance", int(d.Timeout(schema.TimeoutUpdate).Minutes()))
 		if opErr != nil {
 			return opErr
 		}
@@ -1427,6 +1573,9 @@ func expandAttachedDisk(diskConfig map[string]interface{}, d *schema.ResourceDat
 	}
 
 	if v, ok := diskConfig["disk_encryption_key_raw"]; ok {
+		if v.(string) != "" && diskConfig["kms_key_self_link"] != nil && diskConfig["kms_key_self_link"].(string) != "" {
+			return nil, fmt.Errorf("Only one of disk_encryption_key_raw or kms_key_self_link can be set on attached disk %q", diskConfig["source"])
+		}
 		disk.DiskEncryptionKey = &computeBeta.CustomerEncryptionKey{
 			RawKey: v.(string),
 		}
@@ -1434,6 +1583,23 @@ func expandAttachedDisk(diskConfig map[string]interface{}, d *schema.ResourceDat
 	return disk, nil
 }
 
+// hashAttachedDisk hashes an attached disk along with its Cloud KMS key, which
+// expandAttachedDisk can't set on the disk itself, so that changing the key
+// reattaches the disk.
+func hashAttachedDisk(disk *computeBeta.AttachedDisk, diskConfig map[string]interface{}, config *Config) (uint64, error) {
+	kmsKeyName, err := expandKmsKeyName(diskConfig["kms_key_self_link"], config)
+	if err != nil {
+		return 0, err
+	}
+	if kmsKeyName == "" {
+		return hashstructure.Hash(*disk, nil)
+	}
+	return hashstructure.Hash(struct {
+		Disk       computeBeta.AttachedDisk
+		KmsKeyName string
+	}{*disk, kmsKeyName}, nil)
+}
+
 // See comment on expandInstanceTemplateGuestAccelerators regarding why this
 // code is duplicated.
 func expandInstanceGuestAccelerators(d TerraformResourceData, config *Config) ([]*computeBeta.AcceleratorConfig, error) {
@@ -1503,6 +1669,34 @@ func suppressEmptyGuestAcceleratorDiff(d *schema.ResourceDiff, meta interface{})
 	return nil
 }
 
+// customizeDiffAttachedDiskKmsKeys rejects changing the Cloud KMS key of a
+// disk that stays attached. The key is the one the disk was created with, and
+// detaching and reattaching the disk doesn't change it.
+func customizeDiffAttachedDiskKmsKeys(d *schema.ResourceDiff, meta interface{}) error {
+	config := meta.(*Config)
+	o, n := d.GetChange("attached_disk")
+
+	oldKeys := make(map[string]string)
+	for _, v := range o.([]interface{}) {
+		disk := v.(map[string]interface{})
+		oldKeys[GetResourceNameFromSelfLink(disk["source"].(string))] = disk["kms_key_self_link"].(string)
+	}
+
+	for _, v := range n.([]interface{}) {
+		disk := v.(map[string]interface{})
+		source := GetResourceNameFromSelfLink(disk["source"].(string))
+		oldKey, ok := oldKeys[source]
+		if !ok {
+			continue
+		}
+		if newKey := disk["kms_key_self_link"].(string); !kmsKeySelfLinksEqual(oldKey, newKey, config) {
+			return fmt.Errorf("attached_disk %q: kms_key_self_link can't be changed from %q to %q, a disk keeps the key it was created with", source, oldKey, newKey)
+		}
+	}
+
+	return nil
+}
+
 func resourceComputeInstanceDelete(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 
@@ -1526,7 +1720,7 @@ func resourceComputeInstanceDelete(d *schema.ResourceData, meta interface{}) err
 		}
 
 		// Wait for the operation to complete
-		opErr := computeOperationWaitTime(config.clientCompute, op, project, "instance to delete", int(d.Timeout(schema.TimeoutDelete).Minutes()))
+		opErr := computeOperationWaitTimeConfig(config, op, project, "instance to delete", int(d.Timeout(schema.TimeoutDelete).Minutes()))
 		if opErr != nil {
 			return opErr
 		}
@@ -1604,7 +1798,7 @@ func expandBootDisk(d *schema.ResourceData, config *Config, zone *compute.Zone,
 	return disk, nil
 }
 
-func flattenBootDisk(d *schema.ResourceData, disk *computeBeta.AttachedDisk, config *Config) []map[string]interface{} {
+func flattenBootDisk(d *schema.ResourceData, disk *computeBeta.AttachedDisk, kmsKeyName interface{}, config *Config) []map[string]interface{} {
 	result := map[string]interface{}{
 		"auto_delete": disk.AutoDelete,
 		"device_name": disk.DeviceName,
@@ -1612,6 +1806,7 @@ func flattenBootDisk(d *schema.ResourceData, disk *computeBeta.AttachedDisk, con
 		// disk_encryption_key_raw is not returned from the API, so copy it from what the user
 		// originally specified to avoid diffs.
 		"disk_encryption_key_raw": d.Get("boot_disk.0.disk_encryption_key_raw"),
+		"kms_key_self_link":       flattenKmsKeySelfLink(d.Get("boot_disk.0.kms_key_self_link").(string), kmsKeyName, config),
 	}
 
 	diskDetails, err := getDisk(disk.Source, d, config)
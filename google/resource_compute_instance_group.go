@@ -158,7 +158,7 @@ func resourceComputeInstanceGroupCreate(d *schema.ResourceData, meta interface{}
 	d.SetId(fmt.Sprintf("%s/%s", zone, name))
 
 	// Wait for the operation to complete
-	err = computeOperationWait(config.clientCompute, op, project, "Creating InstanceGroup")
+	err = computeOperationWaitConfig(config, op, project, "Creating InstanceGroup")
 	if err != nil {
 		d.SetId("")
 		return err
@@ -182,7 +182,7 @@ func resourceComputeInstanceGroupCreate(d *schema.ResourceData, meta interface{}
 		}
 
 		// Wait for the operation to complete
-		err = computeOperationWait(config.clientCompute, op, project, "Adding instances to InstanceGroup")
+		err = computeOperationWaitConfig(config, op, project, "Adding instances to InstanceGroup")
 		if err != nil {
 			return err
 		}
@@ -294,7 +294,7 @@ func resourceComputeInstanceGroupUpdate(d *schema.ResourceData, meta interface{}
 				}
 			} else {
 				// Wait for the operation to complete
-				err = computeOperationWait(config.clientCompute, removeOp, project, "Updating InstanceGroup")
+				err = computeOperationWaitConfig(config, removeOp, project, "Updating InstanceGroup")
 				if err != nil {
 					return err
 				}
@@ -315,7 +315,7 @@ func resourceComputeInstanceGroupUpdate(d *schema.ResourceData, meta interface{}
 			}
 
 			// Wait for the operation to complete
-			err = computeOperationWait(config.clientCompute, addOp, project, "Updating InstanceGroup")
+			err = computeOperationWaitConfig(config, addOp, project, "Updating InstanceGroup")
 			if err != nil {
 				return err
 			}
@@ -338,7 +338,7 @@ func resourceComputeInstanceGroupUpdate(d *schema.ResourceData, meta interface{}
 			return fmt.Errorf("Error updating named ports for InstanceGroup: %s", err)
 		}
 
-		err = computeOperationWait(config.clientCompute, op, project, "Updating InstanceGroup")
+		err = computeOperationWaitConfig(config, op, project, "Updating InstanceGroup")
 		if err != nil {
 			return err
 		}
@@ -368,7 +368,7 @@ func resourceComputeInstanceGroupDelete(d *schema.ResourceData, meta interface{}
 		return fmt.Errorf("Error deleting InstanceGroup: %s", err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Deleting InstanceGroup")
+	err = computeOperationWaitConfig(config, op, project, "Deleting InstanceGroup")
 	if err != nil {
 		return err
 	}
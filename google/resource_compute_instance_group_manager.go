@@ -18,6 +18,8 @@ var InstanceGroupManagerBaseApiVersion = v1
 var InstanceGroupManagerVersionedFeatures = []Feature{
 	Feature{Version: v0beta, Item: "auto_healing_policies"},
 	Feature{Version: v0beta, Item: "rolling_update_policy"},
+	Feature{Version: v0beta, Item: "stateful_disk"},
+	Feature{Version: v0beta, Item: "version"},
 }
 
 func resourceComputeInstanceGroupManager() *schema.Resource {
@@ -30,6 +32,8 @@ func resourceComputeInstanceGroupManager() *schema.Resource {
 			State: resourceInstanceGroupManagerStateImporter,
 		},
 
+		CustomizeDiff: customizeDiffInstanceGroupManagerVersions,
+
 		Schema: map[string]*schema.Schema{
 			"base_instance_name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -39,10 +43,14 @@ func resourceComputeInstanceGroupManager() *schema.Resource {
 
 			"instance_template": &schema.Schema{
 				Type:             schema.TypeString,
-				Required:         true,
+				Optional:         true,
+				Computed:         true,
+				ConflictsWith:    []string{"version"},
 				DiffSuppressFunc: compareSelfLinkRelativePaths,
 			},
 
+			"version": instanceGroupManagerVersionSchema(),
+
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
@@ -198,6 +206,8 @@ func resourceComputeInstanceGroupManager() *schema.Resource {
 					},
 				},
 			},
+			"stateful_disk": statefulDiskSchema(),
+
 			"wait_for_instances": &schema.Schema{
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -251,6 +261,10 @@ func resourceComputeInstanceGroupManagerCreate(d *schema.ResourceData, meta inte
 		return fmt.Errorf("[rolling_update_policy] must be set when 'update_strategy' is set to 'ROLLING_UPDATE'")
 	}
 
+	if _, ok := d.GetOk("version"); !ok && d.Get("instance_template").(string) == "" {
+		return fmt.Errorf("One of 'instance_template' or 'version' must be set")
+	}
+
 	// Build the parameter
 	manager := &computeBeta.InstanceGroupManager{
 		Name:                d.Get("name").(string),
@@ -261,6 +275,7 @@ func resourceComputeInstanceGroupManagerCreate(d *schema.ResourceData, meta inte
 		NamedPorts:          getNamedPortsBeta(d.Get("named_port").([]interface{})),
 		TargetPools:         convertStringSet(d.Get("target_pools").(*schema.Set)),
 		AutoHealingPolicies: expandAutoHealingPolicies(d.Get("auto_healing_policies").([]interface{})),
+		Versions:            expandVersions(d.Get("version").([]interface{})),
 		// Force send TargetSize to allow a value of 0.
 		ForceSendFields: []string{"TargetSize"},
 	}
@@ -298,11 +313,18 @@ func resourceComputeInstanceGroupManagerCreate(d *schema.ResourceData, meta inte
 	d.SetId(manager.Name)
 
 	// Wait for the operation to complete
-	err = computeSharedOperationWait(config.clientCompute, op, project, "Creating InstanceGroupManager")
+	err = computeSharedOperationWaitConfig(config, op, project, "Creating InstanceGroupManager")
 	if err != nil {
 		return err
 	}
 
+	if _, ok := d.GetOk("stateful_disk"); ok {
+		managerUrl := instanceGroupManagerBetaUrl(project, "zones/"+zone, manager.Name)
+		if err := updateStatefulPolicy(d, config, managerUrl, project); err != nil {
+			return err
+		}
+	}
+
 	return resourceComputeInstanceGroupManagerRead(d, meta)
 }
 
@@ -441,6 +463,16 @@ func resourceComputeInstanceGroupManagerRead(d *schema.ResourceData, meta interf
 	}
 	d.Set("update_strategy", update_strategy.(string))
 	d.Set("auto_healing_policies", flattenAutoHealingPolicies(manager.AutoHealingPolicies))
+	if _, ok := d.GetOk("version"); ok || usesVersions(manager.Versions) {
+		if err := d.Set("version", flattenVersions(manager.Versions)); err != nil {
+			return err
+		}
+	}
+	if _, ok := d.GetOk("stateful_disk"); ok {
+		if err := readStatefulDisks(d, config, instanceGroupManagerBetaUrl(project, "zones/"+GetResourceNameFromSelfLink(manager.Zone), manager.Name)); err != nil {
+			return err
+		}
+	}
 
 	if d.Get("wait_for_instances").(bool) {
 		conf := resource.StateChangeConf{
@@ -515,7 +547,7 @@ func resourceComputeInstanceGroupManagerUpdate(d *schema.ResourceData, meta inte
 		}
 
 		// Wait for the operation to complete
-		err = computeSharedOperationWait(config.clientCompute, op, project, "Updating InstanceGroupManager")
+		err = computeSharedOperationWaitConfig(config, op, project, "Updating InstanceGroupManager")
 		if err != nil {
 			return err
 		}
@@ -557,7 +589,7 @@ func resourceComputeInstanceGroupManagerUpdate(d *schema.ResourceData, meta inte
 		}
 
 		// Wait for the operation to complete
-		err = computeSharedOperationWait(config.clientCompute, op, project, "Updating InstanceGroupManager")
+		err = computeSharedOperationWaitConfig(config, op, project, "Updating InstanceGroupManager")
 		if err != nil {
 			return err
 		}
@@ -628,7 +660,7 @@ func resourceComputeInstanceGroupManagerUpdate(d *schema.ResourceData, meta inte
 			}
 
 			// Wait for the operation to complete
-			err = computeSharedOperationWaitTime(config.clientCompute, op, project, managedInstanceCount*4, "Restarting InstanceGroupManagers instances")
+			err = computeSharedOperationWaitTimeConfig(config, op, project, managedInstanceCount*4, "Restarting InstanceGroupManagers instances")
 			if err != nil {
 				return err
 			}
@@ -650,7 +682,7 @@ func resourceComputeInstanceGroupManagerUpdate(d *schema.ResourceData, meta inte
 				return fmt.Errorf("Error updating managed group instances: %s", err)
 			}
 
-			err = computeSharedOperationWait(config.clientCompute, op, project, "Updating managed group instances")
+			err = computeSharedOperationWaitConfig(config, op, project, "Updating managed group instances")
 			if err != nil {
 				return err
 			}
@@ -659,6 +691,32 @@ func resourceComputeInstanceGroupManagerUpdate(d *schema.ResourceData, meta inte
 		d.SetPartial("instance_template")
 	}
 
+	// We will always be in v0beta inside this conditional
+	if d.HasChange("version") && len(d.Get("version").([]interface{})) > 0 {
+		manager := &computeBeta.InstanceGroupManager{
+			Versions: expandVersions(d.Get("version").([]interface{})),
+		}
+
+		// Versions are rolled out to existing instances like a new instance_template
+		// would be, so the update policy goes along with them.
+		if d.Get("update_strategy").(string) == "ROLLING_UPDATE" {
+			manager.UpdatePolicy = expandUpdatePolicy(d.Get("rolling_update_policy").([]interface{}))
+		}
+
+		op, err := config.clientComputeBeta.InstanceGroupManagers.Patch(
+			project, zone, d.Id(), manager).Do()
+		if err != nil {
+			return fmt.Errorf("Error updating versions: %s", err)
+		}
+
+		err = computeSharedOperationWaitConfig(config, op, project, "Updating versions")
+		if err != nil {
+			return err
+		}
+
+		d.SetPartial("version")
+	}
+
 	// If named_port changes then update:
 	if d.HasChange("named_port") {
 
@@ -696,7 +754,7 @@ func resourceComputeInstanceGroupManagerUpdate(d *schema.ResourceData, meta inte
 		}
 
 		// Wait for the operation to complete:
-		err = computeSharedOperationWait(config.clientCompute, op, project, "Updating InstanceGroupManager")
+		err = computeSharedOperationWaitConfig(config, op, project, "Updating InstanceGroupManager")
 		if err != nil {
 			return err
 		}
@@ -721,7 +779,7 @@ func resourceComputeInstanceGroupManagerUpdate(d *schema.ResourceData, meta inte
 		}
 
 		// Wait for the operation to complete
-		err = computeSharedOperationWait(config.clientCompute, op, project, "Updating InstanceGroupManager")
+		err = computeSharedOperationWaitConfig(config, op, project, "Updating InstanceGroupManager")
 		if err != nil {
 			return err
 		}
@@ -744,7 +802,7 @@ func resourceComputeInstanceGroupManagerUpdate(d *schema.ResourceData, meta inte
 		}
 
 		// Wait for the operation to complete
-		err = computeSharedOperationWait(config.clientCompute, op, project, "Updating AutoHealingPolicies")
+		err = computeSharedOperationWaitConfig(config, op, project, "Updating AutoHealingPolicies")
 		if err != nil {
 			return err
 		}
@@ -752,6 +810,15 @@ func resourceComputeInstanceGroupManagerUpdate(d *schema.ResourceData, meta inte
 		d.SetPartial("auto_healing_policies")
 	}
 
+	if d.HasChange("stateful_disk") {
+		managerUrl := instanceGroupManagerBetaUrl(project, "zones/"+zone, d.Id())
+		if err := updateStatefulPolicy(d, config, managerUrl, project); err != nil {
+			return err
+		}
+
+		d.SetPartial("stateful_disk")
+	}
+
 	d.Partial(false)
 
 	return resourceComputeInstanceGroupManagerRead(d, meta)
@@ -798,7 +865,7 @@ func resourceComputeInstanceGroupManagerDelete(d *schema.ResourceData, meta inte
 	currentSize := int64(d.Get("target_size").(int))
 
 	// Wait for the operation to complete
-	err = computeSharedOperationWait(config.clientCompute, op, project, "Deleting InstanceGroupManager")
+	err = computeSharedOperationWaitConfig(config, op, project, "Deleting InstanceGroupManager")
 
 	for err != nil && currentSize > 0 {
 		if !strings.Contains(err.Error(), "timeout") {
@@ -831,7 +898,7 @@ func resourceComputeInstanceGroupManagerDelete(d *schema.ResourceData, meta inte
 
 		log.Printf("[INFO] timeout occured, but instance group is shrinking (%d < %d)", instanceGroupSize, currentSize)
 		currentSize = instanceGroupSize
-		err = computeSharedOperationWait(config.clientCompute, op, project, "Deleting InstanceGroupManager")
+		err = computeSharedOperationWaitConfig(config, op, project, "Deleting InstanceGroupManager")
 	}
 
 	d.SetId("")
@@ -852,6 +919,148 @@ func expandAutoHealingPolicies(configured []interface{}) []*computeBeta.Instance
 	return autoHealingPolicies
 }
 
+func instanceGroupManagerVersionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		ConflictsWith: []string{"instance_template"},
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+
+				"instance_template": &schema.Schema{
+					Type:             schema.TypeString,
+					Required:         true,
+					DiffSuppressFunc: compareSelfLinkRelativePaths,
+				},
+
+				"target_size": &schema.Schema{
+					Type:     schema.TypeList,
+					Optional: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"fixed": &schema.Schema{
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+
+							"percent": &schema.Schema{
+								Type:         schema.TypeInt,
+								Optional:     true,
+								ValidateFunc: validation.IntBetween(1, 100),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// customizeDiffInstanceGroupManagerVersions rejects versions the API would
+// refuse, or that would be read back differently from how they're set.
+func customizeDiffInstanceGroupManagerVersions(diff *schema.ResourceDiff, meta interface{}) error {
+	return validateInstanceGroupManagerVersions(diff.Get("version").([]interface{}))
+}
+
+func validateInstanceGroupManagerVersions(versions []interface{}) error {
+	var withoutTargetSize []string
+	for _, raw := range versions {
+		data := raw.(map[string]interface{})
+		name := data["name"].(string)
+
+		targetSizes, _ := data["target_size"].([]interface{})
+		if len(targetSizes) == 0 || targetSizes[0] == nil {
+			withoutTargetSize = append(withoutTargetSize, name)
+			continue
+		}
+
+		// An unset fixed can't be told apart from 0, which is a valid size,
+		// so only a non-zero fixed conflicts with percent. An explicit
+		// percent of 0 is rejected by its ValidateFunc.
+		targetSize := targetSizes[0].(map[string]interface{})
+		if targetSize["fixed"].(int) != 0 && targetSize["percent"].(int) != 0 {
+			return fmt.Errorf("version %q: only one of target_size.fixed and target_size.percent can be set", name)
+		}
+	}
+
+	if len(withoutTargetSize) > 1 {
+		return fmt.Errorf("only one version can leave target_size unset, but %s all do", strings.Join(withoutTargetSize, ", "))
+	}
+	return nil
+}
+
+func expandVersions(configured []interface{}) []*computeBeta.InstanceGroupManagerVersion {
+	versions := make([]*computeBeta.InstanceGroupManagerVersion, 0, len(configured))
+	for _, raw := range configured {
+		data := raw.(map[string]interface{})
+
+		version := computeBeta.InstanceGroupManagerVersion{
+			Name:             data["name"].(string),
+			InstanceTemplate: data["instance_template"].(string),
+		}
+
+		// A version without a target size takes the instances the other
+		// versions leave over.
+		if v, ok := data["target_size"]; ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+			targetSize := v.([]interface{})[0].(map[string]interface{})
+
+			// customizeDiffInstanceGroupManagerVersions makes sure only one
+			// of percent and fixed is set.
+			if percent := targetSize["percent"].(int); percent > 0 {
+				version.TargetSize = &computeBeta.FixedOrPercent{
+					Percent: int64(percent),
+				}
+			} else {
+				version.TargetSize = &computeBeta.FixedOrPercent{
+					Fixed: int64(targetSize["fixed"].(int)),
+					// allow setting this value to 0
+					ForceSendFields: []string{"Fixed"},
+				}
+			}
+		}
+
+		versions = append(versions, &version)
+	}
+	return versions
+}
+
+func flattenVersions(versions []*computeBeta.InstanceGroupManagerVersion) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(versions))
+	for _, version := range versions {
+		data := map[string]interface{}{
+			"name":              version.Name,
+			"instance_template": ConvertSelfLinkToV1(version.InstanceTemplate),
+		}
+
+		if version.TargetSize != nil {
+			targetSize := map[string]interface{}{}
+			if version.TargetSize.Percent > 0 {
+				targetSize["percent"] = version.TargetSize.Percent
+			} else {
+				targetSize["fixed"] = version.TargetSize.Fixed
+			}
+			data["target_size"] = []map[string]interface{}{targetSize}
+		}
+
+		result = append(result, data)
+	}
+	return result
+}
+
+// usesVersions reports whether the manager's versions amount to more than the
+// single unnamed version the API reports for a plain instance_template.
+func usesVersions(versions []*computeBeta.InstanceGroupManagerVersion) bool {
+	if len(versions) != 1 {
+		return len(versions) > 1
+	}
+	return versions[0].Name != "" || versions[0].TargetSize != nil
+}
+
 func expandUpdatePolicy(configured []interface{}) *computeBeta.InstanceGroupManagerUpdatePolicy {
 	updatePolicy := &computeBeta.InstanceGroupManagerUpdatePolicy{}
 
@@ -14,9 +14,101 @@ import (
 
 	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
 
+func TestValidateInstanceGroupManagerVersions(t *testing.T) {
+	version := func(name string, targetSize map[string]interface{}) interface{} {
+		v := map[string]interface{}{
+			"name":              name,
+			"instance_template": "template",
+			"target_size":       []interface{}{},
+		}
+		if targetSize != nil {
+			v["target_size"] = []interface{}{targetSize}
+		}
+		return v
+	}
+
+	cases := map[string]struct {
+		Versions    []interface{}
+		ExpectError bool
+	}{
+		"single version without target size": {
+			Versions: []interface{}{version("primary", nil)},
+		},
+		"canary with fixed size": {
+			Versions: []interface{}{
+				version("primary", nil),
+				version("canary", map[string]interface{}{"fixed": 1, "percent": 0}),
+			},
+		},
+		"canary with percent": {
+			Versions: []interface{}{
+				version("primary", nil),
+				version("canary", map[string]interface{}{"fixed": 0, "percent": 20}),
+			},
+		},
+		"fixed size of zero": {
+			Versions: []interface{}{
+				version("primary", nil),
+				version("canary", map[string]interface{}{"fixed": 0, "percent": 0}),
+			},
+		},
+		"fixed and percent": {
+			Versions: []interface{}{
+				version("primary", nil),
+				version("canary", map[string]interface{}{"fixed": 1, "percent": 20}),
+			},
+			ExpectError: true,
+		},
+		"several versions without target size": {
+			Versions: []interface{}{
+				version("primary", nil),
+				version("canary", nil),
+			},
+			ExpectError: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		err := validateInstanceGroupManagerVersions(tc.Versions)
+		if (err != nil) != tc.ExpectError {
+			t.Errorf("%s: expected error: %t, got %v", tn, tc.ExpectError, err)
+		}
+	}
+}
+
+func TestInstanceGroupManagerVersionPercentValidation(t *testing.T) {
+	percent := instanceGroupManagerVersionSchema().Elem.(*schema.Resource).Schema["target_size"].Elem.(*schema.Resource).Schema["percent"]
+	if _, errs := percent.ValidateFunc(0, "percent"); len(errs) == 0 {
+		t.Errorf("expected a percent of 0 to be rejected")
+	}
+	if _, errs := percent.ValidateFunc(100, "percent"); len(errs) > 0 {
+		t.Errorf("expected a percent of 100 to be accepted, got %v", errs)
+	}
+}
+
+func TestCountPendingPerInstanceConfigs(t *testing.T) {
+	configs := []interface{}{
+		map[string]interface{}{"name": "applying", "status": "APPLYING"},
+		map[string]interface{}{"name": "deleting", "status": "DELETING"},
+		map[string]interface{}{"name": "effective", "status": "EFFECTIVE"},
+		map[string]interface{}{"name": "none", "status": "NONE"},
+		map[string]interface{}{"name": "unapplied", "status": "UNAPPLIED"},
+		map[string]interface{}{"name": "unapplied-deletion", "status": "UNAPPLIED_DELETION"},
+		map[string]interface{}{"name": "no-status"},
+	}
+
+	if got := countPendingPerInstanceConfigs(configs); got != 2 {
+		t.Errorf("expected 2 pending configs, got %d", got)
+	}
+	if got := countPendingPerInstanceConfigs(nil); got != 0 {
+		t.Errorf("expected no pending configs, got %d", got)
+	}
+}
+
 func TestAccInstanceGroupManager_basic(t *testing.T) {
 	t.Parallel()
 
@@ -299,6 +391,103 @@ func TestAccInstanceGroupManager_autoHealingPolicies(t *testing.T) {
 	})
 }
 
+func TestAccInstanceGroupManager_statefulDisk(t *testing.T) {
+	t.Parallel()
+
+	template := fmt.Sprintf("igm-test-%s", acctest.RandString(10))
+	igm := fmt.Sprintf("igm-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckInstanceGroupManagerDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccInstanceGroupManager_statefulDisk(template, igm, `
+	stateful_disk {
+		device_name = "stateful-disk"
+	}`),
+				Check: resource.TestCheckResourceAttr("google_compute_instance_group_manager.igm-basic", "stateful_disk.#", "1"),
+			},
+			resource.TestStep{
+				Config: testAccInstanceGroupManager_statefulDisk(template, igm, `
+	stateful_disk {
+		device_name = "stateful-disk"
+		delete_rule = "ON_PERMANENT_INSTANCE_DELETION"
+	}
+	stateful_disk {
+		device_name = "stateful-disk2"
+	}`),
+				Check: resource.TestCheckResourceAttr("google_compute_instance_group_manager.igm-basic", "stateful_disk.#", "2"),
+			},
+			resource.TestStep{
+				Config: testAccInstanceGroupManager_statefulDisk(template, igm, ""),
+				Check:  resource.TestCheckResourceAttr("google_compute_instance_group_manager.igm-basic", "stateful_disk.#", "0"),
+			},
+		},
+	})
+}
+
+func TestAccInstanceGroupManager_versions(t *testing.T) {
+	t.Parallel()
+
+	template1 := fmt.Sprintf("igm-test-%s", acctest.RandString(10))
+	template2 := fmt.Sprintf("igm-test-%s", acctest.RandString(10))
+	igm := fmt.Sprintf("igm-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckInstanceGroupManagerDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccInstanceGroupManager_versions(template1, template2, igm, "fixed = 1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("google_compute_instance_group_manager.igm-versions", "version.#", "2"),
+					resource.TestCheckResourceAttr("google_compute_instance_group_manager.igm-versions", "version.1.target_size.0.fixed", "1"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccInstanceGroupManager_versions(template1, template2, igm, "percent = 50"),
+				Check:  resource.TestCheckResourceAttr("google_compute_instance_group_manager.igm-versions", "version.1.target_size.0.percent", "50"),
+			},
+		},
+	})
+}
+
+func testAccInstanceGroupManager_statefulDisk(template, igm, statefulDisks string) string {
+	return fmt.Sprintf(`
+resource "google_compute_instance_template" "igm-basic" {
+	name = "%s"
+	machine_type = "n1-standard-1"
+	disk {
+		source_image = "debian-cloud/debian-9"
+		auto_delete = true
+		boot = true
+		device_name = "stateful-disk"
+	}
+	disk {
+		source_image = "debian-cloud/debian-9"
+		auto_delete = true
+		device_name = "stateful-disk2"
+	}
+	network_interface {
+		network = "default"
+	}
+}
+
+resource "google_compute_instance_group_manager" "igm-basic" {
+	description = "Terraform test instance group manager"
+	name = "%s"
+	instance_template = "${google_compute_instance_template.igm-basic.self_link}"
+	base_instance_name = "igm-basic"
+	zone = "us-central1-c"
+	target_size = 1
+%s
+}
+	`, template, igm, statefulDisks)
+}
+
 // This test is to make sure that a single version resource can link to a versioned resource
 // without perpetual diffs because the self links mismatch.
 // Once auto_healing_policies is no longer beta, we will need to use a new field or resource
@@ -1188,3 +1377,54 @@ resource "google_compute_autoscaler" "foobar" {
 }
 `, template, target, igm, hck, autoscaler)
 }
+
+func testAccInstanceGroupManager_versions(template1, template2, igm, canarySize string) string {
+	return fmt.Sprintf(`
+resource "google_compute_instance_template" "igm-versions-1" {
+	name = "%s"
+	machine_type = "n1-standard-1"
+	disk {
+		source_image = "debian-cloud/debian-9"
+		auto_delete = true
+		boot = true
+	}
+	network_interface {
+		network = "default"
+	}
+}
+
+resource "google_compute_instance_template" "igm-versions-2" {
+	name = "%s"
+	machine_type = "n1-standard-1"
+	tags = ["canary"]
+	disk {
+		source_image = "debian-cloud/debian-9"
+		auto_delete = true
+		boot = true
+	}
+	network_interface {
+		network = "default"
+	}
+}
+
+resource "google_compute_instance_group_manager" "igm-versions" {
+	name = "%s"
+	base_instance_name = "igm-versions"
+	zone = "us-central1-c"
+	target_size = 4
+
+	version {
+		name = "primary"
+		instance_template = "${google_compute_instance_template.igm-versions-1.self_link}"
+	}
+
+	version {
+		name = "canary"
+		instance_template = "${google_compute_instance_template.igm-versions-2.self_link}"
+		target_size {
+			%s
+		}
+	}
+}
+	`, template1, template2, igm, canarySize)
+}
@@ -141,7 +141,7 @@ func TestAccComputeInstanceMigrateState_bootDisk(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error creating instance: %s", err)
 	}
-	waitErr := computeSharedOperationWait(config.clientCompute, op, config.Project, "instance to create")
+	waitErr := computeSharedOperationWaitConfig(config, op, config.Project, "instance to create")
 	if waitErr != nil {
 		t.Fatal(waitErr)
 	}
@@ -208,7 +208,7 @@ func TestAccComputeInstanceMigrateState_v4FixBootDisk(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error creating instance: %s", err)
 	}
-	waitErr := computeSharedOperationWait(config.clientCompute, op, config.Project, "instance to create")
+	waitErr := computeSharedOperationWaitConfig(config, op, config.Project, "instance to create")
 	if waitErr != nil {
 		t.Fatal(waitErr)
 	}
@@ -261,7 +261,7 @@ func TestAccComputeInstanceMigrateState_attachedDiskFromSource(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error creating disk: %s", err)
 	}
-	waitErr := computeSharedOperationWait(config.clientCompute, op, config.Project, "disk to create")
+	waitErr := computeSharedOperationWaitConfig(config, op, config.Project, "disk to create")
 	if waitErr != nil {
 		t.Fatal(waitErr)
 	}
@@ -292,7 +292,7 @@ func TestAccComputeInstanceMigrateState_attachedDiskFromSource(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error creating instance: %s", err)
 	}
-	waitErr = computeSharedOperationWait(config.clientCompute, op, config.Project, "instance to create")
+	waitErr = computeSharedOperationWaitConfig(config, op, config.Project, "instance to create")
 	if waitErr != nil {
 		t.Fatal(waitErr)
 	}
@@ -341,7 +341,7 @@ func TestAccComputeInstanceMigrateState_v4FixAttachedDiskFromSource(t *testing.T
 	if err != nil {
 		t.Fatalf("Error creating disk: %s", err)
 	}
-	waitErr := computeSharedOperationWait(config.clientCompute, op, config.Project, "disk to create")
+	waitErr := computeSharedOperationWaitConfig(config, op, config.Project, "disk to create")
 	if waitErr != nil {
 		t.Fatal(waitErr)
 	}
@@ -372,7 +372,7 @@ func TestAccComputeInstanceMigrateState_v4FixAttachedDiskFromSource(t *testing.T
 	if err != nil {
 		t.Fatalf("Error creating instance: %s", err)
 	}
-	waitErr = computeSharedOperationWait(config.clientCompute, op, config.Project, "instance to create")
+	waitErr = computeSharedOperationWaitConfig(config, op, config.Project, "instance to create")
 	if waitErr != nil {
 		t.Fatal(waitErr)
 	}
@@ -440,7 +440,7 @@ func TestAccComputeInstanceMigrateState_attachedDiskFromEncryptionKey(t *testing
 	if err != nil {
 		t.Fatalf("Error creating instance: %s", err)
 	}
-	waitErr := computeSharedOperationWait(config.clientCompute, op, config.Project, "instance to create")
+	waitErr := computeSharedOperationWaitConfig(config, op, config.Project, "instance to create")
 	if waitErr != nil {
 		t.Fatal(waitErr)
 	}
@@ -508,7 +508,7 @@ func TestAccComputeInstanceMigrateState_v4FixAttachedDiskFromEncryptionKey(t *te
 	if err != nil {
 		t.Fatalf("Error creating instance: %s", err)
 	}
-	waitErr := computeSharedOperationWait(config.clientCompute, op, config.Project, "instance to create")
+	waitErr := computeSharedOperationWaitConfig(config, op, config.Project, "instance to create")
 	if waitErr != nil {
 		t.Fatal(waitErr)
 	}
@@ -578,7 +578,7 @@ func TestAccComputeInstanceMigrateState_attachedDiskFromAutoDeleteAndImage(t *te
 	if err != nil {
 		t.Fatalf("Error creating instance: %s", err)
 	}
-	waitErr := computeSharedOperationWait(config.clientCompute, op, config.Project, "instance to create")
+	waitErr := computeSharedOperationWaitConfig(config, op, config.Project, "instance to create")
 	if waitErr != nil {
 		t.Fatal(waitErr)
 	}
@@ -650,7 +650,7 @@ func TestAccComputeInstanceMigrateState_v4FixAttachedDiskFromAutoDeleteAndImage(
 	if err != nil {
 		t.Fatalf("Error creating instance: %s", err)
 	}
-	waitErr := computeSharedOperationWait(config.clientCompute, op, config.Project, "instance to create")
+	waitErr := computeSharedOperationWaitConfig(config, op, config.Project, "instance to create")
 	if waitErr != nil {
 		t.Fatal(waitErr)
 	}
@@ -717,7 +717,7 @@ func TestAccComputeInstanceMigrateState_scratchDisk(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error creating instance: %s", err)
 	}
-	waitErr := computeSharedOperationWait(config.clientCompute, op, config.Project, "instance to create")
+	waitErr := computeSharedOperationWaitConfig(config, op, config.Project, "instance to create")
 	if waitErr != nil {
 		t.Fatal(waitErr)
 	}
@@ -781,7 +781,7 @@ func TestAccComputeInstanceMigrateState_v4FixScratchDisk(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error creating instance: %s", err)
 	}
-	waitErr := computeSharedOperationWait(config.clientCompute, op, config.Project, "instance to create")
+	waitErr := computeSharedOperationWaitConfig(config, op, config.Project, "instance to create")
 	if waitErr != nil {
 		t.Fatal(waitErr)
 	}
@@ -840,7 +840,7 @@ func cleanUpInstance(config *Config, instanceName, zone string) {
 	}
 
 	// Wait for the operation to complete
-	opErr := computeOperationWait(config.clientCompute, op, config.Project, "instance to delete")
+	opErr := computeOperationWaitConfig(config, op, config.Project, "instance to delete")
 	if opErr != nil {
 		log.Printf("[WARNING] Error deleting instance %q, dangling resources may exist: %s", instanceName, opErr)
 	}
@@ -854,7 +854,7 @@ func cleanUpDisk(config *Config, diskName, zone string) {
 	}
 
 	// Wait for the operation to complete
-	opErr := computeOperationWait(config.clientCompute, op, config.Project, "disk to delete")
+	opErr := computeOperationWaitConfig(config, op, config.Project, "disk to delete")
 	if opErr != nil {
 		log.Printf("[WARNING] Error deleting disk %q, dangling resources may exist: %s", diskName, opErr)
 	}
@@ -11,7 +11,11 @@ import (
 )
 
 var InstanceTemplateBaseApiVersion = v1
-var InstanceTemplateVersionedFeatures = []Feature{}
+var InstanceTemplateVersionedFeatures = []Feature{
+	Feature{Version: v0beta, Item: "shielded_instance_config"},
+	Feature{Version: v0beta, Item: "scheduling.*.node_affinities"},
+	Feature{Version: v0beta, Item: "disk.*.disk_encryption_key"},
+}
 
 func resourceComputeInstanceTemplate() *schema.Resource {
 	return &schema.Resource{
@@ -24,6 +28,8 @@ func resourceComputeInstanceTemplate() *schema.Resource {
 		SchemaVersion: 1,
 		MigrateState:  resourceComputeInstanceTemplateMigrateState,
 
+		CustomizeDiff: customizeDiffEffectiveLabels("labels"),
+
 		// A compute instance template is more or less a subset of a compute
 		// instance. Please attempt to maintain consistency with the
 		// resource_compute_instance schema when updating this one.
@@ -132,6 +138,23 @@ func resourceComputeInstanceTemplate() *schema.Resource {
 							ForceNew: true,
 							Computed: true,
 						},
+
+						"disk_encryption_key": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							ForceNew: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"kms_key_self_link": &schema.Schema{
+										Type:         schema.TypeString,
+										Required:     true,
+										ForceNew:     true,
+										ValidateFunc: validateKmsCryptoKeyId,
+									},
+								},
+							},
+						},
 					},
 				},
 			},
@@ -324,6 +347,8 @@ func resourceComputeInstanceTemplate() *schema.Resource {
 							Computed: true,
 							ForceNew: true,
 						},
+
+						"node_affinities": nodeAffinitiesSchema(),
 					},
 				},
 			},
@@ -410,6 +435,10 @@ func resourceComputeInstanceTemplate() *schema.Resource {
 				Elem:     &schema.Schema{Type: schema.TypeString},
 				Set:      schema.HashString,
 			},
+
+			"effective_labels": forceNewEffectiveLabelsSchema(),
+
+			"shielded_instance_config": shieldedInstanceConfigSchema(true),
 		},
 	}
 }
@@ -592,9 +621,7 @@ func resourceComputeInstanceTemplateCreate(d *schema.ResourceData, meta interfac
 	instanceProperties.GuestAccelerators = expandInstanceTemplateGuestAccelerators(d, config)
 
 	instanceProperties.Tags = resourceInstanceTags(d)
-	if _, ok := d.GetOk("labels"); ok {
-		instanceProperties.Labels = expandLabels(d)
-	}
+	instanceProperties.Labels = expandEffectiveLabels(d, config, "labels")
 
 	var itName string
 	if v, ok := d.GetOk("name"); ok {
@@ -611,7 +638,7 @@ func resourceComputeInstanceTemplateCreate(d *schema.ResourceData, meta interfac
 	}
 
 	var op interface{}
-	switch getComputeApiVersion(d, InstanceTemplateBaseApiVersion, InstanceGroupManagerVersionedFeatures) {
+	switch getComputeApiVersion(d, InstanceTemplateBaseApiVersion, InstanceTemplateVersionedFeatures) {
 	case v1:
 		instanceTemplateV1 := &compute.InstanceTemplate{}
 		if err := Convert(instanceTemplate, instanceTemplateV1); err != nil {
@@ -619,7 +646,29 @@ func resourceComputeInstanceTemplateCreate(d *schema.ResourceData, meta interfac
 		}
 		op, err = config.clientCompute.InstanceTemplates.Insert(project, instanceTemplateV1).Do()
 	case v0beta:
-		op, err = config.clientComputeBeta.InstanceTemplates.Insert(project, instanceTemplate).Do()
+		rawFields := make(map[string]interface{})
+		if shieldedInstanceConfig := expandShieldedInstanceConfig(d); shieldedInstanceConfig != nil {
+			rawFields["properties.shieldedInstanceConfig"] = shieldedInstanceConfig
+		}
+		if nodeAffinities := expandNodeAffinities(d); nodeAffinities != nil {
+			rawFields["properties.scheduling.nodeAffinities"] = nodeAffinities
+		}
+		for i := 0; i < d.Get("disk.#").(int); i++ {
+			kmsKeyName, err := expandKmsKeyName(d.Get(fmt.Sprintf("disk.%d.disk_encryption_key.0.kms_key_self_link", i)), config)
+			if err != nil {
+				return err
+			}
+			if kmsKeyName != "" {
+				rawFields[fmt.Sprintf("properties.disks.%d.diskEncryptionKey.kmsKeyName", i)] = kmsKeyName
+			}
+		}
+
+		if len(rawFields) > 0 {
+			url := fmt.Sprintf("https://www.googleapis.com/compute/beta/projects/%s/global/instanceTemplates", project)
+			op, err = postWithRawFields(config, url, instanceTemplate, rawFields)
+		} else {
+			op, err = config.clientComputeBeta.InstanceTemplates.Insert(project, instanceTemplate).Do()
+		}
 	}
 	if err != nil {
 		return fmt.Errorf("Error creating instance template: %s", err)
@@ -628,7 +677,7 @@ func resourceComputeInstanceTemplateCreate(d *schema.ResourceData, meta interfac
 	// Store the ID now
 	d.SetId(instanceTemplate.Name)
 
-	err = computeSharedOperationWait(config.clientCompute, op, project, "Creating Instance Template")
+	err = computeSharedOperationWaitConfig(config, op, project, "Creating Instance Template")
 	if err != nil {
 		return err
 	}
@@ -636,7 +685,7 @@ func resourceComputeInstanceTemplateCreate(d *schema.ResourceData, meta interfac
 	return resourceComputeInstanceTemplateRead(d, meta)
 }
 
-func flattenDisks(disks []*computeBeta.AttachedDisk, d *schema.ResourceData) []map[string]interface{} {
+func flattenDisks(disks []*computeBeta.AttachedDisk, rawDisks []interface{}, d *schema.ResourceData, config *Config) []map[string]interface{} {
 	result := make([]map[string]interface{}, 0, len(disks))
 	for i, disk := range disks {
 		diskMap := make(map[string]interface{})
@@ -658,6 +707,19 @@ func flattenDisks(disks []*computeBeta.AttachedDisk, d *schema.ResourceData) []m
 		diskMap["source"] = disk.Source
 		diskMap["mode"] = disk.Mode
 		diskMap["type"] = disk.Type
+		// Cloud KMS keys are read from the raw disks, which are in the same
+		// order.
+		if i < len(rawDisks) {
+			rawDisk, _ := rawDisks[i].(map[string]interface{})
+			if key, ok := rawDisk["diskEncryptionKey"].(map[string]interface{}); ok && key["kmsKeyName"] != nil {
+				kmsKeySelfLink := d.Get(fmt.Sprintf("disk.%d.disk_encryption_key.0.kms_key_self_link", i)).(string)
+				diskMap["disk_encryption_key"] = []map[string]interface{}{
+					{
+						"kms_key_self_link": flattenKmsKeySelfLink(kmsKeySelfLink, key["kmsKeyName"], config),
+					},
+				}
+			}
+		}
 		result = append(result, diskMap)
 	}
 	return result
@@ -671,7 +733,8 @@ func resourceComputeInstanceTemplateRead(d *schema.ResourceData, meta interface{
 	}
 
 	instanceTemplate := &computeBeta.InstanceTemplate{}
-	switch getComputeApiVersion(d, InstanceBaseApiVersion, InstanceVersionedFeatures) {
+	var rawInstanceTemplate map[string]interface{}
+	switch getComputeApiVersion(d, InstanceTemplateBaseApiVersion, InstanceTemplateVersionedFeatures) {
 	case v1:
 		instanceTemplateV1, err := config.clientCompute.InstanceTemplates.Get(project, d.Id()).Do()
 		if err != nil {
@@ -681,11 +744,13 @@ func resourceComputeInstanceTemplateRead(d *schema.ResourceData, meta interface{
 			return err
 		}
 	case v0beta:
-		var err error
-		instanceTemplate, err = config.clientComputeBeta.InstanceTemplates.Get(project, d.Id()).Do()
+		rawInstanceTemplate, err = Get(config, instanceTemplateBetaUrl(project, d.Id()))
 		if err != nil {
 			return handleNotFoundError(err, d, fmt.Sprintf("Instance Template %q", d.Get("name").(string)))
 		}
+		if err := Convert(rawInstanceTemplate, instanceTemplate); err != nil {
+			return err
+		}
 	}
 
 	// Set the metadata fingerprint if there is one.
@@ -715,17 +780,23 @@ func resourceComputeInstanceTemplateRead(d *schema.ResourceData, meta interface{
 			return fmt.Errorf("Error setting tags_fingerprint: %s", err)
 		}
 	}
-	if instanceTemplate.Properties.Labels != nil {
-		d.Set("labels", instanceTemplate.Properties.Labels)
+	if err := setEffectiveLabels(d, config, "labels", instanceTemplate.Properties.Labels); err != nil {
+		return fmt.Errorf("Error setting labels: %s", err)
+	}
+
+	properties, _ := rawInstanceTemplate["properties"].(map[string]interface{})
+	rawDisks, _ := properties["disks"].([]interface{})
+	if err = d.Set("shielded_instance_config", flattenShieldedInstanceConfig(properties["shieldedInstanceConfig"])); err != nil {
+		return fmt.Errorf("Error setting shielded_instance_config: %s", err)
 	}
-	if err = d.Set("self_link", instanceTemplate.SelfLink); err != nil {
+	if err = d.Set("self_link", ConvertSelfLinkToV1(instanceTemplate.SelfLink)); err != nil {
 		return fmt.Errorf("Error setting self_link: %s", err)
 	}
 	if err = d.Set("name", instanceTemplate.Name); err != nil {
 		return fmt.Errorf("Error setting name: %s", err)
 	}
 	if instanceTemplate.Properties.Disks != nil {
-		if err = d.Set("disk", flattenDisks(instanceTemplate.Properties.Disks, d)); err != nil {
+		if err = d.Set("disk", flattenDisks(instanceTemplate.Properties.Disks, rawDisks, d, config)); err != nil {
 			return fmt.Errorf("Error setting disk: %s", err)
 		}
 	}
@@ -765,7 +836,8 @@ func resourceComputeInstanceTemplateRead(d *schema.ResourceData, meta interface{
 		}
 	}
 	if instanceTemplate.Properties.Scheduling != nil {
-		scheduling := flattenScheduling(instanceTemplate.Properties.Scheduling)
+		rawScheduling, _ := properties["scheduling"].(map[string]interface{})
+		scheduling := flattenScheduling(instanceTemplate.Properties.Scheduling, rawScheduling["nodeAffinities"])
 		if err = d.Set("scheduling", scheduling); err != nil {
 			return fmt.Errorf("Error setting scheduling: %s", err)
 		}
@@ -802,7 +874,7 @@ func resourceComputeInstanceTemplateDelete(d *schema.ResourceData, meta interfac
 		return fmt.Errorf("Error deleting instance template: %s", err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Deleting Instance Template")
+	err = computeOperationWaitConfig(config, op, project, "Deleting Instance Template")
 	if err != nil {
 		return err
 	}
@@ -415,6 +415,93 @@ func TestAccComputeInstanceTemplate_minCpuPlatform(t *testing.T) {
 	})
 }
 
+func TestAccComputeInstanceTemplate_shieldedInstanceConfig(t *testing.T) {
+	t.Parallel()
+
+	var instanceTemplate compute.InstanceTemplate
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceTemplateDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeInstanceTemplate_shieldedInstanceConfig(acctest.RandString(10)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceTemplateExists("google_compute_instance_template.foobar", &instanceTemplate),
+					resource.TestCheckResourceAttr("google_compute_instance_template.foobar", "shielded_instance_config.0.enable_secure_boot", "true"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_instance_template.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Beta fields are only read once they are in the config.
+				ImportStateVerifyIgnore: []string{"shielded_instance_config"},
+			},
+		},
+	})
+}
+
+func TestAccComputeInstanceTemplate_soleTenantNodeAffinities(t *testing.T) {
+	t.Parallel()
+
+	var instanceTemplate compute.InstanceTemplate
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceTemplateDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeInstanceTemplate_soleTenantNodeAffinities(acctest.RandString(10)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceTemplateExists("google_compute_instance_template.foobar", &instanceTemplate),
+					resource.TestCheckResourceAttr("google_compute_instance_template.foobar", "scheduling.0.node_affinities.#", "2"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_instance_template.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Beta fields are only read once they are in the config.
+				ImportStateVerifyIgnore: []string{"scheduling.0.node_affinities"},
+			},
+		},
+	})
+}
+
+func TestAccComputeInstanceTemplate_diskEncryptionKms(t *testing.T) {
+	t.Parallel()
+
+	var instanceTemplate compute.InstanceTemplate
+	keyRingName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	cryptoKeyName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceTemplateDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeInstanceTemplate_diskEncryptionKms(keyRingName, cryptoKeyName, acctest.RandString(10)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceTemplateExists("google_compute_instance_template.foobar", &instanceTemplate),
+					resource.TestCheckResourceAttrPair("google_compute_instance_template.foobar", "disk.0.disk_encryption_key.0.kms_key_self_link",
+						"google_kms_crypto_key.foobar", "id"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_instance_template.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Beta fields are only read once they are in the config.
+				ImportStateVerifyIgnore: []string{"disk.0.disk_encryption_key"},
+			},
+		},
+	})
+}
+
 func testAccCheckComputeInstanceTemplateDestroy(s *terraform.State) error {
 	config := testAccProvider.Meta().(*Config)
 
@@ -1173,3 +1260,82 @@ resource "google_compute_instance_template" "foobar" {
 	min_cpu_platform = "%s"
 }`, i, DEFAULT_MIN_CPU_TEST_VALUE)
 }
+
+func testAccComputeInstanceTemplate_shieldedInstanceConfig(i string) string {
+	return fmt.Sprintf(`
+resource "google_compute_instance_template" "foobar" {
+	name = "instance-test-%s"
+	machine_type = "n1-standard-1"
+
+	disk {
+		source_image = "gce-uefi-images/centos-7"
+		auto_delete = true
+		disk_size_gb = 10
+		boot = true
+	}
+
+	network_interface {
+		network = "default"
+	}
+
+	shielded_instance_config {
+		enable_secure_boot = true
+		enable_vtpm = true
+		enable_integrity_monitoring = true
+	}
+}`, i)
+}
+
+func testAccComputeInstanceTemplate_soleTenantNodeAffinities(i string) string {
+	return fmt.Sprintf(`
+resource "google_compute_instance_template" "foobar" {
+	name = "instance-test-%s"
+	machine_type = "n1-standard-1"
+
+	disk {
+		source_image = "debian-cloud/debian-9"
+		auto_delete = true
+		boot = true
+	}
+
+	network_interface {
+		network = "default"
+	}
+
+	scheduling {
+		node_affinities {
+			key = "compute.googleapis.com/node-group-name"
+			operator = "IN"
+			values = ["sole-tenant-group"]
+		}
+
+		node_affinities {
+			key = "workload"
+			operator = "NOT_IN"
+			values = ["batch"]
+		}
+	}
+}`, i)
+}
+
+func testAccComputeInstanceTemplate_diskEncryptionKms(keyRingName, cryptoKeyName, i string) string {
+	return testAccComputeKmsKey(keyRingName, cryptoKeyName) + fmt.Sprintf(`
+resource "google_compute_instance_template" "foobar" {
+	name = "instance-test-%s"
+	machine_type = "n1-standard-1"
+
+	disk {
+		source_image = "debian-cloud/debian-9"
+		auto_delete = true
+		boot = true
+
+		disk_encryption_key {
+			kms_key_self_link = "${google_kms_crypto_key.foobar.id}"
+		}
+	}
+
+	network_interface {
+		network = "default"
+	}
+}`, i)
+}
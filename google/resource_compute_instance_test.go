@@ -7,12 +7,64 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"google.golang.org/api/compute/v1"
 )
 
+func TestComputeInstanceDiff_attachedDiskKmsKey(t *testing.T) {
+	t.Parallel()
+
+	state := &terraform.InstanceState{
+		ID: "instance-1",
+		Attributes: map[string]string{
+			"name":                              "instance-1",
+			"machine_type":                      "n1-standard-1",
+			"zone":                              "us-central1-a",
+			"attached_disk.#":                   "1",
+			"attached_disk.0.source":            "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/disks/disk-1",
+			"attached_disk.0.mode":              "READ_WRITE",
+			"attached_disk.0.kms_key_self_link": "us-central1/my-key-ring/my-key",
+			"attached_disk.0.disk_encryption_key_raw": "",
+		},
+	}
+
+	cases := map[string]struct {
+		Source      string
+		KmsKey      string
+		ExpectError bool
+	}{
+		"same key":           {Source: "disk-1", KmsKey: "us-central1/my-key-ring/my-key"},
+		"same key, new form": {Source: "disk-1", KmsKey: "projects/my-project/locations/us-central1/keyRings/my-key-ring/cryptoKeys/my-key"},
+		"different key":      {Source: "disk-1", KmsKey: "us-central1/my-key-ring/other-key", ExpectError: true},
+		"different disk":     {Source: "disk-2", KmsKey: "us-central1/my-key-ring/other-key"},
+	}
+
+	for tn, tc := range cases {
+		rc, err := config.NewRawConfig(map[string]interface{}{
+			"name":         "instance-1",
+			"machine_type": "n1-standard-1",
+			"zone":         "us-central1-a",
+			"attached_disk": []interface{}{
+				map[string]interface{}{
+					"source":            tc.Source,
+					"kms_key_self_link": tc.KmsKey,
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("%s: bad config: %s", tn, err)
+		}
+
+		_, err = resourceComputeInstance().Diff(state, terraform.NewResourceConfig(rc), &Config{Project: "my-project"})
+		if (err != nil) != tc.ExpectError {
+			t.Errorf("%s: expected error: %t, got %v", tn, tc.ExpectError, err)
+		}
+	}
+}
+
 func computeInstanceImportStep(zone, instanceName string, additionalImportIgnores []string) resource.TestStep {
 	// create_timeout has a default value, but it's deprecated so don't worry about it
 	// metadata is only read into state if set in the config
@@ -282,6 +334,35 @@ func TestAccComputeInstance_diskEncryption(t *testing.T) {
 	})
 }
 
+func TestAccComputeInstance_diskEncryptionKms(t *testing.T) {
+	t.Parallel()
+
+	var instance compute.Instance
+	var instanceName = fmt.Sprintf("instance-test-%s", acctest.RandString(10))
+	var diskName = fmt.Sprintf("instance-testd-%s", acctest.RandString(10))
+	keyRingName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	cryptoKeyName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeInstance_disks_encryptionKms(keyRingName, cryptoKeyName, diskName, instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(
+						"google_compute_instance.foobar", &instance),
+					resource.TestCheckResourceAttrPair("google_compute_instance.foobar", "boot_disk.0.kms_key_self_link",
+						"google_kms_crypto_key.foobar", "id"),
+					resource.TestCheckResourceAttrPair("google_compute_instance.foobar", "attached_disk.0.kms_key_self_link",
+						"google_kms_crypto_key.foobar", "id"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccComputeInstance_attachedDisk(t *testing.T) {
 	t.Parallel()
 
@@ -931,6 +1012,50 @@ func TestAccComputeInstance_minCpuPlatform(t *testing.T) {
 	})
 }
 
+func TestAccComputeInstance_shieldedInstanceConfig(t *testing.T) {
+	t.Parallel()
+
+	var instance compute.Instance
+	instanceName := fmt.Sprintf("terraform-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeInstance_shieldedInstanceConfig(instanceName, true, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists("google_compute_instance.foobar", &instance),
+					resource.TestCheckResourceAttr("google_compute_instance.foobar", "shielded_instance_config.0.enable_secure_boot", "true"),
+				),
+			},
+			computeInstanceImportStep("us-central1-a", instanceName, []string{"allow_stopping_for_update"}),
+			resource.TestStep{
+				// Turning flags off while another stays on has to send them
+				// as false rather than leave them out of the update.
+				Config: testAccComputeInstance_shieldedInstanceConfig(instanceName, true, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists("google_compute_instance.foobar", &instance),
+					resource.TestCheckResourceAttr("google_compute_instance.foobar", "shielded_instance_config.0.enable_secure_boot", "true"),
+					resource.TestCheckResourceAttr("google_compute_instance.foobar", "shielded_instance_config.0.enable_vtpm", "false"),
+					resource.TestCheckResourceAttr("google_compute_instance.foobar", "shielded_instance_config.0.enable_integrity_monitoring", "false"),
+				),
+			},
+			computeInstanceImportStep("us-central1-a", instanceName, []string{"allow_stopping_for_update"}),
+			resource.TestStep{
+				Config: testAccComputeInstance_shieldedInstanceConfig(instanceName, false, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists("google_compute_instance.foobar", &instance),
+					resource.TestCheckResourceAttr("google_compute_instance.foobar", "shielded_instance_config.0.enable_secure_boot", "false"),
+					resource.TestCheckResourceAttr("google_compute_instance.foobar", "shielded_instance_config.0.enable_vtpm", "false"),
+				),
+			},
+			computeInstanceImportStep("us-central1-a", instanceName, []string{"allow_stopping_for_update"}),
+		},
+	})
+}
+
 func TestAccComputeInstance_deletionProtectionExplicitFalse(t *testing.T) {
 	t.Parallel()
 
@@ -1060,7 +1185,7 @@ func testAccCheckComputeInstanceUpdateMachineType(n string) resource.TestCheckFu
 		if err != nil {
 			return fmt.Errorf("Could not stop instance: %s", err)
 		}
-		err = computeOperationWait(config.clientCompute, op, config.Project, "Waiting on stop")
+		err = computeOperationWaitConfig(config, op, config.Project, "Waiting on stop")
 		if err != nil {
 			return fmt.Errorf("Could not stop instance: %s", err)
 		}
@@ -1074,7 +1199,7 @@ func testAccCheckComputeInstanceUpdateMachineType(n string) resource.TestCheckFu
 		if err != nil {
 			return fmt.Errorf("Could not change machine type: %s", err)
 		}
-		err = computeOperationWait(config.clientCompute, op, config.Project, "Waiting machine type change")
+		err = computeOperationWaitConfig(config, op, config.Project, "Waiting machine type change")
 		if err != nil {
 			return fmt.Errorf("Could not change machine type: %s", err)
 		}
@@ -1942,6 +2067,47 @@ resource "google_compute_instance" "foobar" {
 		diskNameToEncryptionKey[diskNames[0]].RawKey, diskNameToEncryptionKey[diskNames[1]].RawKey, diskNameToEncryptionKey[diskNames[2]].RawKey)
 }
 
+func testAccComputeInstance_disks_encryptionKms(keyRingName, cryptoKeyName, diskName, instance string) string {
+	return testAccComputeKmsKey(keyRingName, cryptoKeyName) + fmt.Sprintf(`
+resource "google_compute_disk" "foobar" {
+	name = "%s"
+	size = 10
+	type = "pd-ssd"
+	zone = "us-central1-a"
+
+	disk_encryption_key {
+		kms_key_self_link = "${google_kms_crypto_key.foobar.id}"
+	}
+
+	depends_on = ["google_kms_crypto_key_iam_member.foobar"]
+}
+
+resource "google_compute_instance" "foobar" {
+	name         = "%s"
+	machine_type = "n1-standard-1"
+	zone         = "us-central1-a"
+
+	boot_disk {
+		initialize_params {
+			image = "debian-8-jessie-v20160803"
+		}
+		kms_key_self_link = "${google_kms_crypto_key.foobar.id}"
+	}
+
+	attached_disk {
+		source            = "${google_compute_disk.foobar.self_link}"
+		kms_key_self_link = "${google_kms_crypto_key.foobar.id}"
+	}
+
+	network_interface {
+		network = "default"
+	}
+
+	depends_on = ["google_kms_crypto_key_iam_member.foobar"]
+}
+`, diskName, instance)
+}
+
 func testAccComputeInstance_attachedDisk(disk, instance string) string {
 	return fmt.Sprintf(`
 resource "google_compute_disk" "foobar" {
@@ -2619,6 +2785,33 @@ resource "google_compute_instance" "foobar" {
 }`, instance)
 }
 
+func testAccComputeInstance_shieldedInstanceConfig(instance string, enableSecureBoot, enableVtpm bool) string {
+	return fmt.Sprintf(`
+resource "google_compute_instance" "foobar" {
+  name = "%s"
+  machine_type = "n1-standard-1"
+  zone = "us-central1-a"
+
+  boot_disk {
+    initialize_params {
+      image = "gce-uefi-images/centos-7"
+    }
+  }
+
+  network_interface {
+    network = "default"
+  }
+
+  shielded_instance_config {
+    enable_secure_boot          = %t
+    enable_vtpm                 = %t
+    enable_integrity_monitoring = %t
+  }
+
+  allow_stopping_for_update = true
+}`, instance, enableSecureBoot, enableVtpm, enableVtpm)
+}
+
 func testAccComputeInstance_primaryAliasIpRange(instance string) string {
 	return fmt.Sprintf(`
 resource "google_compute_instance" "foobar" {
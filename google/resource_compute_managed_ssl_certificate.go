@@ -0,0 +1,257 @@
+package google
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	compute "google.golang.org/api/compute/v1"
+)
+
+func resourceComputeManagedSslCertificate() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceComputeManagedSslCertificateCreate,
+		Read:   resourceComputeManagedSslCertificateRead,
+		Delete: resourceComputeManagedSslCertificateDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: resourceComputeManagedSslCertificateImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			// Includes waiting for the certificate to start provisioning.
+			Create: schema.DefaultTimeout(6 * time.Minute),
+			Delete: schema.DefaultTimeout(240 * time.Second),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateGCPName,
+			},
+			"domains": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"certificate_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"creation_timestamp": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"subject_alternative_names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"expire_time": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"self_link": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceComputeManagedSslCertificateCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	obj := map[string]interface{}{
+		"name":        d.Get("name"),
+		"description": d.Get("description"),
+		"type":        "MANAGED",
+		"managed": map[string]interface{}{
+			"domains": d.Get("domains"),
+		},
+	}
+
+	url, err := replaceVars(d, config, "https://www.googleapis.com/compute/beta/projects/{{project}}/global/sslCertificates")
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Creating new ManagedSslCertificate: %#v", obj)
+	res, err := Post(config, url, obj)
+	if err != nil {
+		return fmt.Errorf("Error creating ManagedSslCertificate: %s", err)
+	}
+
+	// Store the ID now
+	id, err := replaceVars(d, config, "{{name}}")
+	if err != nil {
+		return fmt.Errorf("Error constructing id: %s", err)
+	}
+	d.SetId(id)
+
+	op := &compute.Operation{}
+	err = Convert(res, op)
+	if err != nil {
+		return err
+	}
+
+	waitErr := computeOperationWaitTimeConfig(
+		config, op, project, "Creating ManagedSslCertificate",
+		int(d.Timeout(schema.TimeoutCreate).Minutes()))
+
+	if waitErr != nil {
+		// The resource didn't actually create
+		d.SetId("")
+		return waitErr
+	}
+
+	// The certificate only provisions once it's served by a load balancer,
+	// so this waits for provisioning to start rather than finish.
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"", "MANAGED_CERTIFICATE_STATUS_UNSPECIFIED"},
+		Target:     []string{"ACTIVE", "PROVISIONING"},
+		Refresh:    managedSslCertificateStatusRefreshFunc(config, d),
+		Timeout:    d.Timeout(schema.TimeoutCreate),
+		MinTimeout: 2 * time.Second,
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for ManagedSslCertificate %q to provision: %s", d.Id(), err)
+	}
+
+	return resourceComputeManagedSslCertificateRead(d, meta)
+}
+
+func managedSslCertificateStatusRefreshFunc(config *Config, d *schema.ResourceData) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		url, err := replaceVars(d, config, "https://www.googleapis.com/compute/beta/projects/{{project}}/global/sslCertificates/{{name}}")
+		if err != nil {
+			return nil, "", err
+		}
+
+		res, err := Get(config, url)
+		if err != nil {
+			return nil, "", err
+		}
+
+		managed, _ := res["managed"].(map[string]interface{})
+		status, _ := managed["status"].(string)
+		log.Printf("[DEBUG] ManagedSslCertificate %q status: %s", d.Id(), status)
+		return res, status, nil
+	}
+}
+
+func resourceComputeManagedSslCertificateRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	url, err := replaceVars(d, config, "https://www.googleapis.com/compute/beta/projects/{{project}}/global/sslCertificates/{{name}}")
+	if err != nil {
+		return err
+	}
+
+	res, err := Get(config, url)
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("ComputeManagedSslCertificate %q", d.Id()))
+	}
+
+	managed, _ := res["managed"].(map[string]interface{})
+	d.Set("name", res["name"])
+	d.Set("description", res["description"])
+	d.Set("domains", managed["domains"])
+	d.Set("certificate_id", flattenComputeManagedSslCertificateCertificateId(res["id"]))
+	d.Set("creation_timestamp", res["creationTimestamp"])
+	d.Set("subject_alternative_names", res["subjectAlternativeNames"])
+	d.Set("expire_time", res["expireTime"])
+	d.Set("self_link", ConvertSelfLinkToV1(res["selfLink"].(string)))
+	d.Set("project", project)
+
+	return nil
+}
+
+func resourceComputeManagedSslCertificateDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	url, err := replaceVars(d, config, "https://www.googleapis.com/compute/beta/projects/{{project}}/global/sslCertificates/{{name}}")
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Deleting ManagedSslCertificate %q", d.Id())
+	res, err := Delete(config, url)
+	if err != nil {
+		return fmt.Errorf("Error deleting ManagedSslCertificate %q: %s", d.Id(), err)
+	}
+
+	op := &compute.Operation{}
+	err = Convert(res, op)
+	if err != nil {
+		return err
+	}
+
+	err = computeOperationWaitTimeConfig(
+		config, op, project, "Deleting ManagedSslCertificate",
+		int(d.Timeout(schema.TimeoutDelete).Minutes()))
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func resourceComputeManagedSslCertificateImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	config := meta.(*Config)
+	parseImportId([]string{"projects/(?P<project>[^/]+)/global/sslCertificates/(?P<name>[^/]+)", "(?P<project>[^/]+)/(?P<name>[^/]+)", "(?P<name>[^/]+)"}, d, config)
+
+	// Replace import id for the resource id
+	id, err := replaceVars(d, config, "{{name}}")
+	if err != nil {
+		return nil, fmt.Errorf("Error constructing id: %s", err)
+	}
+	d.SetId(id)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// The API sends the uint64 id as a string to keep its precision.
+func flattenComputeManagedSslCertificateCertificateId(v interface{}) interface{} {
+	switch id := v.(type) {
+	case string:
+		return id
+	case float64:
+		return strconv.FormatUint(uint64(id), 10)
+	}
+	return v
+}
@@ -0,0 +1,106 @@
+package google
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccComputeManagedSslCertificate_basic(t *testing.T) {
+	t.Parallel()
+
+	id := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeManagedSslCertificateDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeManagedSslCertificate_basic(id),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeManagedSslCertificateExists("google_compute_managed_ssl_certificate.foobar"),
+					resource.TestCheckResourceAttr("google_compute_managed_ssl_certificate.foobar", "domains.#", "1"),
+					resource.TestCheckResourceAttrPair("google_compute_target_https_proxy.foobar", "ssl_certificates.0",
+						"google_compute_managed_ssl_certificate.foobar", "self_link"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_managed_ssl_certificate.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckComputeManagedSslCertificateDestroy(s *terraform.State) error {
+	config := testAccProvider.Meta().(*Config)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "google_compute_managed_ssl_certificate" {
+			continue
+		}
+
+		_, err := Get(config, convertSelfLinkToBeta(rs.Primary.Attributes["self_link"]))
+		if err == nil {
+			return fmt.Errorf("Managed SSL certificate %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckComputeManagedSslCertificateExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		config := testAccProvider.Meta().(*Config)
+
+		_, err := Get(config, convertSelfLinkToBeta(rs.Primary.Attributes["self_link"]))
+		return err
+	}
+}
+
+func testAccComputeManagedSslCertificate_basic(id string) string {
+	return fmt.Sprintf(`
+resource "google_compute_managed_ssl_certificate" "foobar" {
+	name = "managed-cert-test-%s"
+	description = "Resource created for Terraform acceptance testing"
+	domains = ["sslcert.tf-test.club."]
+}
+
+resource "google_compute_target_https_proxy" "foobar" {
+	name = "managed-cert-test-%s"
+	url_map = "${google_compute_url_map.foobar.self_link}"
+	ssl_certificates = ["${google_compute_managed_ssl_certificate.foobar.self_link}"]
+}
+
+resource "google_compute_url_map" "foobar" {
+	name = "managed-cert-test-%s"
+	default_service = "${google_compute_backend_service.foobar.self_link}"
+}
+
+resource "google_compute_backend_service" "foobar" {
+	name = "managed-cert-test-%s"
+	health_checks = ["${google_compute_http_health_check.foobar.self_link}"]
+}
+
+resource "google_compute_http_health_check" "foobar" {
+	name = "managed-cert-test-%s"
+	request_path = "/"
+	check_interval_sec = 1
+	timeout_sec = 1
+}
+`, id, id, id, id, id)
+}
@@ -125,7 +125,7 @@ func resourceComputeNetworkCreate(d *schema.ResourceData, meta interface{}) erro
 	// It probably maybe worked, so store the ID now
 	d.SetId(network.Name)
 
-	err = computeOperationWait(config.clientCompute, op, project, "Creating Network")
+	err = computeOperationWaitConfig(config, op, project, "Creating Network")
 	if err != nil {
 		return err
 	}
@@ -179,7 +179,7 @@ func resourceComputeNetworkUpdate(d *schema.ResourceData, meta interface{}) erro
 		return fmt.Errorf("Error updating network: %s", err)
 	}
 
-	err = computeSharedOperationWait(config.clientCompute, op, project, "UpdateNetwork")
+	err = computeSharedOperationWaitConfig(config, op, project, "UpdateNetwork")
 	if err != nil {
 		return err
 	}
@@ -205,7 +205,7 @@ func deleteComputeNetwork(project, network string, config *Config) error {
 		return fmt.Errorf("Error deleting network: %s", err)
 	}
 
-	err = computeOperationWaitTime(config.clientCompute, op, project, "Deleting Network", 10)
+	err = computeOperationWaitTimeConfig(config, op, project, "Deleting Network", 10)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,274 @@
+package google
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	compute "google.golang.org/api/compute/v1"
+)
+
+func resourceComputeNetworkEndpoint() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceComputeNetworkEndpointCreate,
+		Read:   resourceComputeNetworkEndpointRead,
+		Delete: resourceComputeNetworkEndpointDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: resourceComputeNetworkEndpointImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(240 * time.Second),
+			Delete: schema.DefaultTimeout(240 * time.Second),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"network_endpoint_group": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+			},
+			"instance": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+			},
+			"ip_address": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"port": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 65535),
+			},
+			"zone": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+			},
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceComputeNetworkEndpointCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	neg, err := ParseNetworkEndpointGroupFieldValue(d.Get("network_endpoint_group").(string), d, config)
+	if err != nil {
+		return fmt.Errorf("Invalid value for network_endpoint_group: %s", err)
+	}
+
+	// Endpoints of the same group can't be attached or detached concurrently.
+	lockName := networkEndpointGroupLockName(neg)
+	mutexKV.Lock(lockName)
+	defer mutexKV.Unlock(lockName)
+
+	obj := map[string]interface{}{
+		"networkEndpoints": []interface{}{expandComputeNetworkEndpoint(d)},
+	}
+
+	log.Printf("[DEBUG] Attaching NetworkEndpoint to %s: %#v", neg.RelativeLink(), obj)
+	res, err := Post(config, networkEndpointGroupBetaUrl(neg)+"/attachNetworkEndpoints", obj)
+	if err != nil {
+		return fmt.Errorf("Error creating NetworkEndpoint: %s", err)
+	}
+
+	// Store the ID now
+	d.SetId(networkEndpointId(neg, d))
+
+	op := &compute.Operation{}
+	err = Convert(res, op)
+	if err != nil {
+		return err
+	}
+
+	waitErr := computeOperationWaitTimeConfig(
+		config, op, neg.Project, "Creating NetworkEndpoint",
+		int(d.Timeout(schema.TimeoutCreate).Minutes()))
+
+	if waitErr != nil {
+		// The resource didn't actually create
+		d.SetId("")
+		return waitErr
+	}
+
+	return resourceComputeNetworkEndpointRead(d, meta)
+}
+
+func resourceComputeNetworkEndpointRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	neg, err := ParseNetworkEndpointGroupFieldValue(d.Get("network_endpoint_group").(string), d, config)
+	if err != nil {
+		return fmt.Errorf("Invalid value for network_endpoint_group: %s", err)
+	}
+
+	// Endpoints have no URL of their own, so they're looked up in the list of
+	// endpoints of their group.
+	endpoint, err := findComputeNetworkEndpoint(config, neg, d)
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("ComputeNetworkEndpoint %q", d.Id()))
+	}
+	if endpoint == nil {
+		log.Printf("[WARN] Removing ComputeNetworkEndpoint %q because it's gone", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	instance, _ := endpoint["instance"].(string)
+	d.Set("instance", GetResourceNameFromSelfLink(instance))
+	d.Set("ip_address", endpoint["ipAddress"])
+	d.Set("port", flattenComputeNetworkEndpointPort(endpoint["port"]))
+	d.Set("zone", neg.Zone)
+	d.Set("project", neg.Project)
+
+	return nil
+}
+
+func resourceComputeNetworkEndpointDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	neg, err := ParseNetworkEndpointGroupFieldValue(d.Get("network_endpoint_group").(string), d, config)
+	if err != nil {
+		return fmt.Errorf("Invalid value for network_endpoint_group: %s", err)
+	}
+
+	lockName := networkEndpointGroupLockName(neg)
+	mutexKV.Lock(lockName)
+	defer mutexKV.Unlock(lockName)
+
+	obj := map[string]interface{}{
+		"networkEndpoints": []interface{}{expandComputeNetworkEndpoint(d)},
+	}
+
+	log.Printf("[DEBUG] Deleting NetworkEndpoint %q", d.Id())
+	res, err := Post(config, networkEndpointGroupBetaUrl(neg)+"/detachNetworkEndpoints", obj)
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("ComputeNetworkEndpoint %q", d.Id()))
+	}
+
+	op := &compute.Operation{}
+	err = Convert(res, op)
+	if err != nil {
+		return err
+	}
+
+	err = computeOperationWaitTimeConfig(
+		config, op, neg.Project, "Deleting NetworkEndpoint",
+		int(d.Timeout(schema.TimeoutDelete).Minutes()))
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func resourceComputeNetworkEndpointImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	if len(parts) != 6 {
+		return nil, fmt.Errorf("Invalid network endpoint specifier. Expecting {project}/{zone}/{network_endpoint_group}/{instance}/{ip_address}/{port}, got %s", d.Id())
+	}
+
+	port, err := strconv.Atoi(parts[5])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid port %q in network endpoint specifier: %s", parts[5], err)
+	}
+
+	d.Set("project", parts[0])
+	d.Set("zone", parts[1])
+	d.Set("network_endpoint_group", parts[2])
+	d.Set("instance", parts[3])
+	d.Set("ip_address", parts[4])
+	d.Set("port", port)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func expandComputeNetworkEndpoint(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{
+		"instance":  GetResourceNameFromSelfLink(d.Get("instance").(string)),
+		"ipAddress": d.Get("ip_address"),
+		"port":      d.Get("port"),
+	}
+}
+
+// findComputeNetworkEndpoint returns the raw JSON of the endpoint of neg
+// that matches d, or nil if there isn't one.
+func findComputeNetworkEndpoint(config *Config, neg *ZonalFieldValue, d *schema.ResourceData) (map[string]interface{}, error) {
+	instance := GetResourceNameFromSelfLink(d.Get("instance").(string))
+	ipAddress := d.Get("ip_address").(string)
+	port := d.Get("port").(int)
+
+	pageToken := ""
+	for {
+		listUrl := networkEndpointGroupBetaUrl(neg) + "/listNetworkEndpoints"
+		if pageToken != "" {
+			listUrl = fmt.Sprintf("%s?pageToken=%s", listUrl, url.QueryEscape(pageToken))
+		}
+
+		res, err := Post(config, listUrl, map[string]interface{}{})
+		if err != nil {
+			return nil, err
+		}
+
+		items, _ := res["items"].([]interface{})
+		for _, v := range items {
+			item, _ := v.(map[string]interface{})
+			endpoint, ok := item["networkEndpoint"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			endpointInstance, _ := endpoint["instance"].(string)
+			if GetResourceNameFromSelfLink(endpointInstance) == instance &&
+				endpoint["ipAddress"] == ipAddress &&
+				flattenComputeNetworkEndpointPort(endpoint["port"]) == port {
+				return endpoint, nil
+			}
+		}
+
+		pageToken, _ = res["nextPageToken"].(string)
+		if pageToken == "" {
+			return nil, nil
+		}
+	}
+}
+
+func networkEndpointId(neg *ZonalFieldValue, d *schema.ResourceData) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s/%d", neg.Project, neg.Zone, neg.Name,
+		GetResourceNameFromSelfLink(d.Get("instance").(string)), d.Get("ip_address").(string), d.Get("port").(int))
+}
+
+func networkEndpointGroupBetaUrl(neg *ZonalFieldValue) string {
+	return "https://www.googleapis.com/compute/beta/" + neg.RelativeLink()
+}
+
+func networkEndpointGroupLockName(neg *ZonalFieldValue) string {
+	return fmt.Sprintf("networkEndpointGroup/%s", neg.RelativeLink())
+}
+
+func flattenComputeNetworkEndpointPort(v interface{}) int {
+	if port, ok := v.(float64); ok {
+		return int(port)
+	}
+	return 0
+}
@@ -0,0 +1,253 @@
+package google
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	compute "google.golang.org/api/compute/v1"
+)
+
+func resourceComputeNetworkEndpointGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceComputeNetworkEndpointGroupCreate,
+		Read:   resourceComputeNetworkEndpointGroupRead,
+		Delete: resourceComputeNetworkEndpointGroupDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: resourceComputeNetworkEndpointGroupImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(240 * time.Second),
+			Delete: schema.DefaultTimeout(240 * time.Second),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateGCPName,
+			},
+			"network": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+			},
+			"subnetwork": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+			},
+			"default_port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 65535),
+			},
+			"network_endpoint_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "GCE_VM_IP_PORT",
+				ValidateFunc: validation.StringInSlice([]string{"GCE_VM_IP_PORT"}, false),
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"zone": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+			},
+			"size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"self_link": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceComputeNetworkEndpointGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	network, err := ParseNetworkFieldValue(d.Get("network").(string), d, config)
+	if err != nil {
+		return fmt.Errorf("Invalid value for network: %s", err)
+	}
+
+	obj := map[string]interface{}{
+		"name":                d.Get("name"),
+		"description":         d.Get("description"),
+		"network":             network.RelativeLink(),
+		"networkEndpointType": d.Get("network_endpoint_type"),
+	}
+	if v, ok := d.GetOk("subnetwork"); ok {
+		subnetwork, err := ParseSubnetworkFieldValue(v.(string), d, config)
+		if err != nil {
+			return fmt.Errorf("Invalid value for subnetwork: %s", err)
+		}
+		obj["subnetwork"] = subnetwork.RelativeLink()
+	}
+	if v, ok := d.GetOk("default_port"); ok {
+		obj["defaultPort"] = v
+	}
+
+	url, err := replaceVars(d, config, "https://www.googleapis.com/compute/beta/projects/{{project}}/zones/{{zone}}/networkEndpointGroups")
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Creating new NetworkEndpointGroup: %#v", obj)
+	res, err := Post(config, url, obj)
+	if err != nil {
+		return fmt.Errorf("Error creating NetworkEndpointGroup: %s", err)
+	}
+
+	// Store the ID now
+	id, err := replaceVars(d, config, "{{zone}}/{{name}}")
+	if err != nil {
+		return fmt.Errorf("Error constructing id: %s", err)
+	}
+	d.SetId(id)
+
+	op := &compute.Operation{}
+	err = Convert(res, op)
+	if err != nil {
+		return err
+	}
+
+	waitErr := computeOperationWaitTimeConfig(
+		config, op, project, "Creating NetworkEndpointGroup",
+		int(d.Timeout(schema.TimeoutCreate).Minutes()))
+
+	if waitErr != nil {
+		// The resource didn't actually create
+		d.SetId("")
+		return waitErr
+	}
+
+	return resourceComputeNetworkEndpointGroupRead(d, meta)
+}
+
+func resourceComputeNetworkEndpointGroupRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	url, err := replaceVars(d, config, "https://www.googleapis.com/compute/beta/projects/{{project}}/zones/{{zone}}/networkEndpointGroups/{{name}}")
+	if err != nil {
+		return err
+	}
+
+	res, err := Get(config, url)
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("ComputeNetworkEndpointGroup %q", d.Id()))
+	}
+
+	d.Set("name", res["name"])
+	d.Set("description", res["description"])
+	d.Set("network", flattenComputeNetworkEndpointGroupSelfLink(res["network"]))
+	d.Set("subnetwork", flattenComputeNetworkEndpointGroupSelfLink(res["subnetwork"]))
+	d.Set("default_port", res["defaultPort"])
+	d.Set("network_endpoint_type", res["networkEndpointType"])
+	d.Set("size", res["size"])
+	d.Set("zone", GetResourceNameFromSelfLink(res["zone"].(string)))
+	d.Set("self_link", flattenComputeNetworkEndpointGroupSelfLink(res["selfLink"]))
+	d.Set("project", project)
+
+	return nil
+}
+
+func resourceComputeNetworkEndpointGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	url, err := replaceVars(d, config, "https://www.googleapis.com/compute/beta/projects/{{project}}/zones/{{zone}}/networkEndpointGroups/{{name}}")
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Deleting NetworkEndpointGroup %q", d.Id())
+	res, err := Delete(config, url)
+	if err != nil {
+		return fmt.Errorf("Error deleting NetworkEndpointGroup %q: %s", d.Id(), err)
+	}
+
+	op := &compute.Operation{}
+	err = Convert(res, op)
+	if err != nil {
+		return err
+	}
+
+	err = computeOperationWaitTimeConfig(
+		config, op, project, "Deleting NetworkEndpointGroup",
+		int(d.Timeout(schema.TimeoutDelete).Minutes()))
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func resourceComputeNetworkEndpointGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	config := meta.(*Config)
+	parseImportId([]string{"projects/(?P<project>[^/]+)/zones/(?P<zone>[^/]+)/networkEndpointGroups/(?P<name>[^/]+)", "(?P<project>[^/]+)/(?P<zone>[^/]+)/(?P<name>[^/]+)", "(?P<zone>[^/]+)/(?P<name>[^/]+)", "(?P<name>[^/]+)"}, d, config)
+
+	// Replace import id for the resource id
+	id, err := replaceVars(d, config, "{{zone}}/{{name}}")
+	if err != nil {
+		return nil, fmt.Errorf("Error constructing id: %s", err)
+	}
+	d.SetId(id)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func flattenComputeNetworkEndpointGroupSelfLink(v interface{}) interface{} {
+	if v == nil {
+		return v
+	}
+	return ConvertSelfLinkToV1(v.(string))
+}
+
+var networkEndpointGroupLinkRegex = regexp.MustCompile("^(https://www.googleapis.com/compute/[a-z0-9]+/)?(projects/[^/]+/)?zones/[^/]+/networkEndpointGroups/[^/]+$")
+
+// isNetworkEndpointGroupLink reports whether a backend group is a network
+// endpoint group rather than an instance group.
+func isNetworkEndpointGroupLink(group string) bool {
+	return networkEndpointGroupLinkRegex.MatchString(group)
+}
@@ -0,0 +1,117 @@
+package google
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccComputeNetworkEndpointGroup_basic(t *testing.T) {
+	t.Parallel()
+
+	negName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	networkName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeNetworkEndpointGroupDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeNetworkEndpointGroup_basic(negName, networkName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeNetworkEndpointGroupExists("google_compute_network_endpoint_group.neg"),
+					resource.TestCheckResourceAttr(
+						"google_compute_network_endpoint_group.neg", "size", "0"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_network_endpoint_group.neg",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestIsNetworkEndpointGroupLink(t *testing.T) {
+	cases := map[string]bool{
+		"https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/networkEndpointGroups/my-neg":         true,
+		"https://www.googleapis.com/compute/beta/projects/my-project/zones/us-central1-a/networkEndpointGroups/my-neg":       true,
+		"projects/my-project/zones/us-central1-a/networkEndpointGroups/my-neg":                                               true,
+		"zones/us-central1-a/networkEndpointGroups/my-neg":                                                                   true,
+		"https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/instanceGroups/my-group":              false,
+		"https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/instanceGroups/networkEndpointGroups": false,
+		"projects/my-project/zones/us-central1-a/networkEndpointGroups/my-neg/endpoints":                                     false,
+		"my-neg": false,
+	}
+
+	for group, expected := range cases {
+		if actual := isNetworkEndpointGroupLink(group); actual != expected {
+			t.Errorf("%s: expected %t, got %t", group, expected, actual)
+		}
+	}
+}
+
+func testAccCheckComputeNetworkEndpointGroupDestroy(s *terraform.State) error {
+	config := testAccProvider.Meta().(*Config)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "google_compute_network_endpoint_group" {
+			continue
+		}
+
+		_, err := Get(config, convertSelfLinkToBeta(rs.Primary.Attributes["self_link"]))
+		if err == nil {
+			return fmt.Errorf("Network endpoint group %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckComputeNetworkEndpointGroupExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		config := testAccProvider.Meta().(*Config)
+
+		_, err := Get(config, convertSelfLinkToBeta(rs.Primary.Attributes["self_link"]))
+		return err
+	}
+}
+
+func testAccComputeNetworkEndpointGroup_basic(negName, networkName string) string {
+	return fmt.Sprintf(`
+resource "google_compute_network_endpoint_group" "neg" {
+	name         = "%s"
+	network      = "${google_compute_network.default.self_link}"
+	subnetwork   = "${google_compute_subnetwork.default.self_link}"
+	default_port = 90
+	zone         = "us-central1-a"
+	description  = "example google_compute_network_endpoint_group for Terraform Google Provider"
+}
+
+resource "google_compute_network" "default" {
+	name                    = "%s"
+	auto_create_subnetworks = false
+}
+
+resource "google_compute_subnetwork" "default" {
+	name          = "%s"
+	ip_cidr_range = "10.0.0.0/16"
+	region        = "us-central1"
+	network       = "${google_compute_network.default.self_link}"
+}
+`, negName, networkName, networkName)
+}
@@ -0,0 +1,115 @@
+package google
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccComputeNetworkEndpoint_basic(t *testing.T) {
+	t.Parallel()
+
+	negName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	instanceName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeNetworkEndpointGroupDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeNetworkEndpoint_basic(negName, instanceName, 90),
+				Check:  testAccCheckComputeNetworkEndpointExists("google_compute_network_endpoint.endpoint"),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_network_endpoint.endpoint",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			resource.TestStep{
+				// Changing the port replaces the endpoint.
+				Config: testAccComputeNetworkEndpoint_basic(negName, instanceName, 100),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeNetworkEndpointExists("google_compute_network_endpoint.endpoint"),
+					resource.TestCheckResourceAttr(
+						"google_compute_network_endpoint.endpoint", "port", "100"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckComputeNetworkEndpointExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		config := testAccProvider.Meta().(*Config)
+		neg := &ZonalFieldValue{
+			Project:      rs.Primary.Attributes["project"],
+			Zone:         rs.Primary.Attributes["zone"],
+			Name:         GetResourceNameFromSelfLink(rs.Primary.Attributes["network_endpoint_group"]),
+			resourceType: "networkEndpointGroups",
+		}
+
+		res, err := Post(config, networkEndpointGroupBetaUrl(neg)+"/listNetworkEndpoints", map[string]interface{}{})
+		if err != nil {
+			return err
+		}
+
+		items, _ := res["items"].([]interface{})
+		for _, v := range items {
+			endpoint := v.(map[string]interface{})["networkEndpoint"].(map[string]interface{})
+			if endpoint["ipAddress"] == rs.Primary.Attributes["ip_address"] &&
+				fmt.Sprintf("%v", endpoint["port"]) == rs.Primary.Attributes["port"] {
+				return nil
+			}
+		}
+		return fmt.Errorf("Network endpoint %s not found", rs.Primary.ID)
+	}
+}
+
+func testAccComputeNetworkEndpoint_basic(negName, instanceName string, port int) string {
+	return fmt.Sprintf(`
+resource "google_compute_network_endpoint" "endpoint" {
+	network_endpoint_group = "${google_compute_network_endpoint_group.neg.name}"
+	zone                   = "us-central1-a"
+
+	instance   = "${google_compute_instance.endpoint.name}"
+	ip_address = "${google_compute_instance.endpoint.network_interface.0.network_ip}"
+	port       = %d
+}
+
+resource "google_compute_network_endpoint_group" "neg" {
+	name         = "%s"
+	network      = "default"
+	default_port = 90
+	zone         = "us-central1-a"
+}
+
+resource "google_compute_instance" "endpoint" {
+	name         = "%s"
+	machine_type = "n1-standard-1"
+	zone         = "us-central1-a"
+
+	boot_disk {
+		initialize_params {
+			image = "debian-cloud/debian-9"
+		}
+	}
+
+	network_interface {
+		network = "default"
+	}
+}
+`, port, negName, instanceName)
+}
@@ -0,0 +1,284 @@
+package google
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	compute "google.golang.org/api/compute/v1"
+)
+
+func resourceComputeNodeGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceComputeNodeGroupCreate,
+		Read:   resourceComputeNodeGroupRead,
+		Update: resourceComputeNodeGroupUpdate,
+		Delete: resourceComputeNodeGroupDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: resourceComputeNodeGroupImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(240 * time.Second),
+			Update: schema.DefaultTimeout(240 * time.Second),
+			Delete: schema.DefaultTimeout(240 * time.Second),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateGCPName,
+			},
+			"node_template": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+			},
+			"size": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"zone": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+			},
+			"creation_timestamp": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"self_link": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceComputeNodeGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	nodeTemplateProp, err := expandComputeNodeGroupNodeTemplate(d.Get("node_template"), d, config)
+	if err != nil {
+		return err
+	}
+
+	obj := map[string]interface{}{
+		"name":         d.Get("name"),
+		"description":  d.Get("description"),
+		"nodeTemplate": nodeTemplateProp,
+	}
+
+	url, err := replaceVars(d, config, "https://www.googleapis.com/compute/beta/projects/{{project}}/zones/{{zone}}/nodeGroups")
+	if err != nil {
+		return err
+	}
+	// The initial size isn't part of the node group, it's passed alongside it.
+	url = fmt.Sprintf("%s?initialNodeCount=%d", url, d.Get("size").(int))
+
+	log.Printf("[DEBUG] Creating new NodeGroup: %#v", obj)
+	res, err := Post(config, url, obj)
+	if err != nil {
+		return fmt.Errorf("Error creating NodeGroup: %s", err)
+	}
+
+	// Store the ID now
+	id, err := replaceVars(d, config, "{{zone}}/{{name}}")
+	if err != nil {
+		return fmt.Errorf("Error constructing id: %s", err)
+	}
+	d.SetId(id)
+
+	op := &compute.Operation{}
+	err = Convert(res, op)
+	if err != nil {
+		return err
+	}
+
+	waitErr := computeOperationWaitTimeConfig(
+		config, op, project, "Creating NodeGroup",
+		int(d.Timeout(schema.TimeoutCreate).Minutes()))
+
+	if waitErr != nil {
+		// The resource didn't actually create
+		d.SetId("")
+		return waitErr
+	}
+
+	return resourceComputeNodeGroupRead(d, meta)
+}
+
+func resourceComputeNodeGroupRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	url, err := replaceVars(d, config, "https://www.googleapis.com/compute/beta/projects/{{project}}/zones/{{zone}}/nodeGroups/{{name}}")
+	if err != nil {
+		return err
+	}
+
+	res, err := Get(config, url)
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("ComputeNodeGroup %q", d.Id()))
+	}
+
+	d.Set("name", res["name"])
+	d.Set("description", res["description"])
+	d.Set("node_template", flattenComputeNodeGroupSelfLink(res["nodeTemplate"]))
+	d.Set("size", flattenComputeNodeGroupSize(res["size"]))
+	d.Set("zone", GetResourceNameFromSelfLink(res["zone"].(string)))
+	d.Set("creation_timestamp", res["creationTimestamp"])
+	d.Set("self_link", flattenComputeNodeGroupSelfLink(res["selfLink"]))
+	d.Set("project", project)
+
+	return nil
+}
+
+func resourceComputeNodeGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	if d.HasChange("node_template") {
+		nodeTemplateProp, err := expandComputeNodeGroupNodeTemplate(d.Get("node_template"), d, config)
+		if err != nil {
+			return err
+		}
+
+		obj := map[string]interface{}{
+			"nodeTemplate": nodeTemplateProp,
+		}
+
+		url, err := replaceVars(d, config, "https://www.googleapis.com/compute/beta/projects/{{project}}/zones/{{zone}}/nodeGroups/{{name}}/setNodeTemplate")
+		if err != nil {
+			return err
+		}
+
+		log.Printf("[DEBUG] Updating NodeGroup %q: %#v", d.Id(), obj)
+		res, err := Post(config, url, obj)
+		if err != nil {
+			return fmt.Errorf("Error updating NodeGroup %q: %s", d.Id(), err)
+		}
+
+		op := &compute.Operation{}
+		err = Convert(res, op)
+		if err != nil {
+			return err
+		}
+
+		err = computeOperationWaitTimeConfig(
+			config, op, project, "Updating NodeGroup",
+			int(d.Timeout(schema.TimeoutUpdate).Minutes()))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceComputeNodeGroupRead(d, meta)
+}
+
+func resourceComputeNodeGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	url, err := replaceVars(d, config, "https://www.googleapis.com/compute/beta/projects/{{project}}/zones/{{zone}}/nodeGroups/{{name}}")
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Deleting NodeGroup %q", d.Id())
+	res, err := Delete(config, url)
+	if err != nil {
+		return fmt.Errorf("Error deleting NodeGroup %q: %s", d.Id(), err)
+	}
+
+	op := &compute.Operation{}
+	err = Convert(res, op)
+	if err != nil {
+		return err
+	}
+
+	err = computeOperationWaitTimeConfig(
+		config, op, project, "Deleting NodeGroup",
+		int(d.Timeout(schema.TimeoutDelete).Minutes()))
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func resourceComputeNodeGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	config := meta.(*Config)
+	parseImportId([]string{"projects/(?P<project>[^/]+)/zones/(?P<zone>[^/]+)/nodeGroups/(?P<name>[^/]+)", "(?P<project>[^/]+)/(?P<zone>[^/]+)/(?P<name>[^/]+)", "(?P<zone>[^/]+)/(?P<name>[^/]+)", "(?P<name>[^/]+)"}, d, config)
+
+	// Replace import id for the resource id
+	id, err := replaceVars(d, config, "{{zone}}/{{name}}")
+	if err != nil {
+		return nil, fmt.Errorf("Error constructing id: %s", err)
+	}
+	d.SetId(id)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func flattenComputeNodeGroupSelfLink(v interface{}) interface{} {
+	if v == nil {
+		return v
+	}
+	return ConvertSelfLinkToV1(v.(string))
+}
+
+func flattenComputeNodeGroupSize(v interface{}) interface{} {
+	// JSON numbers are decoded as float64.
+	if f, ok := v.(float64); ok {
+		return int(f)
+	}
+	return v
+}
+
+func expandComputeNodeGroupNodeTemplate(v interface{}, d *schema.ResourceData, config *Config) (interface{}, error) {
+	f, err := ParseNodeTemplateFieldValue(v.(string), d, config)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid value for node_template: %s", err)
+	}
+	return f.RelativeLink(), nil
+}
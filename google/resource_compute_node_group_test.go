@@ -0,0 +1,100 @@
+package google
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccComputeNodeGroup_updateNodeTemplate(t *testing.T) {
+	t.Parallel()
+
+	groupName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	tmplPrefix := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeNodeGroupDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeNodeGroup_nodeTemplate(groupName, tmplPrefix, "tmpl1"),
+				Check:  testAccCheckComputeNodeGroupExists("google_compute_node_group.nodes"),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_node_group.nodes",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			resource.TestStep{
+				Config: testAccComputeNodeGroup_nodeTemplate(groupName, tmplPrefix, "tmpl2"),
+				Check: resource.TestCheckResourceAttrPair(
+					"google_compute_node_group.nodes", "node_template",
+					"google_compute_node_template.tmpl2", "self_link"),
+			},
+		},
+	})
+}
+
+func testAccCheckComputeNodeGroupDestroy(s *terraform.State) error {
+	config := testAccProvider.Meta().(*Config)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "google_compute_node_group" {
+			continue
+		}
+
+		_, err := Get(config, convertSelfLinkToBeta(rs.Primary.Attributes["self_link"]))
+		if err == nil {
+			return fmt.Errorf("Node group %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckComputeNodeGroupExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		config := testAccProvider.Meta().(*Config)
+
+		_, err := Get(config, convertSelfLinkToBeta(rs.Primary.Attributes["self_link"]))
+		return err
+	}
+}
+
+func testAccComputeNodeGroup_nodeTemplate(groupName, tmplPrefix, tmplToUse string) string {
+	return fmt.Sprintf(`
+resource "google_compute_node_template" "tmpl1" {
+	name = "%s-first"
+	region = "us-central1"
+	node_type = "n1-node-96-624"
+}
+
+resource "google_compute_node_template" "tmpl2" {
+	name = "%s-second"
+	region = "us-central1"
+	node_type = "n1-node-96-624"
+}
+
+resource "google_compute_node_group" "nodes" {
+	name = "%s"
+	zone = "us-central1-a"
+	description = "example google_compute_node_group for Terraform Google Provider"
+
+	size = 1
+	node_template = "${google_compute_node_template.%s.self_link}"
+}
+`, tmplPrefix, tmplPrefix, groupName, tmplToUse)
+}
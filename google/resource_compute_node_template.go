@@ -0,0 +1,212 @@
+package google
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	compute "google.golang.org/api/compute/v1"
+)
+
+func resourceComputeNodeTemplate() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceComputeNodeTemplateCreate,
+		Read:   resourceComputeNodeTemplateRead,
+		Delete: resourceComputeNodeTemplateDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: resourceComputeNodeTemplateImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(240 * time.Second),
+			Delete: schema.DefaultTimeout(240 * time.Second),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateGCPName,
+			},
+			"node_type": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"node_affinity_labels": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"region": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+			},
+			"creation_timestamp": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"self_link": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceComputeNodeTemplateCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	obj := map[string]interface{}{
+		"name":               d.Get("name"),
+		"description":        d.Get("description"),
+		"nodeType":           d.Get("node_type"),
+		"nodeAffinityLabels": d.Get("node_affinity_labels"),
+	}
+
+	url, err := replaceVars(d, config, "https://www.googleapis.com/compute/beta/projects/{{project}}/regions/{{region}}/nodeTemplates")
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Creating new NodeTemplate: %#v", obj)
+	res, err := Post(config, url, obj)
+	if err != nil {
+		return fmt.Errorf("Error creating NodeTemplate: %s", err)
+	}
+
+	// Store the ID now
+	id, err := replaceVars(d, config, "{{region}}/{{name}}")
+	if err != nil {
+		return fmt.Errorf("Error constructing id: %s", err)
+	}
+	d.SetId(id)
+
+	op := &compute.Operation{}
+	err = Convert(res, op)
+	if err != nil {
+		return err
+	}
+
+	waitErr := computeOperationWaitTimeConfig(
+		config, op, project, "Creating NodeTemplate",
+		int(d.Timeout(schema.TimeoutCreate).Minutes()))
+
+	if waitErr != nil {
+		// The resource didn't actually create
+		d.SetId("")
+		return waitErr
+	}
+
+	return resourceComputeNodeTemplateRead(d, meta)
+}
+
+func resourceComputeNodeTemplateRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	url, err := replaceVars(d, config, "https://www.googleapis.com/compute/beta/projects/{{project}}/regions/{{region}}/nodeTemplates/{{name}}")
+	if err != nil {
+		return err
+	}
+
+	res, err := Get(config, url)
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("ComputeNodeTemplate %q", d.Id()))
+	}
+
+	d.Set("name", res["name"])
+	d.Set("description", res["description"])
+	d.Set("node_type", res["nodeType"])
+	d.Set("node_affinity_labels", res["nodeAffinityLabels"])
+	d.Set("region", flattenComputeNodeTemplateRegion(res["region"]))
+	d.Set("creation_timestamp", res["creationTimestamp"])
+	d.Set("self_link", ConvertSelfLinkToV1(res["selfLink"].(string)))
+	d.Set("project", project)
+
+	return nil
+}
+
+func resourceComputeNodeTemplateDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	url, err := replaceVars(d, config, "https://www.googleapis.com/compute/beta/projects/{{project}}/regions/{{region}}/nodeTemplates/{{name}}")
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Deleting NodeTemplate %q", d.Id())
+	res, err := Delete(config, url)
+	if err != nil {
+		return fmt.Errorf("Error deleting NodeTemplate %q: %s", d.Id(), err)
+	}
+
+	op := &compute.Operation{}
+	err = Convert(res, op)
+	if err != nil {
+		return err
+	}
+
+	err = computeOperationWaitTimeConfig(
+		config, op, project, "Deleting NodeTemplate",
+		int(d.Timeout(schema.TimeoutDelete).Minutes()))
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func resourceComputeNodeTemplateImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	config := meta.(*Config)
+	parseImportId([]string{"projects/(?P<project>[^/]+)/regions/(?P<region>[^/]+)/nodeTemplates/(?P<name>[^/]+)", "(?P<project>[^/]+)/(?P<region>[^/]+)/(?P<name>[^/]+)", "(?P<region>[^/]+)/(?P<name>[^/]+)", "(?P<name>[^/]+)"}, d, config)
+
+	// Replace import id for the resource id
+	id, err := replaceVars(d, config, "{{region}}/{{name}}")
+	if err != nil {
+		return nil, fmt.Errorf("Error constructing id: %s", err)
+	}
+	d.SetId(id)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func flattenComputeNodeTemplateRegion(v interface{}) interface{} {
+	if v == nil {
+		return v
+	}
+	return GetResourceNameFromSelfLink(v.(string))
+}
@@ -0,0 +1,84 @@
+package google
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccComputeNodeTemplate_basic(t *testing.T) {
+	t.Parallel()
+
+	templateName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeNodeTemplateDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeNodeTemplate_basic(templateName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeNodeTemplateExists("google_compute_node_template.foobar"),
+					resource.TestCheckResourceAttr("google_compute_node_template.foobar", "node_affinity_labels.foo", "bar"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_node_template.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckComputeNodeTemplateDestroy(s *terraform.State) error {
+	config := testAccProvider.Meta().(*Config)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "google_compute_node_template" {
+			continue
+		}
+
+		_, err := Get(config, convertSelfLinkToBeta(rs.Primary.Attributes["self_link"]))
+		if err == nil {
+			return fmt.Errorf("Node template %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckComputeNodeTemplateExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		config := testAccProvider.Meta().(*Config)
+
+		_, err := Get(config, convertSelfLinkToBeta(rs.Primary.Attributes["self_link"]))
+		return err
+	}
+}
+
+func testAccComputeNodeTemplate_basic(templateName string) string {
+	return fmt.Sprintf(`
+resource "google_compute_node_template" "foobar" {
+	name = "%s"
+	region = "us-central1"
+	node_type = "n1-node-96-624"
+
+	node_affinity_labels {
+		foo = "bar"
+	}
+}`, templateName)
+}
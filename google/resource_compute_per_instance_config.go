@@ -0,0 +1,429 @@
+package google
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"google.golang.org/api/compute/v1"
+)
+
+var perInstanceConfigActions = []string{"NONE", "REFRESH", "RESTART", "REPLACE"}
+
+func resourceComputePerInstanceConfig() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceComputePerInstanceConfigCreate,
+		Read:   resourceComputePerInstanceConfigRead,
+		Update: resourceComputePerInstanceConfigUpdate,
+		Delete: resourceComputePerInstanceConfigDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceComputePerInstanceConfigImportState,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"instance_group_manager": &schema.Schema{
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+			},
+
+			"zone": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region"},
+			},
+
+			"region": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"zone"},
+			},
+
+			"project": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"preserved_state": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"metadata": &schema.Schema{
+							Type:     schema.TypeMap,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+
+						"disk": &schema.Schema{
+							Type:     schema.TypeSet,
+							Optional: true,
+							Set:      perInstanceConfigDiskHash,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"device_name": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+
+									"source": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+
+									"mode": &schema.Schema{
+										Type:         schema.TypeString,
+										Optional:     true,
+										Default:      "READ_WRITE",
+										ValidateFunc: validation.StringInSlice([]string{"READ_ONLY", "READ_WRITE"}, false),
+									},
+
+									"delete_rule": &schema.Schema{
+										Type:         schema.TypeString,
+										Optional:     true,
+										Default:      "NEVER",
+										ValidateFunc: validation.StringInSlice(statefulDiskDeleteRules, false),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+
+			"minimal_action": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "NONE",
+				ValidateFunc: validation.StringInSlice(perInstanceConfigActions, false),
+			},
+
+			"most_disruptive_allowed_action": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "REPLACE",
+				ValidateFunc: validation.StringInSlice(perInstanceConfigActions, false),
+			},
+		},
+	}
+}
+
+// perInstanceConfigManagerUrl returns the beta API URL of the instance group
+// manager the config belongs to, along with the location part of that URL.
+func perInstanceConfigManagerUrl(d *schema.ResourceData, config *Config) (string, string, error) {
+	project, err := getProject(d, config)
+	if err != nil {
+		return "", "", err
+	}
+
+	var location string
+	if region, ok := d.GetOk("region"); ok {
+		location = "regions/" + region.(string)
+	} else {
+		zone, err := getZone(d, config)
+		if err != nil {
+			return "", "", err
+		}
+		location = "zones/" + zone
+	}
+
+	manager := GetResourceNameFromSelfLink(d.Get("instance_group_manager").(string))
+	return instanceGroupManagerBetaUrl(project, location, manager), location, nil
+}
+
+func getPerInstanceConfigLockName(managerUrl string) string {
+	return fmt.Sprintf("instanceGroupManager/%s", managerUrl)
+}
+
+func expandPerInstanceConfig(d *schema.ResourceData) map[string]interface{} {
+	preservedState := map[string]interface{}{}
+	if v, ok := d.GetOk("preserved_state"); ok && v.([]interface{})[0] != nil {
+		state := v.([]interface{})[0].(map[string]interface{})
+
+		if metadata, ok := state["metadata"]; ok {
+			preservedState["metadata"] = metadata
+		}
+
+		disks := make(map[string]interface{})
+		for _, raw := range state["disk"].(*schema.Set).List() {
+			disk := raw.(map[string]interface{})
+			disks[disk["device_name"].(string)] = map[string]interface{}{
+				"source":     disk["source"].(string),
+				"mode":       disk["mode"].(string),
+				"autoDelete": disk["delete_rule"].(string),
+			}
+		}
+		preservedState["disks"] = disks
+	}
+
+	return map[string]interface{}{
+		"name":           d.Get("name").(string),
+		"preservedState": preservedState,
+	}
+}
+
+func flattenPerInstanceConfigPreservedState(perInstanceConfig map[string]interface{}) []map[string]interface{} {
+	preservedState, ok := perInstanceConfig["preservedState"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	disks := make([]interface{}, 0)
+	rawDisks, _ := preservedState["disks"].(map[string]interface{})
+	for deviceName, raw := range rawDisks {
+		disk := raw.(map[string]interface{})
+		disks = append(disks, map[string]interface{}{
+			"device_name": deviceName,
+			"source":      ConvertSelfLinkToV1(disk["source"].(string)),
+			"mode":        disk["mode"],
+			"delete_rule": disk["autoDelete"],
+		})
+	}
+
+	return []map[string]interface{}{
+		{
+			"metadata": preservedState["metadata"],
+			"disk":     schema.NewSet(perInstanceConfigDiskHash, disks),
+		},
+	}
+}
+
+func resourceComputePerInstanceConfigCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	managerUrl, location, err := perInstanceConfigManagerUrl(d, config)
+	if err != nil {
+		return err
+	}
+
+	if err := updatePerInstanceConfig(d, config, managerUrl); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", GetResourceNameFromSelfLink(location),
+		GetResourceNameFromSelfLink(managerUrl), d.Get("name").(string)))
+
+	return resourceComputePerInstanceConfigRead(d, meta)
+}
+
+func resourceComputePerInstanceConfigRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	managerUrl, _, err := perInstanceConfigManagerUrl(d, config)
+	if err != nil {
+		return err
+	}
+
+	configs, err := listPerInstanceConfigs(config, managerUrl)
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("Instance Group Manager %q", d.Get("instance_group_manager").(string)))
+	}
+
+	name := d.Get("name").(string)
+	for _, raw := range configs {
+		perInstanceConfig := raw.(map[string]interface{})
+		if perInstanceConfig["name"] != name {
+			continue
+		}
+
+		d.Set("project", project)
+		if _, ok := d.GetOk("region"); !ok {
+			d.Set("zone", GetResourceNameFromSelfLink(strings.Split(managerUrl, "/instanceGroupManagers/")[0]))
+		}
+		if err := d.Set("preserved_state", flattenPerInstanceConfigPreservedState(perInstanceConfig)); err != nil {
+			return fmt.Errorf("Error reading preserved_state: %s", err)
+		}
+		return nil
+	}
+
+	log.Printf("[WARN] Removing Per Instance Config %q because it's gone", name)
+	d.SetId("")
+	return nil
+}
+
+func resourceComputePerInstanceConfigUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	managerUrl, _, err := perInstanceConfigManagerUrl(d, config)
+	if err != nil {
+		return err
+	}
+
+	if d.HasChange("preserved_state") {
+		if err := updatePerInstanceConfig(d, config, managerUrl); err != nil {
+			return err
+		}
+	}
+
+	return resourceComputePerInstanceConfigRead(d, meta)
+}
+
+func resourceComputePerInstanceConfigDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	managerUrl, _, err := perInstanceConfigManagerUrl(d, config)
+	if err != nil {
+		return err
+	}
+
+	lockName := getPerInstanceConfigLockName(managerUrl)
+	mutexKV.Lock(lockName)
+	defer mutexKV.Unlock(lockName)
+
+	name := d.Get("name").(string)
+	log.Printf("[DEBUG] Deleting Per Instance Config %q from %s", name, managerUrl)
+	res, err := Post(config, managerUrl+"/deletePerInstanceConfigs", map[string]interface{}{
+		"names": []string{name},
+	})
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("Per Instance Config %q", name))
+	}
+
+	op := &compute.Operation{}
+	if err := Convert(res, op); err != nil {
+		return err
+	}
+
+	if err := computeOperationWaitConfig(config, op, project, "Deleting Per Instance Config"); err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// updatePerInstanceConfig creates or replaces the config on its manager, then
+// applies it to the instance if minimal_action asks for it.
+func updatePerInstanceConfig(d *schema.ResourceData, config *Config, managerUrl string) error {
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	lockName := getPerInstanceConfigLockName(managerUrl)
+	mutexKV.Lock(lockName)
+	defer mutexKV.Unlock(lockName)
+
+	perInstanceConfig := expandPerInstanceConfig(d)
+	log.Printf("[DEBUG] Updating Per Instance Config on %s: %#v", managerUrl, perInstanceConfig)
+	res, err := Post(config, managerUrl+"/updatePerInstanceConfigs", map[string]interface{}{
+		"perInstanceConfigs": []interface{}{perInstanceConfig},
+	})
+	if err != nil {
+		return fmt.Errorf("Error updating Per Instance Config: %s", err)
+	}
+
+	op := &compute.Operation{}
+	if err := Convert(res, op); err != nil {
+		return err
+	}
+
+	if err := computeOperationWaitConfig(config, op, project, "Updating Per Instance Config"); err != nil {
+		return err
+	}
+
+	if d.Get("minimal_action").(string) == "NONE" {
+		return nil
+	}
+
+	instance, err := perInstanceConfigInstanceUrl(d, config, managerUrl)
+	if err != nil {
+		return err
+	}
+
+	res, err = Post(config, managerUrl+"/applyUpdatesToInstances", map[string]interface{}{
+		"instances":                   []string{instance},
+		"minimalAction":               d.Get("minimal_action").(string),
+		"mostDisruptiveAllowedAction": d.Get("most_disruptive_allowed_action").(string),
+	})
+	if err != nil {
+		return fmt.Errorf("Error applying Per Instance Config: %s", err)
+	}
+
+	op = &compute.Operation{}
+	if err := Convert(res, op); err != nil {
+		return err
+	}
+
+	return computeOperationWaitConfig(config, op, project, "Applying Per Instance Config")
+}
+
+// perInstanceConfigInstanceUrl returns the partial URL of the instance a
+// config applies to. Instances of regional managers may live in any zone of
+// the region, so they are looked up in the managed instances list.
+func perInstanceConfigInstanceUrl(d *schema.ResourceData, config *Config, managerUrl string) (string, error) {
+	name := d.Get("name").(string)
+	region, ok := d.GetOk("region")
+	if !ok {
+		zone, err := getZone(d, config)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("zones/%s/instances/%s", zone, name), nil
+	}
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return "", err
+	}
+
+	manager := GetResourceNameFromSelfLink(managerUrl)
+	res, err := config.clientComputeBeta.RegionInstanceGroupManagers.ListManagedInstances(project, region.(string), manager).Do()
+	if err != nil {
+		return "", fmt.Errorf("Error listing instances of %s: %s", manager, err)
+	}
+
+	for _, instance := range res.ManagedInstances {
+		if GetResourceNameFromSelfLink(instance.Instance) == name {
+			return getRelativePath(instance.Instance)
+		}
+	}
+	return "", fmt.Errorf("Instance %q not found in Region Instance Group Manager %q", name, manager)
+}
+
+var perInstanceConfigZoneRegexp = regexp.MustCompile("^[a-z]+-[a-z]+[0-9]+-[a-z]$")
+
+func resourceComputePerInstanceConfigImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("Invalid per instance config specifier. Expecting {zone|region}/{instance_group_manager}/{name}")
+	}
+
+	if perInstanceConfigZoneRegexp.MatchString(parts[0]) {
+		d.Set("zone", parts[0])
+	} else {
+		d.Set("region", parts[0])
+	}
+	d.Set("instance_group_manager", parts[1])
+	d.Set("name", parts[2])
+	d.Set("minimal_action", "NONE")
+	d.Set("most_disruptive_allowed_action", "REPLACE")
+
+	return []*schema.ResourceData{d}, nil
+}
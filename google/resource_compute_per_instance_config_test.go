@@ -0,0 +1,139 @@
+package google
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccComputePerInstanceConfig_basic(t *testing.T) {
+	t.Parallel()
+
+	testId := acctest.RandString(10)
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputePerInstanceConfigDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputePerInstanceConfig_basic(testId, "bar"),
+				Check: testAccCheckComputePerInstanceConfigExists(
+					"google_compute_per_instance_config.default"),
+			},
+			resource.TestStep{
+				ResourceName:            "google_compute_per_instance_config.default",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"minimal_action", "most_disruptive_allowed_action"},
+			},
+			resource.TestStep{
+				Config: testAccComputePerInstanceConfig_basic(testId, "baz"),
+				Check: resource.TestCheckResourceAttr(
+					"google_compute_per_instance_config.default", "preserved_state.0.metadata.foo", "baz"),
+			},
+		},
+	})
+}
+
+func testAccCheckComputePerInstanceConfigDestroy(s *terraform.State) error {
+	config := testAccProvider.Meta().(*Config)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "google_compute_per_instance_config" {
+			continue
+		}
+
+		managerUrl := instanceGroupManagerBetaUrl(config.Project, "zones/"+rs.Primary.Attributes["zone"], rs.Primary.Attributes["instance_group_manager"])
+		configs, err := listPerInstanceConfigs(config, managerUrl)
+		if err != nil {
+			// The instance group manager is gone too.
+			continue
+		}
+
+		for _, raw := range configs {
+			if raw.(map[string]interface{})["name"] == rs.Primary.Attributes["name"] {
+				return fmt.Errorf("Per Instance Config %s still exists", rs.Primary.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckComputePerInstanceConfigExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		config := testAccProvider.Meta().(*Config)
+
+		managerUrl := instanceGroupManagerBetaUrl(config.Project, "zones/"+rs.Primary.Attributes["zone"], rs.Primary.Attributes["instance_group_manager"])
+		configs, err := listPerInstanceConfigs(config, managerUrl)
+		if err != nil {
+			return err
+		}
+
+		for _, raw := range configs {
+			if raw.(map[string]interface{})["name"] == rs.Primary.Attributes["name"] {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Per Instance Config %s not found", rs.Primary.ID)
+	}
+}
+
+func testAccComputePerInstanceConfig_basic(testId, metadata string) string {
+	return fmt.Sprintf(`
+resource "google_compute_instance_template" "default" {
+	name = "pic-test-%s"
+	machine_type = "n1-standard-1"
+	disk {
+		source_image = "debian-cloud/debian-9"
+		auto_delete = true
+		boot = true
+	}
+	network_interface {
+		network = "default"
+	}
+}
+
+resource "google_compute_instance_group_manager" "default" {
+	name = "pic-test-%s"
+	instance_template = "${google_compute_instance_template.default.self_link}"
+	base_instance_name = "pic-test"
+	zone = "us-central1-c"
+}
+
+resource "google_compute_disk" "default" {
+	name = "pic-test-%s"
+	image = "debian-cloud/debian-9"
+	zone = "us-central1-c"
+}
+
+resource "google_compute_per_instance_config" "default" {
+	zone = "${google_compute_instance_group_manager.default.zone}"
+	instance_group_manager = "${google_compute_instance_group_manager.default.name}"
+	name = "pic-test-%s"
+	minimal_action = "REPLACE"
+	preserved_state {
+		metadata {
+			foo = "%s"
+		}
+		disk {
+			device_name = "data"
+			source = "${google_compute_disk.default.self_link}"
+		}
+	}
+}
+`, testId, testId, testId, testId, metadata)
+}
@@ -80,7 +80,7 @@ func resourceComputeProjectMetadataCreate(d *schema.ResourceData, meta interface
 
 		log.Printf("[DEBUG] SetCommonMetadata: %d (%s)", op.Id, op.SelfLink)
 
-		return computeOperationWait(config.clientCompute, op, project.Name, "SetCommonMetadata")
+		return computeOperationWaitConfig(config, op, project.Name, "SetCommonMetadata")
 	}
 
 	err = MetadataRetryWrapper(createMD)
@@ -159,7 +159,7 @@ func resourceComputeProjectMetadataUpdate(d *schema.ResourceData, meta interface
 			// Optimistic locking requires the fingerprint received to match
 			// the fingerprint we send the server, if there is a mismatch then we
 			// are working on old data, and must retry
-			return computeOperationWait(config.clientCompute, op, project.Name, "SetCommonMetadata")
+			return computeOperationWaitConfig(config, op, project.Name, "SetCommonMetadata")
 		}
 
 		err := MetadataRetryWrapper(updateMD)
@@ -201,7 +201,7 @@ func resourceComputeProjectMetadataDelete(d *schema.ResourceData, meta interface
 
 	log.Printf("[DEBUG] SetCommonMetadata: %d (%s)", op.Id, op.SelfLink)
 
-	err = computeOperationWait(config.clientCompute, op, project.Name, "SetCommonMetadata")
+	err = computeOperationWaitConfig(config, op, project.Name, "SetCommonMetadata")
 	if err != nil {
 		return err
 	}
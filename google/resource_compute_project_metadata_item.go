@@ -173,7 +173,7 @@ func updateComputeCommonInstanceMetadata(config *Config, projectID string, key s
 
 		log.Printf("[DEBUG] SetCommonInstanceMetadata: %d (%s)", op.Id, op.SelfLink)
 
-		return computeOperationWait(config.clientCompute, op, project.Name, "SetCommonInstanceMetadata")
+		return computeOperationWaitConfig(config, op, project.Name, "SetCommonInstanceMetadata")
 	}
 
 	return MetadataRetryWrapper(updateMD)
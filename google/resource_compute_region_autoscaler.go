@@ -5,6 +5,7 @@ import (
 	"log"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"google.golang.org/api/compute/v1"
 )
 
 func resourceComputeRegionAutoscaler() *schema.Resource {
@@ -79,16 +80,20 @@ func resourceComputeRegionAutoscalerCreate(d *schema.ResourceData, meta interfac
 		return err
 	}
 
-	op, err := config.clientCompute.RegionAutoscalers.Insert(
-		project, region.Name, scaler).Do()
+	res, err := Post(config, autoscalersUrl(project, "regions/"+region.Name), scaler)
 	if err != nil {
 		return fmt.Errorf("Error creating Autoscaler: %s", err)
 	}
 
 	// It probably maybe worked, so store the ID now
-	d.SetId(scaler.Name)
+	d.SetId(d.Get("name").(string))
 
-	err = computeOperationWait(config.clientCompute, op, project, "Creating Autoscaler")
+	op := &compute.Operation{}
+	if err := Convert(res, op); err != nil {
+		return err
+	}
+
+	err = computeOperationWaitConfig(config, op, project, "Creating Autoscaler")
 	if err != nil {
 		return err
 	}
@@ -109,29 +114,14 @@ func resourceComputeRegionAutoscalerRead(d *schema.ResourceData, meta interface{
 		return err
 	}
 
-	scaler, err := config.clientCompute.RegionAutoscalers.Get(
-		project, region, d.Id()).Do()
+	scaler, err := Get(config, fmt.Sprintf("%s/%s", autoscalersUrl(project, "regions/"+region), d.Id()))
 	if err != nil {
 		return handleNotFoundError(err, d, fmt.Sprintf("Autoscaler %q", d.Id()))
 	}
 
-	if scaler == nil {
-		log.Printf("[WARN] Removing Autoscaler %q because it's gone", d.Get("name").(string))
-		d.SetId("")
-		return nil
-	}
-
-	d.Set("self_link", scaler.SelfLink)
-	d.Set("name", scaler.Name)
-	d.Set("target", scaler.Target)
-	d.Set("region", GetResourceNameFromSelfLink(scaler.Region))
-	d.Set("description", scaler.Description)
+	d.Set("region", region)
 	d.Set("project", project)
-	if scaler.AutoscalingPolicy != nil {
-		d.Set("autoscaling_policy", flattenAutoscalingPolicy(scaler.AutoscalingPolicy))
-	}
-
-	return nil
+	return setAutoscaler(d, scaler)
 }
 
 func resourceComputeRegionAutoscalerUpdate(d *schema.ResourceData, meta interface{}) error {
@@ -149,16 +139,20 @@ func resourceComputeRegionAutoscalerUpdate(d *schema.ResourceData, meta interfac
 		return err
 	}
 
-	op, err := config.clientCompute.RegionAutoscalers.Update(
-		project, region, scaler).Do()
+	res, err := Put(config, fmt.Sprintf("%s?autoscaler=%s", autoscalersUrl(project, "regions/"+region), d.Id()), scaler)
 	if err != nil {
 		return fmt.Errorf("Error updating Autoscaler: %s", err)
 	}
 
 	// It probably maybe worked, so store the ID now
-	d.SetId(scaler.Name)
+	d.SetId(d.Get("name").(string))
+
+	op := &compute.Operation{}
+	if err := Convert(res, op); err != nil {
+		return err
+	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Updating Autoscaler")
+	err = computeOperationWaitConfig(config, op, project, "Updating Autoscaler")
 	if err != nil {
 		return err
 	}
@@ -181,7 +175,7 @@ func resourceComputeRegionAutoscalerDelete(d *schema.ResourceData, meta interfac
 		return fmt.Errorf("Error deleting autoscaler: %s", err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Deleting Autoscaler")
+	err = computeOperationWaitConfig(config, op, project, "Deleting Autoscaler")
 	if err != nil {
 		return err
 	}
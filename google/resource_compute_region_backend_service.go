@@ -177,7 +177,7 @@ func resourceComputeRegionBackendServiceCreate(d *schema.ResourceData, meta inte
 
 	d.SetId(service.Name)
 
-	err = computeOperationWait(config.clientCompute, op, project, "Creating Region Backend Service")
+	err = computeOperationWaitConfig(config, op, project, "Creating Region Backend Service")
 	if err != nil {
 		return err
 	}
@@ -285,7 +285,7 @@ func resourceComputeRegionBackendServiceUpdate(d *schema.ResourceData, meta inte
 
 	d.SetId(service.Name)
 
-	err = computeOperationWait(config.clientCompute, op, project, "Updating Backend Service")
+	err = computeOperationWaitConfig(config, op, project, "Updating Backend Service")
 	if err != nil {
 		return err
 	}
@@ -313,7 +313,7 @@ func resourceComputeRegionBackendServiceDelete(d *schema.ResourceData, meta inte
 		return fmt.Errorf("Error deleting backend service: %s", err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Deleting Backend Service")
+	err = computeOperationWaitConfig(config, op, project, "Deleting Backend Service")
 	if err != nil {
 		return err
 	}
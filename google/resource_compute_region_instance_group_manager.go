@@ -20,6 +20,8 @@ var RegionInstanceGroupManagerVersionedFeatures = []Feature{
 	Feature{Version: v0beta, Item: "auto_healing_policies"},
 	Feature{Version: v0beta, Item: "distribution_policy_zones"},
 	Feature{Version: v0beta, Item: "rolling_update_policy"},
+	Feature{Version: v0beta, Item: "stateful_disk"},
+	Feature{Version: v0beta, Item: "version"},
 }
 
 func resourceComputeRegionInstanceGroupManager() *schema.Resource {
@@ -31,6 +33,8 @@ func resourceComputeRegionInstanceGroupManager() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceRegionInstanceGroupManagerStateImporter,
 		},
+
+		CustomizeDiff: customizeDiffInstanceGroupManagerVersions,
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(5 * time.Minute),
 			Update: schema.DefaultTimeout(5 * time.Minute),
@@ -46,10 +50,14 @@ func resourceComputeRegionInstanceGroupManager() *schema.Resource {
 
 			"instance_template": &schema.Schema{
 				Type:             schema.TypeString,
-				Required:         true,
+				Optional:         true,
+				Computed:         true,
+				ConflictsWith:    []string{"version"},
 				DiffSuppressFunc: compareSelfLinkRelativePaths,
 			},
 
+			"version": instanceGroupManagerVersionSchema(),
+
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
@@ -129,6 +137,8 @@ func resourceComputeRegionInstanceGroupManager() *schema.Resource {
 				Optional: true,
 			},
 
+			"stateful_disk": statefulDiskSchema(),
+
 			// If true, the resource will report ready only after no instances are being created.
 			// This will not block future reads if instances are being recreated, and it respects
 			// the "createNoRetry" parameter that's available for this resource.
@@ -242,6 +252,10 @@ func resourceComputeRegionInstanceGroupManagerCreate(d *schema.ResourceData, met
 		return fmt.Errorf("[rolling_update_policy] must be set when 'update_strategy' is set to 'ROLLING_UPDATE'")
 	}
 
+	if _, ok := d.GetOk("version"); !ok && d.Get("instance_template").(string) == "" {
+		return fmt.Errorf("One of 'instance_template' or 'version' must be set")
+	}
+
 	manager := &computeBeta.InstanceGroupManager{
 		Name:                d.Get("name").(string),
 		Description:         d.Get("description").(string),
@@ -251,6 +265,7 @@ func resourceComputeRegionInstanceGroupManagerCreate(d *schema.ResourceData, met
 		NamedPorts:          getNamedPortsBeta(d.Get("named_port").([]interface{})),
 		TargetPools:         convertStringSet(d.Get("target_pools").(*schema.Set)),
 		AutoHealingPolicies: expandAutoHealingPolicies(d.Get("auto_healing_policies").([]interface{})),
+		Versions:            expandVersions(d.Get("version").([]interface{})),
 		DistributionPolicy:  expandDistributionPolicy(d.Get("distribution_policy_zones").(*schema.Set)),
 		// Force send TargetSize to allow size of 0.
 		ForceSendFields: []string{"TargetSize"},
@@ -277,10 +292,18 @@ func resourceComputeRegionInstanceGroupManagerCreate(d *schema.ResourceData, met
 	d.SetId(manager.Name)
 
 	// Wait for the operation to complete
-	err = computeSharedOperationWait(config.clientCompute, op, project, "Creating InstanceGroupManager")
+	err = computeSharedOperationWaitConfig(config, op, project, "Creating InstanceGroupManager")
 	if err != nil {
 		return err
 	}
+
+	if _, ok := d.GetOk("stateful_disk"); ok {
+		managerUrl := instanceGroupManagerBetaUrl(project, "regions/"+d.Get("region").(string), manager.Name)
+		if err := updateStatefulPolicy(d, config, managerUrl, project); err != nil {
+			return err
+		}
+	}
+
 	return resourceComputeRegionInstanceGroupManagerRead(d, config)
 }
 
@@ -326,6 +349,7 @@ func getRegionalManager(d *schema.ResourceData, meta interface{}) (*computeBeta.
 }
 
 func waitForInstancesRefreshFunc(f getInstanceManagerFunc, d *schema.ResourceData, meta interface{}) resource.StateRefreshFunc {
+	var stateful *bool
 	return func() (interface{}, string, error) {
 		m, err := f(d, meta)
 		if err != nil {
@@ -334,9 +358,36 @@ func waitForInstancesRefreshFunc(f getInstanceManagerFunc, d *schema.ResourceDat
 		}
 		if done := m.CurrentActions.None; done < m.TargetSize {
 			return done, "creating", nil
-		} else {
-			return done, "created", nil
 		}
+
+		// Instances with a per-instance config aren't ready until the config
+		// has been applied to them. Only stateful managers can have any, so
+		// the others are never listed.
+		managerUrl := convertSelfLinkToBeta(m.SelfLink)
+		if stateful == nil {
+			_, ok := d.GetOk("stateful_disk")
+			if !ok {
+				ok, err = instanceGroupManagerIsStateful(meta.(*Config), managerUrl)
+				if err != nil {
+					log.Printf("[WARNING] Error in fetching manager %q while waiting for instances to come up: %s\n", managerUrl, err)
+					return m.CurrentActions.None, "creating", nil
+				}
+			}
+			stateful = &ok
+		}
+		if !*stateful {
+			return m.CurrentActions.None, "created", nil
+		}
+
+		pending, err := perInstanceConfigsPending(meta.(*Config), managerUrl)
+		if err != nil {
+			log.Printf("[WARNING] Error in fetching per-instance configs while waiting for instances to come up: %s\n", err)
+			return nil, "error", err
+		}
+		if pending > 0 {
+			return m.CurrentActions.None, "creating", nil
+		}
+		return m.CurrentActions.None, "created", nil
 	}
 }
 
@@ -364,10 +415,20 @@ func resourceComputeRegionInstanceGroupManagerRead(d *schema.ResourceData, meta
 	d.Set("fingerprint", manager.Fingerprint)
 	d.Set("instance_group", manager.InstanceGroup)
 	d.Set("auto_healing_policies", flattenAutoHealingPolicies(manager.AutoHealingPolicies))
+	if _, ok := d.GetOk("version"); ok || usesVersions(manager.Versions) {
+		if err := d.Set("version", flattenVersions(manager.Versions)); err != nil {
+			return err
+		}
+	}
 	if err := d.Set("distribution_policy_zones", flattenDistributionPolicy(manager.DistributionPolicy)); err != nil {
 		return err
 	}
 	d.Set("self_link", ConvertSelfLinkToV1(manager.SelfLink))
+	if _, ok := d.GetOk("stateful_disk"); ok {
+		if err := readStatefulDisks(d, config, instanceGroupManagerBetaUrl(project, "regions/"+GetResourceNameFromSelfLink(manager.Region), manager.Name)); err != nil {
+			return err
+		}
+	}
 
 	if d.Get("wait_for_instances").(bool) {
 		conf := resource.StateChangeConf{
@@ -438,7 +499,7 @@ func resourceComputeRegionInstanceGroupManagerUpdate(d *schema.ResourceData, met
 		}
 
 		// Wait for the operation to complete
-		err = computeSharedOperationWait(config.clientCompute, op, project, "Updating RegionInstanceGroupManager")
+		err = computeSharedOperationWaitConfig(config, op, project, "Updating RegionInstanceGroupManager")
 		if err != nil {
 			return err
 		}
@@ -479,7 +540,7 @@ func resourceComputeRegionInstanceGroupManagerUpdate(d *schema.ResourceData, met
 		}
 
 		// Wait for the operation to complete
-		err = computeSharedOperationWait(config.clientCompute, op, project, "Updating InstanceGroupManager")
+		err = computeSharedOperationWaitConfig(config, op, project, "Updating InstanceGroupManager")
 		if err != nil {
 			return err
 		}
@@ -500,7 +561,7 @@ func resourceComputeRegionInstanceGroupManagerUpdate(d *schema.ResourceData, met
 				return fmt.Errorf("Error updating managed group instances: %s", err)
 			}
 
-			err = computeSharedOperationWait(config.clientCompute, op, project, "Updating managed group instances")
+			err = computeSharedOperationWaitConfig(config, op, project, "Updating managed group instances")
 			if err != nil {
 				return err
 			}
@@ -509,6 +570,32 @@ func resourceComputeRegionInstanceGroupManagerUpdate(d *schema.ResourceData, met
 		d.SetPartial("instance_template")
 	}
 
+	// We will always be in v0beta inside this conditional
+	if d.HasChange("version") && len(d.Get("version").([]interface{})) > 0 {
+		manager := &computeBeta.InstanceGroupManager{
+			Versions: expandVersions(d.Get("version").([]interface{})),
+		}
+
+		// Versions are rolled out to existing instances like a new instance_template
+		// would be, so the update policy goes along with them.
+		if d.Get("update_strategy").(string) == "ROLLING_UPDATE" {
+			manager.UpdatePolicy = expandUpdatePolicy(d.Get("rolling_update_policy").([]interface{}))
+		}
+
+		op, err := config.clientComputeBeta.RegionInstanceGroupManagers.Patch(
+			project, region, d.Id(), manager).Do()
+		if err != nil {
+			return fmt.Errorf("Error updating versions: %s", err)
+		}
+
+		err = computeSharedOperationWaitConfig(config, op, project, "Updating versions")
+		if err != nil {
+			return err
+		}
+
+		d.SetPartial("version")
+	}
+
 	if d.HasChange("named_port") {
 		// Build the parameters for a "SetNamedPorts" request:
 		namedPorts := getNamedPortsBeta(d.Get("named_port").([]interface{}))
@@ -544,7 +631,7 @@ func resourceComputeRegionInstanceGroupManagerUpdate(d *schema.ResourceData, met
 		}
 
 		// Wait for the operation to complete:
-		err = computeSharedOperationWait(config.clientCompute, op, project, "Updating RegionInstanceGroupManager")
+		err = computeSharedOperationWaitConfig(config, op, project, "Updating RegionInstanceGroupManager")
 		if err != nil {
 			return err
 		}
@@ -569,7 +656,7 @@ func resourceComputeRegionInstanceGroupManagerUpdate(d *schema.ResourceData, met
 		}
 
 		// Wait for the operation to complete
-		err = computeSharedOperationWait(config.clientCompute, op, project, "Resizing RegionInstanceGroupManager")
+		err = computeSharedOperationWaitConfig(config, op, project, "Resizing RegionInstanceGroupManager")
 		if err != nil {
 			return err
 		}
@@ -591,7 +678,7 @@ func resourceComputeRegionInstanceGroupManagerUpdate(d *schema.ResourceData, met
 		}
 
 		// Wait for the operation to complete
-		err = computeSharedOperationWait(config.clientCompute, op, project, "Updating AutoHealingPolicies")
+		err = computeSharedOperationWaitConfig(config, op, project, "Updating AutoHealingPolicies")
 		if err != nil {
 			return err
 		}
@@ -599,6 +686,15 @@ func resourceComputeRegionInstanceGroupManagerUpdate(d *schema.ResourceData, met
 		d.SetPartial("auto_healing_policies")
 	}
 
+	if d.HasChange("stateful_disk") {
+		managerUrl := instanceGroupManagerBetaUrl(project, "regions/"+region, d.Id())
+		if err := updateStatefulPolicy(d, config, managerUrl, project); err != nil {
+			return err
+		}
+
+		d.SetPartial("stateful_disk")
+	}
+
 	d.Partial(false)
 
 	return resourceComputeRegionInstanceGroupManagerRead(d, meta)
@@ -628,7 +724,7 @@ func resourceComputeRegionInstanceGroupManagerDelete(d *schema.ResourceData, met
 	}
 
 	// Wait for the operation to complete
-	err = computeSharedOperationWaitTime(config.clientCompute, op, project, int(d.Timeout(schema.TimeoutDelete).Minutes()), "Deleting RegionInstanceGroupManager")
+	err = computeSharedOperationWaitTimeConfig(config, op, project, int(d.Timeout(schema.TimeoutDelete).Minutes()), "Deleting RegionInstanceGroupManager")
 
 	d.SetId("")
 	return nil
@@ -309,6 +309,33 @@ func TestAccRegionInstanceGroupManager_distributionPolicy(t *testing.T) {
 	})
 }
 
+func TestAccRegionInstanceGroupManager_versions(t *testing.T) {
+	t.Parallel()
+
+	template1 := fmt.Sprintf("igm-test-%s", acctest.RandString(10))
+	template2 := fmt.Sprintf("igm-test-%s", acctest.RandString(10))
+	igm := fmt.Sprintf("igm-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRegionInstanceGroupManagerDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccRegionInstanceGroupManager_versions(template1, template2, igm, "fixed = 1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("google_compute_region_instance_group_manager.igm-versions", "version.#", "2"),
+					resource.TestCheckResourceAttr("google_compute_region_instance_group_manager.igm-versions", "version.1.target_size.0.fixed", "1"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccRegionInstanceGroupManager_versions(template1, template2, igm, "percent = 50"),
+				Check:  resource.TestCheckResourceAttr("google_compute_region_instance_group_manager.igm-versions", "version.1.target_size.0.percent", "50"),
+			},
+		},
+	})
+}
+
 func testAccCheckRegionInstanceGroupManagerDestroy(s *terraform.State) error {
 	config := testAccProvider.Meta().(*Config)
 
@@ -1118,3 +1145,54 @@ resource "google_compute_region_instance_group_manager" "igm-rolling-update-poli
 	}
 }`, igm)
 }
+
+func testAccRegionInstanceGroupManager_versions(template1, template2, igm, canarySize string) string {
+	return fmt.Sprintf(`
+resource "google_compute_instance_template" "igm-versions-1" {
+	name = "%s"
+	machine_type = "n1-standard-1"
+	disk {
+		source_image = "debian-cloud/debian-9"
+		auto_delete = true
+		boot = true
+	}
+	network_interface {
+		network = "default"
+	}
+}
+
+resource "google_compute_instance_template" "igm-versions-2" {
+	name = "%s"
+	machine_type = "n1-standard-1"
+	tags = ["canary"]
+	disk {
+		source_image = "debian-cloud/debian-9"
+		auto_delete = true
+		boot = true
+	}
+	network_interface {
+		network = "default"
+	}
+}
+
+resource "google_compute_region_instance_group_manager" "igm-versions" {
+	name = "%s"
+	base_instance_name = "igm-versions"
+	region = "us-central1"
+	target_size = 4
+
+	version {
+		name = "primary"
+		instance_template = "${google_compute_instance_template.igm-versions-1.self_link}"
+	}
+
+	version {
+		name = "canary"
+		instance_template = "${google_compute_instance_template.igm-versions-2.self_link}"
+		target_size {
+			%s
+		}
+	}
+}
+	`, template1, template2, igm, canarySize)
+}
@@ -0,0 +1,496 @@
+package google
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	compute "google.golang.org/api/compute/v1"
+)
+
+// Resource policies are immutable, so every field forces a new resource.
+
+func resourceComputeResourcePolicy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceComputeResourcePolicyCreate,
+		Read:   resourceComputeResourcePolicyRead,
+		Delete: resourceComputeResourcePolicyDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: resourceComputeResourcePolicyImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(240 * time.Second),
+			Delete: schema.DefaultTimeout(240 * time.Second),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateGCPName,
+			},
+			"snapshot_schedule_policy": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"schedule": {
+							Type:     schema.TypeList,
+							Required: true,
+							ForceNew: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"hourly_schedule": {
+										Type:     schema.TypeList,
+										Optional: true,
+										ForceNew: true,
+										MaxItems: 1,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"hours_in_cycle": {
+													Type:         schema.TypeInt,
+													Required:     true,
+													ForceNew:     true,
+													ValidateFunc: validation.IntAtLeast(1),
+												},
+												"start_time": resourcePolicyStartTimeSchema(),
+											},
+										},
+										ConflictsWith: []string{"snapshot_schedule_policy.0.schedule.0.daily_schedule", "snapshot_schedule_policy.0.schedule.0.weekly_schedule"},
+									},
+									"daily_schedule": {
+										Type:     schema.TypeList,
+										Optional: true,
+										ForceNew: true,
+										MaxItems: 1,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"days_in_cycle": {
+													Type:         schema.TypeInt,
+													Required:     true,
+													ForceNew:     true,
+													ValidateFunc: validation.IntBetween(1, 1),
+												},
+												"start_time": resourcePolicyStartTimeSchema(),
+											},
+										},
+										ConflictsWith: []string{"snapshot_schedule_policy.0.schedule.0.hourly_schedule", "snapshot_schedule_policy.0.schedule.0.weekly_schedule"},
+									},
+									"weekly_schedule": {
+										Type:     schema.TypeList,
+										Optional: true,
+										ForceNew: true,
+										MaxItems: 1,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"day_of_weeks": {
+													Type:     schema.TypeSet,
+													Required: true,
+													ForceNew: true,
+													MinItems: 1,
+													MaxItems: 7,
+													Elem: &schema.Resource{
+														Schema: map[string]*schema.Schema{
+															"day": {
+																Type:         schema.TypeString,
+																Required:     true,
+																ForceNew:     true,
+																ValidateFunc: validation.StringInSlice([]string{"MONDAY", "TUESDAY", "WEDNESDAY", "THURSDAY", "FRIDAY", "SATURDAY", "SUNDAY"}, false),
+															},
+															"start_time": resourcePolicyStartTimeSchema(),
+														},
+													},
+												},
+											},
+										},
+										ConflictsWith: []string{"snapshot_schedule_policy.0.schedule.0.hourly_schedule", "snapshot_schedule_policy.0.schedule.0.daily_schedule"},
+									},
+								},
+							},
+						},
+						"retention_policy": {
+							Type:     schema.TypeList,
+							Optional: true,
+							ForceNew: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"max_retention_days": {
+										Type:         schema.TypeInt,
+										Required:     true,
+										ForceNew:     true,
+										ValidateFunc: validation.IntAtLeast(1),
+									},
+									"on_source_disk_delete": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										ForceNew:     true,
+										Default:      "KEEP_AUTO_SNAPSHOTS",
+										ValidateFunc: validation.StringInSlice([]string{"KEEP_AUTO_SNAPSHOTS", "APPLY_RETENTION_POLICY"}, false),
+									},
+								},
+							},
+						},
+						"snapshot_properties": {
+							Type:     schema.TypeList,
+							Optional: true,
+							ForceNew: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"labels": {
+										Type:     schema.TypeMap,
+										Optional: true,
+										ForceNew: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+									"storage_locations": {
+										Type:     schema.TypeSet,
+										Optional: true,
+										ForceNew: true,
+										MaxItems: 1,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+										Set:      schema.HashString,
+									},
+									"guest_flush": {
+										Type:     schema.TypeBool,
+										Optional: true,
+										ForceNew: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"region": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+			},
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"self_link": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourcePolicyStartTimeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+		ForceNew: true,
+		// Schedules start on the hour, in UTC.
+		ValidateFunc: validateRegexp(`^([01][0-9]|2[0-3]):00$`),
+	}
+}
+
+func resourceComputeResourcePolicyCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	snapshotSchedulePolicyProp, err := expandComputeResourcePolicySnapshotSchedulePolicy(d.Get("snapshot_schedule_policy"))
+	if err != nil {
+		return err
+	}
+
+	obj := map[string]interface{}{
+		"name":                   d.Get("name"),
+		"snapshotSchedulePolicy": snapshotSchedulePolicyProp,
+	}
+
+	url, err := replaceVars(d, config, "https://www.googleapis.com/compute/beta/projects/{{project}}/regions/{{region}}/resourcePolicies")
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Creating new ResourcePolicy: %#v", obj)
+	res, err := Post(config, url, obj)
+	if err != nil {
+		return fmt.Errorf("Error creating ResourcePolicy: %s", err)
+	}
+
+	// Store the ID now
+	id, err := replaceVars(d, config, "{{region}}/{{name}}")
+	if err != nil {
+		return fmt.Errorf("Error constructing id: %s", err)
+	}
+	d.SetId(id)
+
+	op := &compute.Operation{}
+	err = Convert(res, op)
+	if err != nil {
+		return err
+	}
+
+	waitErr := computeOperationWaitTimeConfig(
+		config, op, project, "Creating ResourcePolicy",
+		int(d.Timeout(schema.TimeoutCreate).Minutes()))
+
+	if waitErr != nil {
+		// The resource didn't actually create
+		d.SetId("")
+		return waitErr
+	}
+
+	return resourceComputeResourcePolicyRead(d, meta)
+}
+
+func resourceComputeResourcePolicyRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	url, err := replaceVars(d, config, "https://www.googleapis.com/compute/beta/projects/{{project}}/regions/{{region}}/resourcePolicies/{{name}}")
+	if err != nil {
+		return err
+	}
+
+	res, err := Get(config, url)
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("ComputeResourcePolicy %q", d.Id()))
+	}
+
+	d.Set("name", res["name"])
+	if err := d.Set("snapshot_schedule_policy", flattenComputeResourcePolicySnapshotSchedulePolicy(res["snapshotSchedulePolicy"])); err != nil {
+		return fmt.Errorf("Error setting snapshot_schedule_policy: %s", err)
+	}
+	d.Set("region", GetResourceNameFromSelfLink(res["region"].(string)))
+	d.Set("self_link", ConvertSelfLinkToV1(res["selfLink"].(string)))
+	d.Set("project", project)
+
+	return nil
+}
+
+func resourceComputeResourcePolicyDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	url, err := replaceVars(d, config, "https://www.googleapis.com/compute/beta/projects/{{project}}/regions/{{region}}/resourcePolicies/{{name}}")
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Deleting ResourcePolicy %q", d.Id())
+	res, err := Delete(config, url)
+	if err != nil {
+		return fmt.Errorf("Error deleting ResourcePolicy %q: %s", d.Id(), err)
+	}
+
+	op := &compute.Operation{}
+	err = Convert(res, op)
+	if err != nil {
+		return err
+	}
+
+	err = computeOperationWaitTimeConfig(
+		config, op, project, "Deleting ResourcePolicy",
+		int(d.Timeout(schema.TimeoutDelete).Minutes()))
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func resourceComputeResourcePolicyImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	config := meta.(*Config)
+	parseImportId([]string{"projects/(?P<project>[^/]+)/regions/(?P<region>[^/]+)/resourcePolicies/(?P<name>[^/]+)", "(?P<project>[^/]+)/(?P<region>[^/]+)/(?P<name>[^/]+)", "(?P<region>[^/]+)/(?P<name>[^/]+)", "(?P<name>[^/]+)"}, d, config)
+
+	// Replace import id for the resource id
+	id, err := replaceVars(d, config, "{{region}}/{{name}}")
+	if err != nil {
+		return nil, fmt.Errorf("Error constructing id: %s", err)
+	}
+	d.SetId(id)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func expandComputeResourcePolicySnapshotSchedulePolicy(v interface{}) (map[string]interface{}, error) {
+	l := v.([]interface{})
+	if len(l) == 0 || l[0] == nil {
+		return nil, nil
+	}
+	raw := l[0].(map[string]interface{})
+
+	schedule, err := expandComputeResourcePolicySchedule(raw["schedule"])
+	if err != nil {
+		return nil, err
+	}
+
+	policy := map[string]interface{}{
+		"schedule": schedule,
+	}
+
+	if l := raw["retention_policy"].([]interface{}); len(l) > 0 && l[0] != nil {
+		retention := l[0].(map[string]interface{})
+		policy["retentionPolicy"] = map[string]interface{}{
+			"maxRetentionDays":   retention["max_retention_days"],
+			"onSourceDiskDelete": retention["on_source_disk_delete"],
+		}
+	}
+
+	if l := raw["snapshot_properties"].([]interface{}); len(l) > 0 && l[0] != nil {
+		properties := l[0].(map[string]interface{})
+		policy["snapshotProperties"] = map[string]interface{}{
+			"labels":           properties["labels"],
+			"storageLocations": convertStringSet(properties["storage_locations"].(*schema.Set)),
+			"guestFlush":       properties["guest_flush"],
+		}
+	}
+
+	return policy, nil
+}
+
+func expandComputeResourcePolicySchedule(v interface{}) (map[string]interface{}, error) {
+	l := v.([]interface{})
+	if len(l) == 0 || l[0] == nil {
+		return nil, fmt.Errorf("One of hourly_schedule, daily_schedule or weekly_schedule must be set")
+	}
+	raw := l[0].(map[string]interface{})
+
+	if l := raw["hourly_schedule"].([]interface{}); len(l) > 0 && l[0] != nil {
+		hourly := l[0].(map[string]interface{})
+		return map[string]interface{}{
+			"hourlySchedule": map[string]interface{}{
+				"hoursInCycle": hourly["hours_in_cycle"],
+				"startTime":    hourly["start_time"],
+			},
+		}, nil
+	}
+
+	if l := raw["daily_schedule"].([]interface{}); len(l) > 0 && l[0] != nil {
+		daily := l[0].(map[string]interface{})
+		return map[string]interface{}{
+			"dailySchedule": map[string]interface{}{
+				"daysInCycle": daily["days_in_cycle"],
+				"startTime":   daily["start_time"],
+			},
+		}, nil
+	}
+
+	if l := raw["weekly_schedule"].([]interface{}); len(l) > 0 && l[0] != nil {
+		weekly := l[0].(map[string]interface{})
+		days := make([]interface{}, 0)
+		for _, rawDay := range weekly["day_of_weeks"].(*schema.Set).List() {
+			day := rawDay.(map[string]interface{})
+			days = append(days, map[string]interface{}{
+				"day":       day["day"],
+				"startTime": day["start_time"],
+			})
+		}
+		return map[string]interface{}{
+			"weeklySchedule": map[string]interface{}{
+				"dayOfWeeks": days,
+			},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("One of hourly_schedule, daily_schedule or weekly_schedule must be set")
+}
+
+func flattenComputeResourcePolicySnapshotSchedulePolicy(v interface{}) []map[string]interface{} {
+	policy, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := map[string]interface{}{
+		"schedule": flattenComputeResourcePolicySchedule(policy["schedule"]),
+	}
+
+	if retention, ok := policy["retentionPolicy"].(map[string]interface{}); ok {
+		result["retention_policy"] = []map[string]interface{}{
+			{
+				"max_retention_days":    retention["maxRetentionDays"],
+				"on_source_disk_delete": retention["onSourceDiskDelete"],
+			},
+		}
+	}
+
+	if properties, ok := policy["snapshotProperties"].(map[string]interface{}); ok {
+		locations, _ := properties["storageLocations"].([]interface{})
+		result["snapshot_properties"] = []map[string]interface{}{
+			{
+				"labels":            properties["labels"],
+				"storage_locations": schema.NewSet(schema.HashString, locations),
+				"guest_flush":       properties["guestFlush"] == true,
+			},
+		}
+	}
+
+	return []map[string]interface{}{result}
+}
+
+func flattenComputeResourcePolicySchedule(v interface{}) []map[string]interface{} {
+	schedule, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := make(map[string]interface{})
+	if hourly, ok := schedule["hourlySchedule"].(map[string]interface{}); ok {
+		result["hourly_schedule"] = []map[string]interface{}{
+			{
+				"hours_in_cycle": hourly["hoursInCycle"],
+				"start_time":     hourly["startTime"],
+			},
+		}
+	}
+	if daily, ok := schedule["dailySchedule"].(map[string]interface{}); ok {
+		result["daily_schedule"] = []map[string]interface{}{
+			{
+				"days_in_cycle": daily["daysInCycle"],
+				"start_time":    daily["startTime"],
+			},
+		}
+	}
+	if weekly, ok := schedule["weeklySchedule"].(map[string]interface{}); ok {
+		rawDays, _ := weekly["dayOfWeeks"].([]interface{})
+		days := make([]interface{}, 0, len(rawDays))
+		for _, rawDay := range rawDays {
+			day := rawDay.(map[string]interface{})
+			days = append(days, map[string]interface{}{
+				"day":        day["day"],
+				"start_time": day["startTime"],
+			})
+		}
+		result["weekly_schedule"] = []map[string]interface{}{
+			{
+				"day_of_weeks": days,
+			},
+		}
+	}
+
+	return []map[string]interface{}{result}
+}
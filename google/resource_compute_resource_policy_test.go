@@ -0,0 +1,119 @@
+package google
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccComputeResourcePolicy_hourly(t *testing.T) {
+	t.Parallel()
+
+	policyName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeResourcePolicyDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeResourcePolicy_hourly(policyName),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_resource_policy.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccComputeResourcePolicy_weekly(t *testing.T) {
+	t.Parallel()
+
+	policyName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeResourcePolicyDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeResourcePolicy_weekly(policyName),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_resource_policy.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckComputeResourcePolicyDestroy(s *terraform.State) error {
+	config := testAccProvider.Meta().(*Config)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "google_compute_resource_policy" {
+			continue
+		}
+
+		_, err := Get(config, convertSelfLinkToBeta(rs.Primary.Attributes["self_link"]))
+		if err == nil {
+			return fmt.Errorf("Resource policy %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccComputeResourcePolicy_hourly(policyName string) string {
+	return fmt.Sprintf(`
+resource "google_compute_resource_policy" "foobar" {
+	name = "%s"
+	region = "us-central1"
+	snapshot_schedule_policy {
+		schedule {
+			hourly_schedule {
+				hours_in_cycle = 4
+				start_time = "23:00"
+			}
+		}
+		retention_policy {
+			max_retention_days = 10
+			on_source_disk_delete = "APPLY_RETENTION_POLICY"
+		}
+		snapshot_properties {
+			labels {
+				my_label = "value"
+			}
+			storage_locations = ["us"]
+		}
+	}
+}`, policyName)
+}
+
+func testAccComputeResourcePolicy_weekly(policyName string) string {
+	return fmt.Sprintf(`
+resource "google_compute_resource_policy" "foobar" {
+	name = "%s"
+	region = "us-central1"
+	snapshot_schedule_policy {
+		schedule {
+			weekly_schedule {
+				day_of_weeks {
+					day = "MONDAY"
+					start_time = "04:00"
+				}
+				day_of_weeks {
+					day = "THURSDAY"
+					start_time = "16:00"
+				}
+			}
+		}
+	}
+}`, policyName)
+}
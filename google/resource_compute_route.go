@@ -188,7 +188,7 @@ func resourceComputeRouteCreate(d *schema.ResourceData, meta interface{}) error
 	// It probably maybe worked, so store the ID now
 	d.SetId(route.Name)
 
-	err = computeOperationWait(config.clientCompute, op, project, "Creating Route")
+	err = computeOperationWaitConfig(config, op, project, "Creating Route")
 	if err != nil {
 		return err
 	}
@@ -248,7 +248,7 @@ func resourceComputeRouteDelete(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("Error deleting route: %s", err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Deleting Route")
+	err = computeOperationWaitConfig(config, op, project, "Deleting Route")
 	if err != nil {
 		return err
 	}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"google.golang.org/api/compute/v1"
 	"google.golang.org/api/googleapi"
 )
@@ -15,6 +16,7 @@ func resourceComputeRouter() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceComputeRouterCreate,
 		Read:   resourceComputeRouterRead,
+		Update: resourceComputeRouterUpdate,
 		Delete: resourceComputeRouterDelete,
 		Importer: &schema.ResourceImporter{
 			State: resourceComputeRouterImportState,
@@ -58,7 +60,6 @@ func resourceComputeRouter() *schema.Resource {
 				Type:     schema.TypeList,
 				MaxItems: 1,
 				Required: true,
-				ForceNew: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 
@@ -67,6 +68,12 @@ func resourceComputeRouter() *schema.Resource {
 							Required: true,
 							ForceNew: true,
 						},
+
+						"advertise_mode": routerAdvertiseModeSchema(),
+
+						"advertised_groups": routerAdvertisedGroupsSchema(),
+
+						"advertised_ip_ranges": routerAdvertisedIpRangesSchema(),
 					},
 				},
 			},
@@ -115,15 +122,8 @@ func resourceComputeRouterCreate(d *schema.ResourceData, meta interface{}) error
 		router.Description = v.(string)
 	}
 
-	if _, ok := d.GetOk("bgp"); ok {
-		prefix := "bgp.0"
-		if v, ok := d.GetOk(prefix + ".asn"); ok {
-			asn := v.(int)
-			bgp := &compute.RouterBgp{
-				Asn: int64(asn),
-			}
-			router.Bgp = bgp
-		}
+	if v, ok := d.GetOk("bgp"); ok {
+		router.Bgp = expandRouterBgp(v.([]interface{}))
 	}
 
 	op, err := routersService.Insert(project, region, router).Do()
@@ -131,7 +131,7 @@ func resourceComputeRouterCreate(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("Error Inserting Router %s into network %s: %s", name, network.Name, err)
 	}
 	d.SetId(fmt.Sprintf("%s/%s", region, name))
-	err = computeOperationWait(config.clientCompute, op, project, "Inserting Router")
+	err = computeOperationWaitConfig(config, op, project, "Inserting Router")
 	if err != nil {
 		d.SetId("")
 		return fmt.Errorf("Error Waiting to Insert Router %s into network %s: %s", name, network.Name, err)
@@ -176,12 +176,55 @@ func resourceComputeRouterRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("description", router.Description)
 	d.Set("region", region)
 	d.Set("project", project)
-	d.Set("bgp", flattenAsn(router.Bgp.Asn))
+	if err := d.Set("bgp", flattenRouterBgp(router.Bgp)); err != nil {
+		return fmt.Errorf("Error reading bgp: %s", err)
+	}
 	d.SetId(fmt.Sprintf("%s/%s", region, name))
 
 	return nil
 }
 
+func resourceComputeRouterUpdate(d *schema.ResourceData, meta interface{}) error {
+
+	config := meta.(*Config)
+
+	region, err := getRegion(d, config)
+	if err != nil {
+		return err
+	}
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	name := d.Get("name").(string)
+
+	routerLock := getRouterLockName(region, name)
+	mutexKV.Lock(routerLock)
+	defer mutexKV.Unlock(routerLock)
+
+	if d.HasChange("bgp") {
+		bgp := expandRouterBgp(d.Get("bgp").([]interface{}))
+		// Patching merges nested messages, so the custom advertisements
+		// have to be sent even when empty to clear them.
+		bgp.ForceSendFields = []string{"AdvertisedGroups", "AdvertisedIpRanges"}
+
+		log.Printf("[DEBUG] Updating router %s/%s with bgp: %+v", region, name, bgp)
+		op, err := config.clientCompute.Routers.Patch(project, region, name, &compute.Router{Bgp: bgp}).Do()
+		if err != nil {
+			return fmt.Errorf("Error patching router %s/%s: %s", region, name, err)
+		}
+
+		err = computeOperationWaitConfig(config, op, project, "Patching router")
+		if err != nil {
+			return fmt.Errorf("Error waiting to patch router %s/%s: %s", region, name, err)
+		}
+	}
+
+	return resourceComputeRouterRead(d, meta)
+}
+
 func resourceComputeRouterDelete(d *schema.ResourceData, meta interface{}) error {
 
 	config := meta.(*Config)
@@ -209,7 +252,7 @@ func resourceComputeRouterDelete(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("Error Reading Router %s: %s", name, err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Deleting Router")
+	err = computeOperationWaitConfig(config, op, project, "Deleting Router")
 	if err != nil {
 		return fmt.Errorf("Error Waiting to Delete Router %s: %s", name, err)
 	}
@@ -246,10 +289,94 @@ func getRouterLink(config *Config, project string, region string, router string)
 
 }
 
-func flattenAsn(asn int64) []map[string]interface{} {
-	result := make([]map[string]interface{}, 0, 1)
-	r := make(map[string]interface{})
-	r["asn"] = asn
-	result = append(result, r)
+func routerAdvertiseModeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Default:      "DEFAULT",
+		ValidateFunc: validation.StringInSlice([]string{"DEFAULT", "CUSTOM"}, false),
+	}
+}
+
+func routerAdvertisedGroupsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: validation.StringInSlice([]string{"ALL_SUBNETS"}, false),
+		},
+	}
+}
+
+func routerAdvertisedIpRangesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"range": &schema.Schema{
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validateIpCidrRange,
+				},
+				"description": &schema.Schema{
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+func expandRouterBgp(configured []interface{}) *compute.RouterBgp {
+	if len(configured) == 0 || configured[0] == nil {
+		return nil
+	}
+
+	data := configured[0].(map[string]interface{})
+	return &compute.RouterBgp{
+		Asn:                int64(data["asn"].(int)),
+		AdvertiseMode:      data["advertise_mode"].(string),
+		AdvertisedGroups:   convertStringArr(data["advertised_groups"].([]interface{})),
+		AdvertisedIpRanges: expandRouterAdvertisedIpRanges(data["advertised_ip_ranges"].([]interface{})),
+	}
+}
+
+func flattenRouterBgp(bgp *compute.RouterBgp) []map[string]interface{} {
+	if bgp == nil {
+		return nil
+	}
+
+	return []map[string]interface{}{
+		{
+			"asn":                  bgp.Asn,
+			"advertise_mode":       bgp.AdvertiseMode,
+			"advertised_groups":    bgp.AdvertisedGroups,
+			"advertised_ip_ranges": flattenRouterAdvertisedIpRanges(bgp.AdvertisedIpRanges),
+		},
+	}
+}
+
+func expandRouterAdvertisedIpRanges(configured []interface{}) []*compute.RouterAdvertisedIpRange {
+	ranges := make([]*compute.RouterAdvertisedIpRange, 0, len(configured))
+	for _, raw := range configured {
+		data := raw.(map[string]interface{})
+		ranges = append(ranges, &compute.RouterAdvertisedIpRange{
+			Range:       data["range"].(string),
+			Description: data["description"].(string),
+		})
+	}
+	return ranges
+}
+
+func flattenRouterAdvertisedIpRanges(ranges []*compute.RouterAdvertisedIpRange) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(ranges))
+	for _, r := range ranges {
+		result = append(result, map[string]interface{}{
+			"range":       r.Range,
+			"description": r.Description,
+		})
+	}
 	return result
 }
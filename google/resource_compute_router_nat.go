@@ -0,0 +1,541 @@
+package google
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"google.golang.org/api/compute/v1"
+)
+
+// NAT configs are read and patched onto their router as raw JSON.
+
+var (
+	routerNatSubnetworkConfig = []string{
+		"ALL_SUBNETWORKS_ALL_IP_RANGES",
+		"ALL_SUBNETWORKS_ALL_PRIMARY_IP_RANGES",
+		"LIST_OF_SUBNETWORKS",
+	}
+
+	routerNatSubnetworkRangeConfig = []string{
+		"ALL_IP_RANGES",
+		"LIST_OF_SECONDARY_IP_RANGES",
+		"PRIMARY_IP_RANGE",
+	}
+
+	// Maps each timeout in the schema to its field in the API.
+	routerNatTimeoutFields = map[string]string{
+		"udp_idle_timeout_sec":             "udpIdleTimeoutSec",
+		"icmp_idle_timeout_sec":            "icmpIdleTimeoutSec",
+		"tcp_established_idle_timeout_sec": "tcpEstablishedIdleTimeoutSec",
+		"tcp_transitory_idle_timeout_sec":  "tcpTransitoryIdleTimeoutSec",
+	}
+)
+
+func resourceComputeRouterNat() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceComputeRouterNatCreate,
+		Read:   resourceComputeRouterNatRead,
+		Update: resourceComputeRouterNatUpdate,
+		Delete: resourceComputeRouterNatDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceComputeRouterNatImportState,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateRFC1035Name(2, 63),
+			},
+			"router": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"nat_ip_allocate_option": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"MANUAL_ONLY", "AUTO_ONLY"}, false),
+			},
+			"nat_ips": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      selfLinkRelativePathHash,
+			},
+			"source_subnetwork_ip_ranges_to_nat": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(routerNatSubnetworkConfig, false),
+			},
+			"subnetwork": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Set:      routerNatSubnetworkHash,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"source_ip_ranges_to_nat": &schema.Schema{
+							Type:     schema.TypeSet,
+							Required: true,
+							MinItems: 1,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringInSlice(routerNatSubnetworkRangeConfig, false),
+							},
+						},
+						"secondary_ip_range_names": &schema.Schema{
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"min_ports_per_vm": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(2),
+			},
+			"udp_idle_timeout_sec": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"icmp_idle_timeout_sec": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"tcp_established_idle_timeout_sec": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1200,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"tcp_transitory_idle_timeout_sec": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"log_config": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"enable": &schema.Schema{
+							Type:     schema.TypeBool,
+							Required: true,
+						},
+						"filter": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "ALL",
+							ValidateFunc: validation.StringInSlice([]string{"ERRORS_ONLY", "TRANSLATIONS_ONLY", "ALL"}, false),
+						},
+					},
+				},
+			},
+			"project": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"region": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceComputeRouterNatCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	region, err := getRegion(d, config)
+	if err != nil {
+		return err
+	}
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	routerName := d.Get("router").(string)
+	natName := d.Get("name").(string)
+
+	nat, err := expandRouterNat(d)
+	if err != nil {
+		return err
+	}
+
+	routerLock := getRouterLockName(region, routerName)
+	mutexKV.Lock(routerLock)
+	defer mutexKV.Unlock(routerLock)
+
+	routerUrl := routerNatRouterUrl(project, region, routerName)
+	nats, err := getRouterNats(config, routerUrl)
+	if err != nil {
+		return fmt.Errorf("Error Reading router %s/%s: %s", region, routerName, err)
+	}
+
+	for _, raw := range nats {
+		if raw.(map[string]interface{})["name"] == natName {
+			return fmt.Errorf("Router %s has NAT %s already", routerName, natName)
+		}
+	}
+
+	log.Printf("[INFO] Adding NAT %s to router %s/%s", natName, region, routerName)
+	nats = append(nats, nat)
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", region, routerName, natName))
+	if err := patchRouterNats(config, routerUrl, project, nats); err != nil {
+		d.SetId("")
+		return fmt.Errorf("Error patching router %s/%s: %s", region, routerName, err)
+	}
+
+	return resourceComputeRouterNatRead(d, meta)
+}
+
+func resourceComputeRouterNatRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	region, err := getRegion(d, config)
+	if err != nil {
+		return err
+	}
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	routerName := d.Get("router").(string)
+	natName := d.Get("name").(string)
+
+	nats, err := getRouterNats(config, routerNatRouterUrl(project, region, routerName))
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("Router %s/%s", region, routerName))
+	}
+
+	for _, raw := range nats {
+		nat := raw.(map[string]interface{})
+		if nat["name"] != natName {
+			continue
+		}
+
+		d.SetId(fmt.Sprintf("%s/%s/%s", region, routerName, natName))
+		d.Set("nat_ip_allocate_option", nat["natIpAllocateOption"])
+		d.Set("nat_ips", nat["natIps"])
+		d.Set("source_subnetwork_ip_ranges_to_nat", nat["sourceSubnetworkIpRangesToNat"])
+		if err := d.Set("subnetwork", flattenRouterNatSubnetworks(nat["subnetworks"])); err != nil {
+			return fmt.Errorf("Error reading subnetwork: %s", err)
+		}
+		d.Set("min_ports_per_vm", nat["minPortsPerVm"])
+		// Timeouts left at their default aren't always returned.
+		for k, apiKey := range routerNatTimeoutFields {
+			if v, ok := nat[apiKey]; ok {
+				d.Set(k, v)
+			}
+		}
+		if err := d.Set("log_config", flattenRouterNatLogConfig(nat["logConfig"])); err != nil {
+			return fmt.Errorf("Error reading log_config: %s", err)
+		}
+		d.Set("region", region)
+		d.Set("project", project)
+		return nil
+	}
+
+	log.Printf("[WARN] Removing router NAT %s/%s/%s because it is gone", region, routerName, natName)
+	d.SetId("")
+	return nil
+}
+
+func resourceComputeRouterNatUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	region, err := getRegion(d, config)
+	if err != nil {
+		return err
+	}
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	routerName := d.Get("router").(string)
+	natName := d.Get("name").(string)
+
+	nat, err := expandRouterNat(d)
+	if err != nil {
+		return err
+	}
+
+	routerLock := getRouterLockName(region, routerName)
+	mutexKV.Lock(routerLock)
+	defer mutexKV.Unlock(routerLock)
+
+	routerUrl := routerNatRouterUrl(project, region, routerName)
+	nats, err := getRouterNats(config, routerUrl)
+	if err != nil {
+		return fmt.Errorf("Error Reading router %s/%s: %s", region, routerName, err)
+	}
+
+	found := false
+	for i, raw := range nats {
+		if raw.(map[string]interface{})["name"] == natName {
+			nats[i] = nat
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("Router %s/%s has no NAT %s", region, routerName, natName)
+	}
+
+	log.Printf("[INFO] Updating NAT %s on router %s/%s", natName, region, routerName)
+	if err := patchRouterNats(config, routerUrl, project, nats); err != nil {
+		return fmt.Errorf("Error patching router %s/%s: %s", region, routerName, err)
+	}
+
+	return resourceComputeRouterNatRead(d, meta)
+}
+
+func resourceComputeRouterNatDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	region, err := getRegion(d, config)
+	if err != nil {
+		return err
+	}
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	routerName := d.Get("router").(string)
+	natName := d.Get("name").(string)
+
+	routerLock := getRouterLockName(region, routerName)
+	mutexKV.Lock(routerLock)
+	defer mutexKV.Unlock(routerLock)
+
+	routerUrl := routerNatRouterUrl(project, region, routerName)
+	nats, err := getRouterNats(config, routerUrl)
+	if err != nil {
+		if isGoogleApiErrorWithCode(err, 404) {
+			log.Printf("[WARN] Removing router NAT %s because its router %s/%s is gone", natName, region, routerName)
+			return nil
+		}
+
+		return fmt.Errorf("Error Reading Router %s: %s", routerName, err)
+	}
+
+	newNats := make([]interface{}, 0, len(nats))
+	for _, raw := range nats {
+		if raw.(map[string]interface{})["name"] != natName {
+			newNats = append(newNats, raw)
+		}
+	}
+
+	if len(newNats) == len(nats) {
+		log.Printf("[DEBUG] Router %s/%s had no NAT %s already", region, routerName, natName)
+		d.SetId("")
+		return nil
+	}
+
+	log.Printf("[INFO] Removing NAT %s from router %s/%s", natName, region, routerName)
+	if err := patchRouterNats(config, routerUrl, project, newNats); err != nil {
+		return fmt.Errorf("Error patching router %s/%s: %s", region, routerName, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceComputeRouterNatImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("Invalid router NAT specifier. Expecting {region}/{router}/{nat}")
+	}
+
+	d.Set("region", parts[0])
+	d.Set("router", parts[1])
+	d.Set("name", parts[2])
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func routerNatRouterUrl(project, region, router string) string {
+	return fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/regions/%s/routers/%s", project, region, router)
+}
+
+func getRouterNats(config *Config, routerUrl string) ([]interface{}, error) {
+	res, err := Get(config, routerUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	nats, _ := res["nats"].([]interface{})
+	return nats, nil
+}
+
+// patchRouterNats replaces the router's NATs with nats. Callers must hold
+// the router's lock.
+func patchRouterNats(config *Config, routerUrl, project string, nats []interface{}) error {
+	log.Printf("[DEBUG] Updating router %s with NATs: %+v", routerUrl, nats)
+	res, err := Patch(config, routerUrl, map[string]interface{}{"nats": nats}, []string{"nats"}, nil)
+	if err != nil {
+		return err
+	}
+
+	op := &compute.Operation{}
+	if err := Convert(res, op); err != nil {
+		return err
+	}
+
+	return computeOperationWaitConfig(config, op, project, "Patching router")
+}
+
+func expandRouterNat(d *schema.ResourceData) (map[string]interface{}, error) {
+	nat := map[string]interface{}{
+		"name":                          d.Get("name").(string),
+		"natIpAllocateOption":           d.Get("nat_ip_allocate_option").(string),
+		"sourceSubnetworkIpRangesToNat": d.Get("source_subnetwork_ip_ranges_to_nat").(string),
+	}
+	for k, apiKey := range routerNatTimeoutFields {
+		nat[apiKey] = d.Get(k).(int)
+	}
+
+	natIps := convertStringSet(d.Get("nat_ips").(*schema.Set))
+	switch nat["natIpAllocateOption"] {
+	case "MANUAL_ONLY":
+		if len(natIps) == 0 {
+			return nil, fmt.Errorf("nat_ips must be set when nat_ip_allocate_option is MANUAL_ONLY")
+		}
+		nat["natIps"] = natIps
+	case "AUTO_ONLY":
+		if len(natIps) > 0 {
+			return nil, fmt.Errorf("nat_ips can only be set when nat_ip_allocate_option is MANUAL_ONLY")
+		}
+	}
+
+	subnetworks := d.Get("subnetwork").(*schema.Set).List()
+	if nat["sourceSubnetworkIpRangesToNat"] == "LIST_OF_SUBNETWORKS" {
+		if len(subnetworks) == 0 {
+			return nil, fmt.Errorf("subnetwork must be set when source_subnetwork_ip_ranges_to_nat is LIST_OF_SUBNETWORKS")
+		}
+		nat["subnetworks"] = expandRouterNatSubnetworks(subnetworks)
+	} else if len(subnetworks) > 0 {
+		return nil, fmt.Errorf("subnetwork can only be set when source_subnetwork_ip_ranges_to_nat is LIST_OF_SUBNETWORKS")
+	}
+
+	if v, ok := d.GetOk("min_ports_per_vm"); ok {
+		nat["minPortsPerVm"] = v.(int)
+	}
+
+	if v, ok := d.GetOk("log_config"); ok {
+		logConfig := extractFirstMapConfig(v.([]interface{}))
+		nat["logConfig"] = map[string]interface{}{
+			"enable": logConfig["enable"].(bool),
+			"filter": logConfig["filter"].(string),
+		}
+	}
+
+	return nat, nil
+}
+
+func expandRouterNatSubnetworks(subnetworks []interface{}) []interface{} {
+	result := make([]interface{}, 0, len(subnetworks))
+	for _, raw := range subnetworks {
+		subnetwork := raw.(map[string]interface{})
+		s := map[string]interface{}{
+			"name":                subnetwork["name"].(string),
+			"sourceIpRangesToNat": convertStringSet(subnetwork["source_ip_ranges_to_nat"].(*schema.Set)),
+		}
+		if names := convertStringSet(subnetwork["secondary_ip_range_names"].(*schema.Set)); len(names) > 0 {
+			s["secondaryIpRangeNames"] = names
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+func flattenRouterNatSubnetworks(v interface{}) []map[string]interface{} {
+	subnetworks, _ := v.([]interface{})
+	result := make([]map[string]interface{}, 0, len(subnetworks))
+	for _, raw := range subnetworks {
+		subnetwork := raw.(map[string]interface{})
+		result = append(result, map[string]interface{}{
+			"name":                     subnetwork["name"],
+			"source_ip_ranges_to_nat":  subnetwork["sourceIpRangesToNat"],
+			"secondary_ip_range_names": subnetwork["secondaryIpRangeNames"],
+		})
+	}
+	return result
+}
+
+func flattenRouterNatLogConfig(v interface{}) []map[string]interface{} {
+	logConfig, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	return []map[string]interface{}{
+		{
+			"enable": logConfig["enable"],
+			"filter": logConfig["filter"],
+		},
+	}
+}
+
+// routerNatSubnetworkHash hashes subnetworks by their relative path, so that
+// a self link in any API version matches the one returned by the API.
+func routerNatSubnetworkHash(v interface{}) int {
+	var buf bytes.Buffer
+	m := v.(map[string]interface{})
+	if path, err := getRelativePath(m["name"].(string)); err == nil {
+		buf.WriteString(fmt.Sprintf("%s-", path))
+	} else {
+		buf.WriteString(fmt.Sprintf("%s-", m["name"].(string)))
+	}
+
+	// The nested sets are still plain lists when read back from the API, so
+	// sort their values to get the same hash either way.
+	for _, k := range []string{"source_ip_ranges_to_nat", "secondary_ip_range_names"} {
+		var values []string
+		switch v := m[k].(type) {
+		case *schema.Set:
+			values = convertStringSet(v)
+		case []interface{}:
+			values = convertStringArr(v)
+		}
+		sort.Strings(values)
+
+		for _, v := range values {
+			buf.WriteString(fmt.Sprintf("%s-", v))
+		}
+	}
+
+	return hashcode.String(buf.String())
+}
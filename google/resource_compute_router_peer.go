@@ -15,6 +15,7 @@ func resourceComputeRouterPeer() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceComputeRouterPeerCreate,
 		Read:   resourceComputeRouterPeerRead,
+		Update: resourceComputeRouterPeerUpdate,
 		Delete: resourceComputeRouterPeerDelete,
 		Importer: &schema.ResourceImporter{
 			State: resourceComputeRouterPeerImportState,
@@ -55,6 +56,12 @@ func resourceComputeRouterPeer() *schema.Resource {
 				ForceNew: true,
 			},
 
+			"advertise_mode": routerAdvertiseModeSchema(),
+
+			"advertised_groups": routerAdvertisedGroupsSchema(),
+
+			"advertised_ip_ranges": routerAdvertisedIpRangesSchema(),
+
 			"ip_address": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
@@ -136,6 +143,8 @@ func resourceComputeRouterPeerCreate(d *schema.ResourceData, meta interface{}) e
 		peer.AdvertisedRoutePriority = int64(v.(int))
 	}
 
+	expandRouterPeerAdvertisements(d, peer)
+
 	log.Printf("[INFO] Adding peer %s", peerName)
 	peers = append(peers, peer)
 	patchRouter := &compute.Router{
@@ -148,7 +157,7 @@ func resourceComputeRouterPeerCreate(d *schema.ResourceData, meta interface{}) e
 		return fmt.Errorf("Error patching router %s/%s: %s", region, routerName, err)
 	}
 	d.SetId(fmt.Sprintf("%s/%s/%s", region, routerName, peerName))
-	err = computeOperationWait(config.clientCompute, op, project, "Patching router")
+	err = computeOperationWaitConfig(config, op, project, "Patching router")
 	if err != nil {
 		d.SetId("")
 		return fmt.Errorf("Error waiting to patch router %s/%s: %s", region, routerName, err)
@@ -196,6 +205,11 @@ func resourceComputeRouterPeerRead(d *schema.ResourceData, meta interface{}) err
 			d.Set("peer_asn", peer.PeerAsn)
 			d.Set("advertised_route_priority", peer.AdvertisedRoutePriority)
 			d.Set("ip_address", peer.IpAddress)
+			d.Set("advertise_mode", peer.AdvertiseMode)
+			d.Set("advertised_groups", peer.AdvertisedGroups)
+			if err := d.Set("advertised_ip_ranges", flattenRouterAdvertisedIpRanges(peer.AdvertisedIpRanges)); err != nil {
+				return fmt.Errorf("Error reading advertised_ip_ranges: %s", err)
+			}
 			d.Set("region", region)
 			d.Set("project", project)
 			return nil
@@ -207,6 +221,65 @@ func resourceComputeRouterPeerRead(d *schema.ResourceData, meta interface{}) err
 	return nil
 }
 
+func resourceComputeRouterPeerUpdate(d *schema.ResourceData, meta interface{}) error {
+
+	config := meta.(*Config)
+
+	region, err := getRegion(d, config)
+	if err != nil {
+		return err
+	}
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	routerName := d.Get("router").(string)
+	peerName := d.Get("name").(string)
+
+	routerLock := getRouterLockName(region, routerName)
+	mutexKV.Lock(routerLock)
+	defer mutexKV.Unlock(routerLock)
+
+	routersService := config.clientCompute.Routers
+	router, err := routersService.Get(project, region, routerName).Do()
+	if err != nil {
+		return fmt.Errorf("Error Reading router %s/%s: %s", region, routerName, err)
+	}
+
+	var peer *compute.RouterBgpPeer
+	for _, p := range router.BgpPeers {
+		if p.Name == peerName {
+			peer = p
+			break
+		}
+	}
+	if peer == nil {
+		return fmt.Errorf("Router %s/%s has no peer %s", region, routerName, peerName)
+	}
+
+	expandRouterPeerAdvertisements(d, peer)
+
+	log.Printf("[INFO] Updating peer %s", peerName)
+	patchRouter := &compute.Router{
+		BgpPeers: router.BgpPeers,
+	}
+
+	log.Printf("[DEBUG] Updating router %s/%s with peers: %+v", region, routerName, router.BgpPeers)
+	op, err := routersService.Patch(project, region, router.Name, patchRouter).Do()
+	if err != nil {
+		return fmt.Errorf("Error patching router %s/%s: %s", region, routerName, err)
+	}
+
+	err = computeOperationWaitConfig(config, op, project, "Patching router")
+	if err != nil {
+		return fmt.Errorf("Error waiting to patch router %s/%s: %s", region, routerName, err)
+	}
+
+	return resourceComputeRouterPeerRead(d, meta)
+}
+
 func resourceComputeRouterPeerDelete(d *schema.ResourceData, meta interface{}) error {
 
 	config := meta.(*Config)
@@ -267,7 +340,7 @@ func resourceComputeRouterPeerDelete(d *schema.ResourceData, meta interface{}) e
 		return fmt.Errorf("Error patching router %s/%s: %s", region, routerName, err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Patching router")
+	err = computeOperationWaitConfig(config, op, project, "Patching router")
 	if err != nil {
 		return fmt.Errorf("Error waiting to patch router %s/%s: %s", region, routerName, err)
 	}
@@ -288,3 +361,9 @@ func resourceComputeRouterPeerImportState(d *schema.ResourceData, meta interface
 
 	return []*schema.ResourceData{d}, nil
 }
+
+func expandRouterPeerAdvertisements(d *schema.ResourceData, peer *compute.RouterBgpPeer) {
+	peer.AdvertiseMode = d.Get("advertise_mode").(string)
+	peer.AdvertisedGroups = convertStringArr(d.Get("advertised_groups").([]interface{}))
+	peer.AdvertisedIpRanges = expandRouterAdvertisedIpRanges(d.Get("advertised_ip_ranges").([]interface{}))
+}
@@ -28,6 +28,16 @@ func TestAccComputeRouterPeer_basic(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			resource.TestStep{
+				Config: testAccComputeRouterPeerAdvertisements(testId),
+				Check: testAccCheckComputeRouterPeerExists(
+					"google_compute_router_peer.foobar"),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_router_peer.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 			resource.TestStep{
 				Config: testAccComputeRouterPeerKeepRouter(testId),
 				Check: testAccCheckComputeRouterPeerDelete(
@@ -158,6 +168,20 @@ func testAccCheckComputeRouterPeerExists(n string) resource.TestCheckFunc {
 }
 
 func testAccComputeRouterPeerBasic(testId string) string {
+	return testAccComputeRouterPeerConfig(testId, "")
+}
+
+func testAccComputeRouterPeerAdvertisements(testId string) string {
+	return testAccComputeRouterPeerConfig(testId, `
+			advertise_mode = "CUSTOM"
+			advertised_groups = ["ALL_SUBNETS"]
+			advertised_ip_ranges {
+				range = "10.128.0.0/16"
+				description = "on-prem summary"
+			}`)
+}
+
+func testAccComputeRouterPeerConfig(testId, advertisements string) string {
 	return fmt.Sprintf(`
 	        resource "google_compute_network" "foobar" {
 			name = "router-peer-test-%s"
@@ -231,8 +255,9 @@ func testAccComputeRouterPeerBasic(testId string) string {
 			peer_asn = 65515
 			advertised_route_priority = 100
 			interface = "${google_compute_router_interface.foobar.name}"
+			%s
 		}
-	`, testId, testId, testId, testId, testId, testId, testId, testId, testId, testId, testId)
+	`, testId, testId, testId, testId, testId, testId, testId, testId, testId, testId, testId, advertisements)
 }
 
 func testAccComputeRouterPeerKeepRouter(testId string) string {
@@ -63,6 +63,51 @@ func TestAccComputeRouter_noRegion(t *testing.T) {
 	})
 }
 
+func TestAccComputeRouter_advertisements(t *testing.T) {
+	t.Parallel()
+
+	testId := acctest.RandString(10)
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeRouterDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeRouterAdvertisements(testId, "DEFAULT", ""),
+				Check: testAccCheckComputeRouterExists(
+					"google_compute_router.foobar"),
+			},
+			resource.TestStep{
+				Config: testAccComputeRouterAdvertisements(testId, "CUSTOM", `
+				advertised_groups = ["ALL_SUBNETS"]
+				advertised_ip_ranges {
+					range = "10.128.0.0/16"
+					description = "on-prem summary"
+				}
+				advertised_ip_ranges {
+					range = "192.168.0.0/24"
+				}`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeRouterExists(
+						"google_compute_router.foobar"),
+					resource.TestCheckResourceAttr(
+						"google_compute_router.foobar", "bgp.0.advertised_ip_ranges.#", "2"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_router.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			resource.TestStep{
+				Config: testAccComputeRouterAdvertisements(testId, "DEFAULT", ""),
+				Check: resource.TestCheckResourceAttr(
+					"google_compute_router.foobar", "bgp.0.advertised_ip_ranges.#", "0"),
+			},
+		},
+	})
+}
+
 func testAccCheckComputeRouterDestroy(s *terraform.State) error {
 	config := testAccProvider.Meta().(*Config)
 
@@ -176,3 +221,21 @@ func testAccComputeRouterNoRegion(providerRegion string) string {
 		}
 	`, testId, testId, providerRegion, testId)
 }
+
+func testAccComputeRouterAdvertisements(testId, mode, advertisements string) string {
+	return fmt.Sprintf(`
+		resource "google_compute_network" "foobar" {
+			name = "router-test-%s"
+		}
+		resource "google_compute_router" "foobar" {
+			name = "router-test-%s"
+			region = "us-central1"
+			network = "${google_compute_network.foobar.name}"
+			bgp {
+				asn = 64514
+				advertise_mode = "%s"
+				%s
+			}
+		}
+	`, testId, testId, mode, advertisements)
+}
@@ -10,6 +10,12 @@ import (
 	"google.golang.org/api/googleapi"
 )
 
+var SnapshotBaseApiVersion = v1
+var SnapshotVersionedFeatures = []Feature{
+	Feature{Version: v0beta, Item: "snapshot_encryption_key.0.kms_key_self_link"},
+	Feature{Version: v0beta, Item: "source_disk_encryption_key.0.kms_key_self_link"},
+}
+
 func resourceComputeSnapshot() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceComputeSnapshotCreate,
@@ -17,6 +23,8 @@ func resourceComputeSnapshot() *schema.Resource {
 		Delete: resourceComputeSnapshotDelete,
 		Update: resourceComputeSnapshotUpdate,
 
+		CustomizeDiff: customizeDiffEffectiveLabels("labels"),
+
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -32,24 +40,38 @@ func resourceComputeSnapshot() *schema.Resource {
 			},
 
 			"snapshot_encryption_key_raw": &schema.Schema{
-				Type:      schema.TypeString,
-				Optional:  true,
-				ForceNew:  true,
-				Sensitive: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"snapshot_encryption_key"},
 			},
 
+			"snapshot_encryption_key": func() *schema.Schema {
+				s := customerEncryptionKeySchema()
+				s.ConflictsWith = []string{"snapshot_encryption_key_raw"}
+				return s
+			}(),
+
 			"snapshot_encryption_key_sha256": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
 			},
 
 			"source_disk_encryption_key_raw": &schema.Schema{
-				Type:      schema.TypeString,
-				Optional:  true,
-				ForceNew:  true,
-				Sensitive: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"source_disk_encryption_key"},
 			},
 
+			"source_disk_encryption_key": func() *schema.Schema {
+				s := customerEncryptionKeySchema()
+				s.ConflictsWith = []string{"source_disk_encryption_key_raw"}
+				return s
+			}(),
+
 			"source_disk_encryption_key_sha256": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
@@ -85,6 +107,8 @@ func resourceComputeSnapshot() *schema.Resource {
 				Set:      schema.HashString,
 			},
 
+			"effective_labels": effectiveLabelsSchema(),
+
 			"label_fingerprint": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
@@ -128,8 +152,30 @@ func resourceComputeSnapshotCreate(d *schema.ResourceData, meta interface{}) err
 		return err
 	}
 
-	op, err := config.clientCompute.Disks.CreateSnapshot(
-		project, zone, source_disk, snapshot).Do()
+	rawFields := make(map[string]interface{})
+	snapshotEncryptionKey, err := expandCustomerEncryptionKey(d, "snapshot_encryption_key", config)
+	if err != nil {
+		return err
+	}
+	if snapshotEncryptionKey != nil {
+		rawFields["snapshotEncryptionKey"] = snapshotEncryptionKey
+	}
+	sourceDiskEncryptionKey, err := expandCustomerEncryptionKey(d, "source_disk_encryption_key", config)
+	if err != nil {
+		return err
+	}
+	if sourceDiskEncryptionKey != nil {
+		rawFields["sourceDiskEncryptionKey"] = sourceDiskEncryptionKey
+	}
+
+	var op *compute.Operation
+	if len(rawFields) > 0 {
+		url := diskBetaUrl(project, zone, source_disk) + "/createSnapshot"
+		op, err = postWithRawFields(config, url, snapshot, rawFields)
+	} else {
+		op, err = config.clientCompute.Disks.CreateSnapshot(
+			project, zone, source_disk, snapshot).Do()
+	}
 	if err != nil {
 		return fmt.Errorf("Error creating snapshot: %s", err)
 	}
@@ -138,20 +184,20 @@ func resourceComputeSnapshotCreate(d *schema.ResourceData, meta interface{}) err
 	d.SetId(snapshot.Name)
 
 	timeout := int(d.Timeout(schema.TimeoutCreate).Minutes())
-	err = computeOperationWaitTime(config.clientCompute, op, project, "Creating Snapshot", timeout)
+	err = computeOperationWaitTimeConfig(config, op, project, "Creating Snapshot", timeout)
 	if err != nil {
 		return err
 	}
 
 	// Now if labels are set, go ahead and apply them
-	if labels := expandLabels(d); len(labels) > 0 {
+	if labels := expandEffectiveLabels(d, config, "labels"); len(labels) > 0 {
 		// First, read the remote resource in order to find the fingerprint
 		apiSnapshot, err := config.clientCompute.Snapshots.Get(project, d.Id()).Do()
 		if err != nil {
 			return fmt.Errorf("Eror when reading snapshot for label update: %s", err)
 		}
 
-		err = updateLabels(config.clientCompute, project, d.Id(), labels, apiSnapshot.LabelFingerprint, timeout)
+		err = updateLabels(config, project, d.Id(), labels, apiSnapshot.LabelFingerprint, timeout)
 		if err != nil {
 			return err
 		}
@@ -172,14 +218,31 @@ func resourceComputeSnapshotRead(d *schema.ResourceData, meta interface{}) error
 		return err
 	}
 
-	snapshot, err := config.clientCompute.Snapshots.Get(
-		project, d.Id()).Do()
-	if err != nil {
-		return handleNotFoundError(err, d, fmt.Sprintf("Snapshot %q", d.Get("name").(string)))
+	snapshot := &compute.Snapshot{}
+	var rawSnapshot map[string]interface{}
+	switch getComputeApiVersion(d, SnapshotBaseApiVersion, SnapshotVersionedFeatures) {
+	case v1:
+		snapshot, err = config.clientCompute.Snapshots.Get(
+			project, d.Id()).Do()
+		if err != nil {
+			return handleNotFoundError(err, d, fmt.Sprintf("Snapshot %q", d.Get("name").(string)))
+		}
+		rawSnapshot = map[string]interface{}{
+			"snapshotEncryptionKey":   rawCustomerEncryptionKey(snapshot.SnapshotEncryptionKey),
+			"sourceDiskEncryptionKey": rawCustomerEncryptionKey(snapshot.SourceDiskEncryptionKey),
+		}
+	case v0beta:
+		rawSnapshot, err = Get(config, fmt.Sprintf("https://www.googleapis.com/compute/beta/projects/%s/global/snapshots/%s", project, d.Id()))
+		if err != nil {
+			return handleNotFoundError(err, d, fmt.Sprintf("Snapshot %q", d.Get("name").(string)))
+		}
+		if err := Convert(rawSnapshot, snapshot); err != nil {
+			return err
+		}
 	}
 
-	d.Set("self_link", snapshot.SelfLink)
-	d.Set("source_disk_link", snapshot.SourceDisk)
+	d.Set("self_link", ConvertSelfLinkToV1(snapshot.SelfLink))
+	d.Set("source_disk_link", ConvertSelfLinkToV1(snapshot.SourceDisk))
 	d.Set("name", snapshot.Name)
 
 	if snapshot.SnapshotEncryptionKey != nil && snapshot.SnapshotEncryptionKey.Sha256 != "" {
@@ -190,10 +253,14 @@ func resourceComputeSnapshotRead(d *schema.ResourceData, meta interface{}) error
 		d.Set("source_disk_encryption_key_sha256", snapshot.SourceDiskEncryptionKey.Sha256)
 	}
 
-	d.Set("labels", snapshot.Labels)
+	if err := setEffectiveLabels(d, config, "labels", snapshot.Labels); err != nil {
+		return err
+	}
 	d.Set("label_fingerprint", snapshot.LabelFingerprint)
 	d.Set("project", project)
 	d.Set("zone", zone)
+	d.Set("snapshot_encryption_key", flattenCustomerEncryptionKey(d, "snapshot_encryption_key", rawSnapshot["snapshotEncryptionKey"], config))
+	d.Set("source_disk_encryption_key", flattenCustomerEncryptionKey(d, "source_disk_encryption_key", rawSnapshot["sourceDiskEncryptionKey"], config))
 
 	return nil
 }
@@ -208,13 +275,14 @@ func resourceComputeSnapshotUpdate(d *schema.ResourceData, meta interface{}) err
 
 	d.Partial(true)
 
-	if d.HasChange("labels") {
-		err = updateLabels(config.clientCompute, project, d.Id(), expandLabels(d), d.Get("label_fingerprint").(string), int(d.Timeout(schema.TimeoutDelete).Minutes()))
+	if effectiveLabelsHaveChanged(d, "labels") {
+		err = updateLabels(config, project, d.Id(), expandEffectiveLabels(d, config, "labels"), d.Get("label_fingerprint").(string), int(d.Timeout(schema.TimeoutDelete).Minutes()))
 		if err != nil {
 			return err
 		}
 
 		d.SetPartial("labels")
+		d.SetPartial("effective_labels")
 	}
 
 	d.Partial(false)
@@ -243,7 +311,7 @@ func resourceComputeSnapshotDelete(d *schema.ResourceData, meta interface{}) err
 		return fmt.Errorf("Error deleting snapshot: %s", err)
 	}
 
-	err = computeOperationWaitTime(config.clientCompute, op, project, "Deleting Snapshot", int(d.Timeout(schema.TimeoutDelete).Minutes()))
+	err = computeOperationWaitTimeConfig(config, op, project, "Deleting Snapshot", int(d.Timeout(schema.TimeoutDelete).Minutes()))
 	if err != nil {
 		return err
 	}
@@ -252,15 +320,15 @@ func resourceComputeSnapshotDelete(d *schema.ResourceData, meta interface{}) err
 	return nil
 }
 
-func updateLabels(client *compute.Service, project string, resourceId string, labels map[string]string, labelFingerprint string, timeout int) error {
+func updateLabels(config *Config, project string, resourceId string, labels map[string]string, labelFingerprint string, timeout int) error {
 	setLabelsReq := compute.GlobalSetLabelsRequest{
 		Labels:           labels,
 		LabelFingerprint: labelFingerprint,
 	}
-	op, err := client.Snapshots.SetLabels(project, resourceId, &setLabelsReq).Do()
+	op, err := config.clientCompute.Snapshots.SetLabels(project, resourceId, &setLabelsReq).Do()
 	if err != nil {
 		return err
 	}
 
-	return computeOperationWaitTime(client, op, project, "Setting labels on snapshot", timeout)
+	return computeOperationWaitTimeConfig(config, op, project, "Setting labels on snapshot", timeout)
 }
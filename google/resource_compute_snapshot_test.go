@@ -90,6 +90,33 @@ func TestAccComputeSnapshot_encryption(t *testing.T) {
 	})
 }
 
+func TestAccComputeSnapshot_encryptionKms(t *testing.T) {
+	t.Parallel()
+
+	snapshotName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	diskName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	keyRingName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	cryptoKeyName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	var snapshot compute.Snapshot
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeSnapshotDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeSnapshot_encryptionKms(keyRingName, cryptoKeyName, snapshotName, diskName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeSnapshotExists(
+						"google_compute_snapshot.foobar", &snapshot),
+					resource.TestCheckResourceAttrPair("google_compute_snapshot.foobar", "snapshot_encryption_key.0.kms_key_self_link",
+						"google_kms_crypto_key.foobar", "id"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckComputeSnapshotDestroy(s *terraform.State) error {
 	config := testAccProvider.Meta().(*Config)
 
@@ -246,3 +273,30 @@ resource "google_compute_snapshot" "foobar" {
 	snapshot_encryption_key_raw = "SGVsbG8gZnJvbSBHb29nbGUgQ2xvdWQgUGxhdGZvcm0="
 }`, diskName, snapshotName)
 }
+
+func testAccComputeSnapshot_encryptionKms(keyRingName, cryptoKeyName, snapshotName, diskName string) string {
+	return testAccComputeKmsKey(keyRingName, cryptoKeyName) + fmt.Sprintf(`
+resource "google_compute_disk" "foobar" {
+	name = "%s"
+	image = "debian-8-jessie-v20160921"
+	size = 10
+	type = "pd-ssd"
+	zone = "us-central1-a"
+
+	disk_encryption_key {
+		kms_key_self_link = "${google_kms_crypto_key.foobar.id}"
+	}
+
+	depends_on = ["google_kms_crypto_key_iam_member.foobar"]
+}
+
+resource "google_compute_snapshot" "foobar" {
+	name = "%s"
+	source_disk = "${google_compute_disk.foobar.name}"
+	zone = "us-central1-a"
+
+	snapshot_encryption_key {
+		kms_key_self_link = "${google_kms_crypto_key.foobar.id}"
+	}
+}`, diskName, snapshotName)
+}
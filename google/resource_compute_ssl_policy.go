@@ -135,7 +135,7 @@ func resourceComputeSslPolicyCreate(d *schema.ResourceData, meta interface{}) er
 
 	d.SetId(sslPolicy.Name)
 
-	err = computeSharedOperationWaitTime(config.clientCompute, op, project, int(d.Timeout(schema.TimeoutCreate).Minutes()), "Creating SSL Policy")
+	err = computeSharedOperationWaitTimeConfig(config, op, project, int(d.Timeout(schema.TimeoutCreate).Minutes()), "Creating SSL Policy")
 	if err != nil {
 		d.SetId("") // if insert fails, remove from state
 		return err
@@ -204,7 +204,7 @@ func resourceComputeSslPolicyUpdate(d *schema.ResourceData, meta interface{}) er
 		return fmt.Errorf("Error updating SSL Policy: %s", err)
 	}
 
-	err = computeSharedOperationWaitTime(config.clientCompute, op, project, int(d.Timeout(schema.TimeoutUpdate).Minutes()), "Updating SSL Policy")
+	err = computeSharedOperationWaitTimeConfig(config, op, project, int(d.Timeout(schema.TimeoutUpdate).Minutes()), "Updating SSL Policy")
 	if err != nil {
 		return err
 	}
@@ -228,7 +228,7 @@ func resourceComputeSslPolicyDelete(d *schema.ResourceData, meta interface{}) er
 		return fmt.Errorf("Error deleting SSL Policy: %s", err)
 	}
 
-	err = computeSharedOperationWaitTime(config.clientCompute, op, project, int(d.Timeout(schema.TimeoutDelete).Minutes()), "Deleting Subnetwork")
+	err = computeSharedOperationWaitTimeConfig(config, op, project, int(d.Timeout(schema.TimeoutDelete).Minutes()), "Deleting Subnetwork")
 	if err != nil {
 		return err
 	}
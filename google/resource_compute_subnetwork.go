@@ -9,6 +9,7 @@ import (
 	"github.com/apparentlymart/go-cidr/cidr"
 	"github.com/hashicorp/terraform/helper/customdiff"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	computeBeta "google.golang.org/api/compute/v0.beta"
 	"google.golang.org/api/compute/v1"
 	"net"
@@ -19,6 +20,8 @@ var (
 	SubnetworkBaseApiVersion    = v1
 	SubnetworkVersionedFeatures = []Feature{
 		{Version: v0beta, Item: "secondary_ip_range"},
+		{Version: v0beta, Item: "enable_flow_logs"},
+		{Version: v0beta, Item: "log_config"},
 	}
 )
 
@@ -112,6 +115,44 @@ func resourceComputeSubnetwork() *schema.Resource {
 				},
 			},
 
+			"enable_flow_logs": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
+			"log_config": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				// The API fills in defaults once flow logs are enabled.
+				Computed: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"aggregation_interval": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "INTERVAL_5_SEC",
+							ValidateFunc: validation.StringInSlice([]string{
+								"INTERVAL_5_SEC", "INTERVAL_30_SEC", "INTERVAL_1_MIN",
+								"INTERVAL_5_MIN", "INTERVAL_10_MIN", "INTERVAL_15_MIN",
+							}, false),
+						},
+						"flow_sampling": &schema.Schema{
+							Type:         schema.TypeFloat,
+							Optional:     true,
+							Default:      0.5,
+							ValidateFunc: validateFloatBetween(0, 1),
+						},
+						"metadata": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "INCLUDE_ALL_METADATA",
+							ValidateFunc: validation.StringInSlice([]string{"INCLUDE_ALL_METADATA", "EXCLUDE_ALL_METADATA"}, false),
+						},
+					},
+				},
+			},
+
 			"self_link": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
@@ -153,7 +194,20 @@ func resourceComputeSubnetworkCreate(d *schema.ResourceData, meta interface{}) e
 
 	log.Printf("[DEBUG] Subnetwork insert request: %#v", subnetwork)
 
-	op, err := config.clientCompute.Subnetworks.Insert(project, region, subnetwork).Do()
+	var op interface{}
+	if _, ok := d.GetOk("log_config"); ok {
+		url := fmt.Sprintf("https://www.googleapis.com/compute/beta/projects/%s/regions/%s/subnetworks", project, region)
+		op, err = postWithRawFields(config, url, subnetwork, expandSubnetworkFlowLogs(d))
+	} else if d.Get("enable_flow_logs").(bool) {
+		subnetworkV0Beta := &computeBeta.Subnetwork{}
+		if err := Convert(subnetwork, subnetworkV0Beta); err != nil {
+			return err
+		}
+		subnetworkV0Beta.EnableFlowLogs = true
+		op, err = config.clientComputeBeta.Subnetworks.Insert(project, region, subnetworkV0Beta).Do()
+	} else {
+		op, err = config.clientCompute.Subnetworks.Insert(project, region, subnetwork).Do()
+	}
 
 	if err != nil {
 		return fmt.Errorf("Error creating subnetwork: %s", err)
@@ -167,7 +221,7 @@ func resourceComputeSubnetworkCreate(d *schema.ResourceData, meta interface{}) e
 	subnetwork.Region = region
 	d.SetId(createSubnetID(subnetwork))
 
-	err = computeSharedOperationWaitTime(config.clientCompute, op, project, int(d.Timeout(schema.TimeoutCreate).Minutes()), "Creating Subnetwork")
+	err = computeSharedOperationWaitTimeConfig(config, op, project, int(d.Timeout(schema.TimeoutCreate).Minutes()), "Creating Subnetwork")
 	if err != nil {
 		return err
 	}
@@ -210,6 +264,7 @@ func resourceComputeSubnetworkRead(d *schema.ResourceData, meta interface{}) err
 	d.Set("project", project)
 	d.Set("region", region)
 	d.Set("self_link", ConvertSelfLinkToV1(subnetwork.SelfLink))
+	d.Set("fingerprint", subnetwork.Fingerprint)
 
 	return nil
 }
@@ -229,9 +284,24 @@ func resourceComputeSubnetworkReadV0Beta(d *schema.ResourceData, meta interface{
 
 	name := d.Get("name").(string)
 
-	subnetwork, err := config.clientComputeBeta.Subnetworks.Get(project, region, name).Do()
-	if err != nil {
-		return handleNotFoundError(err, d, fmt.Sprintf("Subnetwork %q", name))
+	subnetwork := &computeBeta.Subnetwork{}
+	var rawSubnetwork map[string]interface{}
+	if _, ok := d.GetOk("log_config"); ok {
+		rawSubnetwork, err = Get(config, fmt.Sprintf("https://www.googleapis.com/compute/beta/projects/%s/regions/%s/subnetworks/%s", project, region, name))
+		if err != nil {
+			return handleNotFoundError(err, d, fmt.Sprintf("Subnetwork %q", name))
+		}
+		if err := Convert(rawSubnetwork, subnetwork); err != nil {
+			return err
+		}
+		if err := d.Set("log_config", flattenSubnetworkLogConfig(rawSubnetwork["logConfig"])); err != nil {
+			return fmt.Errorf("Error setting log_config: %s", err)
+		}
+	} else {
+		subnetwork, err = config.clientComputeBeta.Subnetworks.Get(project, region, name).Do()
+		if err != nil {
+			return handleNotFoundError(err, d, fmt.Sprintf("Subnetwork %q", name))
+		}
 	}
 
 	d.Set("name", subnetwork.Name)
@@ -245,12 +315,12 @@ func resourceComputeSubnetworkReadV0Beta(d *schema.ResourceData, meta interface{
 	d.Set("region", region)
 	d.Set("self_link", ConvertSelfLinkToV1(subnetwork.SelfLink))
 	d.Set("fingerprint", subnetwork.Fingerprint)
+	d.Set("enable_flow_logs", subnetwork.EnableFlowLogs)
 
 	return nil
 }
 
 func resourceComputeSubnetworkUpdate(d *schema.ResourceData, meta interface{}) error {
-	computeApiVersion := getComputeApiVersion(d, SubnetworkBaseApiVersion, SubnetworkVersionedFeatures)
 	config := meta.(*Config)
 
 	region, err := getRegion(d, config)
@@ -279,7 +349,7 @@ func resourceComputeSubnetworkUpdate(d *schema.ResourceData, meta interface{}) e
 			return fmt.Errorf("Error updating subnetwork PrivateIpGoogleAccess: %s", err)
 		}
 
-		err = computeSharedOperationWaitTime(config.clientCompute, op, project, int(d.Timeout(schema.TimeoutUpdate).Minutes()), "Updating Subnetwork PrivateIpGoogleAccess")
+		err = computeSharedOperationWaitTimeConfig(config, op, project, int(d.Timeout(schema.TimeoutUpdate).Minutes()), "Updating Subnetwork PrivateIpGoogleAccess")
 		if err != nil {
 			return err
 		}
@@ -298,7 +368,7 @@ func resourceComputeSubnetworkUpdate(d *schema.ResourceData, meta interface{}) e
 			return fmt.Errorf("Error expanding the ip cidr range: %s", err)
 		}
 
-		err = computeSharedOperationWaitTime(config.clientCompute, op, project, int(d.Timeout(schema.TimeoutUpdate).Minutes()), "Expanding Subnetwork IP CIDR range")
+		err = computeSharedOperationWaitTimeConfig(config, op, project, int(d.Timeout(schema.TimeoutUpdate).Minutes()), "Expanding Subnetwork IP CIDR range")
 		if err != nil {
 			return err
 		}
@@ -306,24 +376,45 @@ func resourceComputeSubnetworkUpdate(d *schema.ResourceData, meta interface{}) e
 		d.SetPartial("ip_cidr_range")
 	}
 
-	if d.HasChange("secondary_ip_range") && computeApiVersion == v0beta {
-		v0BetaSubnetwork := &computeBeta.Subnetwork{
-			SecondaryIpRanges: expandSecondaryRangesV0Beta(d.Get("secondary_ip_range").([]interface{})),
-			Fingerprint:       d.Get("fingerprint").(string),
+	if d.HasChange("secondary_ip_range") || d.HasChange("enable_flow_logs") || d.HasChange("log_config") {
+		// Earlier updates change the fingerprint, so the current one is
+		// fetched rather than the one in state.
+		subnetwork, err := config.clientCompute.Subnetworks.Get(project, region, d.Get("name").(string)).Do()
+		if err != nil {
+			return fmt.Errorf("Error reading subnetwork fingerprint: %s", err)
 		}
 
-		op, err := config.clientComputeBeta.Subnetworks.Patch(
-			project, region, d.Get("name").(string), v0BetaSubnetwork).Do()
+		obj := map[string]interface{}{
+			"fingerprint":       subnetwork.Fingerprint,
+			"secondaryIpRanges": expandSecondaryRanges(d.Get("secondary_ip_range").([]interface{})),
+		}
+		for k, v := range expandSubnetworkFlowLogs(d) {
+			obj[k] = v
+		}
+		// Send false rather than null when flow logs are turned off.
+		obj["enableFlowLogs"] = d.Get("enable_flow_logs").(bool)
+		forceSendFields, nullFields := patchFieldLists(d, obj, subnetworkPatchFields)
+
+		url := fmt.Sprintf("https://www.googleapis.com/compute/beta/projects/%s/regions/%s/subnetworks/%s", project, region, d.Get("name").(string))
+		log.Printf("[DEBUG] Patching Subnetwork %q: %#v", d.Id(), obj)
+		res, err := Patch(config, url, obj, forceSendFields, nullFields)
 		if err != nil {
-			return fmt.Errorf("Error updating subnetwork SecondaryIpRanges: %s", err)
+			return fmt.Errorf("Error patching subnetwork: %s", err)
+		}
+
+		op := &compute.Operation{}
+		if err := Convert(res, op); err != nil {
+			return err
 		}
 
-		err = computeSharedOperationWaitTime(config.clientCompute, op, project, int(d.Timeout(schema.TimeoutUpdate).Minutes()), "Updating Subnetwork SecondaryIpRanges")
+		err = computeSharedOperationWaitTimeConfig(config, op, project, int(d.Timeout(schema.TimeoutUpdate).Minutes()), "Patching Subnetwork")
 		if err != nil {
 			return err
 		}
 
 		d.SetPartial("secondary_ip_range")
+		d.SetPartial("enable_flow_logs")
+		d.SetPartial("log_config")
 	}
 
 	d.Partial(false)
@@ -351,7 +442,7 @@ func resourceComputeSubnetworkDelete(d *schema.ResourceData, meta interface{}) e
 		return fmt.Errorf("Error deleting subnetwork: %s", err)
 	}
 
-	err = computeSharedOperationWaitTime(config.clientCompute, op, project, int(d.Timeout(schema.TimeoutDelete).Minutes()), "Deleting Subnetwork")
+	err = computeSharedOperationWaitTimeConfig(config, op, project, int(d.Timeout(schema.TimeoutDelete).Minutes()), "Deleting Subnetwork")
 	if err != nil {
 		return err
 	}
@@ -445,3 +536,44 @@ func isShrinkageIpCidr(old, new, _ interface{}) bool {
 
 	return true
 }
+
+// subnetworkPatchFields maps the fields patched in place to their keys in the
+// request body.
+var subnetworkPatchFields = map[string]string{
+	"secondary_ip_range": "secondaryIpRanges",
+	"enable_flow_logs":   "enableFlowLogs",
+	"log_config":         "logConfig",
+}
+
+func expandSubnetworkFlowLogs(d *schema.ResourceData) map[string]interface{} {
+	flowLogs := make(map[string]interface{})
+	if v, ok := d.GetOk("enable_flow_logs"); ok {
+		flowLogs["enableFlowLogs"] = v.(bool)
+	}
+
+	if v, ok := d.GetOk("log_config"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+		logConfig := v.([]interface{})[0].(map[string]interface{})
+		flowLogs["logConfig"] = map[string]interface{}{
+			"enable":              d.Get("enable_flow_logs").(bool),
+			"aggregationInterval": logConfig["aggregation_interval"],
+			"flowSampling":        logConfig["flow_sampling"],
+			"metadata":            logConfig["metadata"],
+		}
+	}
+	return flowLogs
+}
+
+func flattenSubnetworkLogConfig(v interface{}) []map[string]interface{} {
+	logConfig, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	return []map[string]interface{}{
+		{
+			"aggregation_interval": logConfig["aggregationInterval"],
+			"flow_sampling":        logConfig["flowSampling"],
+			"metadata":             logConfig["metadata"],
+		},
+	}
+}
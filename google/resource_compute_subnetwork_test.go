@@ -167,6 +167,59 @@ func TestAccComputeSubnetwork_secondaryIpRanges(t *testing.T) {
 					testAccCheckComputeSubnetworkHasNotSecondaryIpRange(&subnetwork, "tf-test-secondary-range-update2", "192.168.11.0/24"),
 				),
 			},
+			resource.TestStep{
+				Config: testAccComputeSubnetwork_secondaryIpRanges_removed(cnName, subnetworkName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeSubnetworkExists("google_compute_subnetwork.network-with-private-secondary-ip-ranges", &subnetwork),
+					testAccCheckComputeSubnetworkHasNotSecondaryIpRange(&subnetwork, "tf-test-secondary-range-update1", "192.168.10.0/24"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccComputeSubnetwork_flowLogs(t *testing.T) {
+	t.Parallel()
+
+	var subnetwork compute.Subnetwork
+
+	cnName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	subnetworkName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeSubnetworkDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeSubnetwork_flowLogs(cnName, subnetworkName, true, "INTERVAL_5_SEC", 0.5),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeSubnetworkExists("google_compute_subnetwork.network-with-flow-logs", &subnetwork),
+					resource.TestCheckResourceAttr("google_compute_subnetwork.network-with-flow-logs", "enable_flow_logs", "true"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_subnetwork.network-with-flow-logs",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Beta fields are only read once they are in the config.
+				ImportStateVerifyIgnore: []string{"enable_flow_logs", "log_config"},
+			},
+			resource.TestStep{
+				Config: testAccComputeSubnetwork_flowLogs(cnName, subnetworkName, true, "INTERVAL_1_MIN", 0.2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeSubnetworkExists("google_compute_subnetwork.network-with-flow-logs", &subnetwork),
+					resource.TestCheckResourceAttr("google_compute_subnetwork.network-with-flow-logs", "log_config.0.aggregation_interval", "INTERVAL_1_MIN"),
+					resource.TestCheckResourceAttr("google_compute_subnetwork.network-with-flow-logs", "log_config.0.flow_sampling", "0.2"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccComputeSubnetwork_flowLogs(cnName, subnetworkName, false, "INTERVAL_1_MIN", 0.2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeSubnetworkExists("google_compute_subnetwork.network-with-flow-logs", &subnetwork),
+					resource.TestCheckResourceAttr("google_compute_subnetwork.network-with-flow-logs", "enable_flow_logs", "false"),
+				),
+			},
 		},
 	})
 }
@@ -357,3 +410,42 @@ resource "google_compute_subnetwork" "network-with-private-secondary-ip-ranges"
 }
 `, cnName, subnetworkName)
 }
+
+func testAccComputeSubnetwork_secondaryIpRanges_removed(cnName, subnetworkName string) string {
+	return fmt.Sprintf(`
+resource "google_compute_network" "custom-test" {
+	name = "%s"
+	auto_create_subnetworks = false
+}
+
+resource "google_compute_subnetwork" "network-with-private-secondary-ip-ranges" {
+	name = "%s"
+	ip_cidr_range = "10.2.0.0/16"
+	region = "us-central1"
+	network = "${google_compute_network.custom-test.self_link}"
+}
+`, cnName, subnetworkName)
+}
+
+func testAccComputeSubnetwork_flowLogs(cnName, subnetworkName string, enable bool, interval string, sampling float64) string {
+	return fmt.Sprintf(`
+resource "google_compute_network" "custom-test" {
+	name = "%s"
+	auto_create_subnetworks = false
+}
+
+resource "google_compute_subnetwork" "network-with-flow-logs" {
+	name = "%s"
+	ip_cidr_range = "10.2.0.0/16"
+	region = "us-central1"
+	network = "${google_compute_network.custom-test.self_link}"
+	enable_flow_logs = %t
+
+	log_config {
+		aggregation_interval = "%s"
+		flow_sampling = %g
+		metadata = "INCLUDE_ALL_METADATA"
+	}
+}
+`, cnName, subnetworkName, enable, interval, sampling)
+}
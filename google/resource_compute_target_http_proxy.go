@@ -83,7 +83,7 @@ func resourceComputeTargetHttpProxyCreate(d *schema.ResourceData, meta interface
 		return fmt.Errorf("Error creating TargetHttpProxy: %s", err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Creating Target Http Proxy")
+	err = computeOperationWaitConfig(config, op, project, "Creating Target Http Proxy")
 	if err != nil {
 		return err
 	}
@@ -112,7 +112,7 @@ func resourceComputeTargetHttpProxyUpdate(d *schema.ResourceData, meta interface
 			return fmt.Errorf("Error updating target: %s", err)
 		}
 
-		err = computeOperationWait(config.clientCompute, op, project, "Updating Target Http Proxy")
+		err = computeOperationWaitConfig(config, op, project, "Updating Target Http Proxy")
 		if err != nil {
 			return err
 		}
@@ -165,7 +165,7 @@ func resourceComputeTargetHttpProxyDelete(d *schema.ResourceData, meta interface
 		return fmt.Errorf("Error deleting TargetHttpProxy: %s", err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Deleting Target Http Proxy")
+	err = computeOperationWaitConfig(config, op, project, "Deleting Target Http Proxy")
 	if err != nil {
 		return err
 	}
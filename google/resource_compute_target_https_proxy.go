@@ -102,7 +102,7 @@ func resourceComputeTargetHttpsProxyCreate(d *schema.ResourceData, meta interfac
 		return fmt.Errorf("Error creating TargetHttpsProxy: %s", err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Creating Target Https Proxy")
+	err = computeOperationWaitConfig(config, op, project, "Creating Target Https Proxy")
 	if err != nil {
 		return err
 	}
@@ -131,7 +131,7 @@ func resourceComputeTargetHttpsProxyUpdate(d *schema.ResourceData, meta interfac
 			return fmt.Errorf("Error updating Target HTTPS proxy URL map: %s", err)
 		}
 
-		err = computeOperationWait(config.clientCompute, op, project, "Updating Target Https Proxy URL Map")
+		err = computeOperationWaitConfig(config, op, project, "Updating Target Https Proxy URL Map")
 		if err != nil {
 			return err
 		}
@@ -153,7 +153,7 @@ func resourceComputeTargetHttpsProxyUpdate(d *schema.ResourceData, meta interfac
 			return fmt.Errorf("Error updating Target Https Proxy SSL Certificates: %s", err)
 		}
 
-		err = computeOperationWait(config.clientCompute, op, project, "Updating Target Https Proxy SSL certificates")
+		err = computeOperationWaitConfig(config, op, project, "Updating Target Https Proxy SSL certificates")
 		if err != nil {
 			return err
 		}
@@ -207,7 +207,7 @@ func resourceComputeTargetHttpsProxyDelete(d *schema.ResourceData, meta interfac
 		return fmt.Errorf("Error deleting TargetHttpsProxy: %s", err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Deleting Target Https Proxy")
+	err = computeOperationWaitConfig(config, op, project, "Deleting Target Https Proxy")
 	if err != nil {
 		return err
 	}
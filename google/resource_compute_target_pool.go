@@ -199,7 +199,7 @@ func resourceComputeTargetPoolCreate(d *schema.ResourceData, meta interface{}) e
 	// It probably maybe worked, so store the ID now
 	d.SetId(tpool.Name)
 
-	err = computeOperationWait(config.clientCompute, op, project, "Creating Target Pool")
+	err = computeOperationWaitConfig(config, op, project, "Creating Target Pool")
 	if err != nil {
 		return err
 	}
@@ -276,7 +276,7 @@ func resourceComputeTargetPoolUpdate(d *schema.ResourceData, meta interface{}) e
 			return fmt.Errorf("Error updating health_check: %s", err)
 		}
 
-		err = computeOperationWait(config.clientCompute, op, project, "Updating Target Pool")
+		err = computeOperationWaitConfig(config, op, project, "Updating Target Pool")
 		if err != nil {
 			return err
 		}
@@ -292,7 +292,7 @@ func resourceComputeTargetPoolUpdate(d *schema.ResourceData, meta interface{}) e
 			return fmt.Errorf("Error updating health_check: %s", err)
 		}
 
-		err = computeOperationWait(config.clientCompute, op, project, "Updating Target Pool")
+		err = computeOperationWaitConfig(config, op, project, "Updating Target Pool")
 		if err != nil {
 			return err
 		}
@@ -326,7 +326,7 @@ func resourceComputeTargetPoolUpdate(d *schema.ResourceData, meta interface{}) e
 			return fmt.Errorf("Error updating instances: %s", err)
 		}
 
-		err = computeOperationWait(config.clientCompute, op, project, "Updating Target Pool")
+		err = computeOperationWaitConfig(config, op, project, "Updating Target Pool")
 		if err != nil {
 			return err
 		}
@@ -341,7 +341,7 @@ func resourceComputeTargetPoolUpdate(d *schema.ResourceData, meta interface{}) e
 		if err != nil {
 			return fmt.Errorf("Error updating instances: %s", err)
 		}
-		err = computeOperationWait(config.clientCompute, op, project, "Updating Target Pool")
+		err = computeOperationWaitConfig(config, op, project, "Updating Target Pool")
 		if err != nil {
 			return err
 		}
@@ -359,7 +359,7 @@ func resourceComputeTargetPoolUpdate(d *schema.ResourceData, meta interface{}) e
 			return fmt.Errorf("Error updating backup_pool: %s", err)
 		}
 
-		err = computeOperationWait(config.clientCompute, op, project, "Updating Target Pool")
+		err = computeOperationWaitConfig(config, op, project, "Updating Target Pool")
 		if err != nil {
 			return err
 		}
@@ -437,7 +437,7 @@ func resourceComputeTargetPoolDelete(d *schema.ResourceData, meta interface{}) e
 		return fmt.Errorf("Error deleting TargetPool: %s", err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Deleting Target Pool")
+	err = computeOperationWaitConfig(config, op, project, "Deleting Target Pool")
 	if err != nil {
 		return err
 	}
@@ -86,7 +86,7 @@ func resourceComputeTargetTcpProxyCreate(d *schema.ResourceData, meta interface{
 		return fmt.Errorf("Error creating TargetTcpProxy: %s", err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Creating Target Tcp Proxy")
+	err = computeOperationWaitConfig(config, op, project, "Creating Target Tcp Proxy")
 	if err != nil {
 		return err
 	}
@@ -117,7 +117,7 @@ func resourceComputeTargetTcpProxyUpdate(d *schema.ResourceData, meta interface{
 			return fmt.Errorf("Error updating target: %s", err)
 		}
 
-		err = computeOperationWait(config.clientCompute, op, project, "Updating Target Tcp Proxy")
+		err = computeOperationWaitConfig(config, op, project, "Updating Target Tcp Proxy")
 		if err != nil {
 			return err
 		}
@@ -171,7 +171,7 @@ func resourceComputeTargetTcpProxyDelete(d *schema.ResourceData, meta interface{
 		return fmt.Errorf("Error deleting TargetTcpProxy: %s", err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Deleting Target Tcp Proxy")
+	err = computeOperationWaitConfig(config, op, project, "Deleting Target Tcp Proxy")
 	if err != nil {
 		return err
 	}
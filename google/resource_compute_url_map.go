@@ -8,6 +8,15 @@ import (
 	"google.golang.org/api/compute/v1"
 )
 
+var UrlMapBaseApiVersion = v1
+var UrlMapVersionedFeatures = []Feature{
+	Feature{Version: v0beta, Item: "default_url_redirect"},
+	Feature{Version: v0beta, Item: "header_action"},
+	Feature{Version: v0beta, Item: "path_matcher.*.route_rules"},
+	Feature{Version: v0beta, Item: "test.*.expected_output_url"},
+	Feature{Version: v0beta, Item: "test.*.expected_redirect_response_code"},
+}
+
 func resourceComputeUrlMap() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceComputeUrlMapCreate,
@@ -21,10 +30,13 @@ func resourceComputeUrlMap() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"default_service": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"default_url_redirect"},
 			},
 
+			"default_url_redirect": urlMapDefaultUrlRedirectSchema(),
+
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
@@ -41,6 +53,8 @@ func resourceComputeUrlMap() *schema.Resource {
 				Computed: true,
 			},
 
+			"header_action": urlMapHeaderActionSchema(),
+
 			"host_rule": &schema.Schema{
 				Type:     schema.TypeSet,
 				Optional: true,
@@ -110,6 +124,8 @@ func resourceComputeUrlMap() *schema.Resource {
 								},
 							},
 						},
+
+						"route_rules": urlMapRouteRulesSchema(),
 					},
 				},
 			},
@@ -148,12 +164,25 @@ func resourceComputeUrlMap() *schema.Resource {
 
 						"service": &schema.Schema{
 							Type:     schema.TypeString,
-							Required: true,
+							Optional: true,
+						},
+
+						"expected_output_url": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+
+						"expected_redirect_response_code": &schema.Schema{
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validateUrlMapTestRedirectResponseCode,
 						},
 					},
 				},
 			},
 		},
+
+		CustomizeDiff: customizeDiffUrlMap,
 	}
 }
 
@@ -246,6 +275,10 @@ func createUrlMapTest(v interface{}) *compute.UrlMapTest {
 func resourceComputeUrlMapCreate(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 
+	if err := validateUrlMapTargets(d); err != nil {
+		return err
+	}
+
 	project, err := getProject(d, config)
 	if err != nil {
 		return err
@@ -287,13 +320,41 @@ func resourceComputeUrlMapCreate(d *schema.ResourceData, meta interface{}) error
 		urlMap.Tests[i] = createUrlMapTest(v)
 	}
 
-	op, err := config.clientCompute.UrlMaps.Insert(project, urlMap).Do()
+	rawFields := make(map[string]interface{})
+	if v := expandUrlMapRedirect(d.Get("default_url_redirect")); v != nil {
+		rawFields["defaultUrlRedirect"] = v
+	}
+	if v := expandUrlMapHeaderAction(d.Get("header_action")); v != nil {
+		rawFields["headerAction"] = v
+	}
+	for i, v := range _pathMatchers {
+		if routeRules := expandUrlMapRouteRules(v.(map[string]interface{})["route_rules"]); routeRules != nil {
+			rawFields[fmt.Sprintf("pathMatchers.%d.routeRules", i)] = routeRules
+		}
+	}
+	for i, v := range _tests {
+		_test := v.(map[string]interface{})
+		if url := _test["expected_output_url"].(string); url != "" {
+			rawFields[fmt.Sprintf("tests.%d.expectedOutputUrl", i)] = url
+		}
+		if code := _test["expected_redirect_response_code"].(int); code != 0 {
+			rawFields[fmt.Sprintf("tests.%d.expectedRedirectResponseCode", i)] = code
+		}
+	}
+
+	var op *compute.Operation
+	if len(rawFields) > 0 {
+		url := fmt.Sprintf("https://www.googleapis.com/compute/beta/projects/%s/global/urlMaps", project)
+		op, err = postWithRawFields(config, url, urlMap, rawFields)
+	} else {
+		op, err = config.clientCompute.UrlMaps.Insert(project, urlMap).Do()
+	}
 
 	if err != nil {
 		return fmt.Errorf("Error, failed to insert Url Map %s: %s", name, err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Insert Url Map")
+	err = computeOperationWaitConfig(config, op, project, "Insert Url Map")
 
 	if err != nil {
 		return fmt.Errorf("Error, failed waitng to insert Url Map %s: %s", name, err)
@@ -312,7 +373,8 @@ func resourceComputeUrlMapRead(d *schema.ResourceData, meta interface{}) error {
 
 	name := d.Get("name").(string)
 
-	urlMap, err := config.clientCompute.UrlMaps.Get(project, name).Do()
+	computeApiVersion := getComputeApiVersion(d, UrlMapBaseApiVersion, UrlMapVersionedFeatures)
+	urlMap, rawUrlMap, err := getUrlMap(config, project, name, computeApiVersion)
 
 	if err != nil {
 		return handleNotFoundError(err, d, fmt.Sprintf("URL Map %q", d.Get("name").(string)))
@@ -320,10 +382,12 @@ func resourceComputeUrlMapRead(d *schema.ResourceData, meta interface{}) error {
 
 	d.SetId(name)
 	d.Set("project", project)
-	d.Set("self_link", urlMap.SelfLink)
+	d.Set("self_link", ConvertSelfLinkToV1(urlMap.SelfLink))
 	d.Set("map_id", strconv.FormatUint(urlMap.Id, 10))
 	d.Set("fingerprint", urlMap.Fingerprint)
-	d.Set("default_service", urlMap.DefaultService)
+	d.Set("default_service", ConvertSelfLinkToV1(urlMap.DefaultService))
+	d.Set("default_url_redirect", flattenUrlMapRedirect(rawUrlMap["defaultUrlRedirect"]))
+	d.Set("header_action", flattenUrlMapHeaderAction(rawUrlMap["headerAction"]))
 
 	hostRuleMap := make(map[string]*compute.HostRule)
 	for _, v := range urlMap.HostRules {
@@ -369,6 +433,8 @@ func resourceComputeUrlMapRead(d *schema.ResourceData, meta interface{}) error {
 		pathMatcherMap[v.Name] = v
 	}
 
+	rawPathMatchers := rawUrlMapEntries(rawUrlMap, "pathMatchers", rawUrlMapPathMatcherIdent)
+
 	/* Only read path matchers into our TF state that we have defined */
 	_pathMatchers := d.Get("path_matcher").([]interface{})
 	_newPathMatchers := make([]interface{}, 0)
@@ -379,13 +445,13 @@ func resourceComputeUrlMapRead(d *schema.ResourceData, meta interface{}) error {
 		if pathMatcher, ok := pathMatcherMap[_name]; ok {
 			_newPathMatcher := make(map[string]interface{})
 			_newPathMatcher["name"] = _name
-			_newPathMatcher["default_service"] = pathMatcher.DefaultService
+			_newPathMatcher["default_service"] = ConvertSelfLinkToV1(pathMatcher.DefaultService)
 			_newPathMatcher["description"] = pathMatcher.Description
 
 			_newPathRules := make([]interface{}, len(pathMatcher.PathRules))
 			for ip, pathRule := range pathMatcher.PathRules {
 				_newPathRule := make(map[string]interface{})
-				_newPathRule["service"] = pathRule.Service
+				_newPathRule["service"] = ConvertSelfLinkToV1(pathRule.Service)
 				_paths := make([]interface{}, len(pathRule.Paths))
 
 				for ipp, vpp := range pathRule.Paths {
@@ -398,6 +464,7 @@ func resourceComputeUrlMapRead(d *schema.ResourceData, meta interface{}) error {
 			}
 
 			_newPathMatcher["path_rule"] = _newPathRules
+			_newPathMatcher["route_rules"] = flattenUrlMapRouteRules(rawPathMatchers[_name]["routeRules"])
 			_newPathMatchers = append(_newPathMatchers, _newPathMatcher)
 		}
 	}
@@ -409,6 +476,8 @@ func resourceComputeUrlMapRead(d *schema.ResourceData, meta interface{}) error {
 		testMap[fmt.Sprintf("%s/%s", v.Host, v.Path)] = v
 	}
 
+	rawTests := rawUrlMapEntries(rawUrlMap, "tests", rawUrlMapTestIdent)
+
 	_tests := make([]interface{}, 0)
 	/* Only read tests into our TF state that we have defined */
 	if v, ok := d.GetOk("test"); ok {
@@ -426,7 +495,11 @@ func resourceComputeUrlMapRead(d *schema.ResourceData, meta interface{}) error {
 			_newTest["host"] = _host
 			_newTest["path"] = _path
 			_newTest["description"] = test.Description
-			_newTest["service"] = test.Service
+			_newTest["service"] = ConvertSelfLinkToV1(test.Service)
+
+			rawTest := rawTests[fmt.Sprintf("%s/%s", _host, _path)]
+			_newTest["expected_output_url"] = rawTest["expectedOutputUrl"]
+			_newTest["expected_redirect_response_code"] = flattenUrlMapInt(rawTest["expectedRedirectResponseCode"])
 
 			_newTests = append(_newTests, _newTest)
 		}
@@ -440,13 +513,18 @@ func resourceComputeUrlMapRead(d *schema.ResourceData, meta interface{}) error {
 func resourceComputeUrlMapUpdate(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 
+	if err := validateUrlMapTargets(d); err != nil {
+		return err
+	}
+
 	project, err := getProject(d, config)
 	if err != nil {
 		return err
 	}
 
 	name := d.Get("name").(string)
-	urlMap, err := config.clientCompute.UrlMaps.Get(project, name).Do()
+	computeApiVersion := getComputeApiVersionUpdate(d, UrlMapBaseApiVersion, UrlMapVersionedFeatures, []Feature{})
+	urlMap, rawUrlMap, err := getUrlMap(config, project, name, computeApiVersion)
 	if err != nil {
 		return fmt.Errorf("Error, failed to get Url Map %s: %s", name, err)
 	}
@@ -643,13 +721,19 @@ func resourceComputeUrlMapUpdate(d *schema.ResourceData, meta interface{}) error
 
 		urlMap.Tests = newTests
 	}
-	op, err := config.clientCompute.UrlMaps.Update(project, urlMap.Name, urlMap).Do()
+
+	var op *compute.Operation
+	if rawUrlMap != nil {
+		op, err = updateUrlMapWithRawFields(d, config, project, urlMap, rawUrlMap)
+	} else {
+		op, err = config.clientCompute.UrlMaps.Update(project, name, urlMap).Do()
+	}
 
 	if err != nil {
 		return fmt.Errorf("Error, failed to update Url Map %s: %s", name, err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Update Url Map")
+	err = computeOperationWaitConfig(config, op, project, "Update Url Map")
 
 	if err != nil {
 		return fmt.Errorf("Error, failed waitng to update Url Map %s: %s", name, err)
@@ -658,6 +742,98 @@ func resourceComputeUrlMapUpdate(d *schema.ResourceData, meta interface{}) error
 	return resourceComputeUrlMapRead(d, meta)
 }
 
+// getUrlMap returns the URL map. At v0beta it is read as raw JSON, which is
+// returned as well. It is nil at v1.
+func getUrlMap(config *Config, project, name string, computeApiVersion ApiVersion) (*compute.UrlMap, map[string]interface{}, error) {
+	if computeApiVersion == v1 {
+		urlMap, err := config.clientCompute.UrlMaps.Get(project, name).Do()
+		return urlMap, nil, err
+	}
+
+	rawUrlMap, err := Get(config, urlMapBetaUrl(project, name))
+	if err != nil {
+		return nil, nil, err
+	}
+	urlMap := &compute.UrlMap{}
+	if err := Convert(rawUrlMap, urlMap); err != nil {
+		return nil, nil, err
+	}
+	return urlMap, rawUrlMap, nil
+}
+
+// updateUrlMapWithRawFields sends urlMap to the beta API with its raw fields
+// added to it. Path matchers and tests that
+// aren't in the config are sent as rawUrlMap has them, so that none of
+// their fields are lost.
+func updateUrlMapWithRawFields(d *schema.ResourceData, config *Config, project string, urlMap *compute.UrlMap, rawUrlMap map[string]interface{}) (*compute.Operation, error) {
+	url := urlMapBetaUrl(project, urlMap.Name)
+	body := make(map[string]interface{})
+	if err := Convert(urlMap, &body); err != nil {
+		return nil, err
+	}
+
+	delete(body, "defaultUrlRedirect")
+	if v := expandUrlMapRedirect(d.Get("default_url_redirect")); v != nil {
+		body["defaultUrlRedirect"] = v
+	}
+	delete(body, "headerAction")
+	if v := expandUrlMapHeaderAction(d.Get("header_action")); v != nil {
+		body["headerAction"] = v
+	}
+
+	pathMatchers := make(map[string]map[string]interface{})
+	for _, v := range d.Get("path_matcher").([]interface{}) {
+		_pathMatcher := v.(map[string]interface{})
+		pathMatchers[_pathMatcher["name"].(string)] = _pathMatcher
+	}
+	rawPathMatchers := rawUrlMapEntries(rawUrlMap, "pathMatchers", rawUrlMapPathMatcherIdent)
+	bodyPathMatchers, _ := body["pathMatchers"].([]interface{})
+	for i, v := range bodyPathMatchers {
+		pathMatcher := v.(map[string]interface{})
+		name := rawUrlMapPathMatcherIdent(pathMatcher)
+		if _pathMatcher, ok := pathMatchers[name]; ok {
+			if routeRules := expandUrlMapRouteRules(_pathMatcher["route_rules"]); routeRules != nil {
+				pathMatcher["routeRules"] = routeRules
+			}
+		} else if raw, ok := rawPathMatchers[name]; ok {
+			bodyPathMatchers[i] = raw
+		}
+	}
+
+	tests := make(map[string]map[string]interface{})
+	for _, v := range d.Get("test").([]interface{}) {
+		_test := v.(map[string]interface{})
+		tests[fmt.Sprintf("%s/%s", _test["host"], _test["path"])] = _test
+	}
+	rawTests := rawUrlMapEntries(rawUrlMap, "tests", rawUrlMapTestIdent)
+	bodyTests, _ := body["tests"].([]interface{})
+	for i, v := range bodyTests {
+		test := v.(map[string]interface{})
+		ident := rawUrlMapTestIdent(test)
+		if _test, ok := tests[ident]; ok {
+			if url := _test["expected_output_url"].(string); url != "" {
+				test["expectedOutputUrl"] = url
+			}
+			if code := _test["expected_redirect_response_code"].(int); code != 0 {
+				test["expectedRedirectResponseCode"] = code
+			}
+		} else if raw, ok := rawTests[ident]; ok {
+			bodyTests[i] = raw
+		}
+	}
+
+	res, err := Put(config, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	op := &compute.Operation{}
+	if err := Convert(res, op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
 func resourceComputeUrlMapDelete(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 
@@ -674,7 +850,7 @@ func resourceComputeUrlMapDelete(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("Error, failed to delete Url Map %s: %s", name, err)
 	}
 
-	err = computeOperationWait(config.clientCompute, op, project, "Delete Url Map")
+	err = computeOperationWaitConfig(config, op, project, "Delete Url Map")
 
 	if err != nil {
 		return fmt.Errorf("Error, failed waitng to delete Url Map %s: %s", name, err)
@@ -123,6 +123,77 @@ func TestAccComputeUrlMap_noPathRulesWithUpdate(t *testing.T) {
 	})
 }
 
+func TestAccComputeUrlMap_defaultUrlRedirect(t *testing.T) {
+	t.Parallel()
+
+	umName := fmt.Sprintf("urlmap-test-%s", acctest.RandString(10))
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeUrlMapDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeUrlMap_defaultUrlRedirect(umName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeUrlMapExists(
+						"google_compute_url_map.foobar"),
+					resource.TestCheckResourceAttr(
+						"google_compute_url_map.foobar", "default_url_redirect.0.https_redirect", "true"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "google_compute_url_map.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Beta fields are only read once they are in the config.
+				ImportStateVerifyIgnore: []string{"host_rule", "path_matcher", "test", "default_url_redirect", "header_action"},
+			},
+		},
+	})
+}
+
+func TestAccComputeUrlMap_routeRules(t *testing.T) {
+	t.Parallel()
+
+	bsName := fmt.Sprintf("urlmap-test-%s", acctest.RandString(10))
+	hcName := fmt.Sprintf("urlmap-test-%s", acctest.RandString(10))
+	umName := fmt.Sprintf("urlmap-test-%s", acctest.RandString(10))
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeUrlMapDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeUrlMap_routeRules(bsName, hcName, umName, 90),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeUrlMapExists(
+						"google_compute_url_map.foobar"),
+					resource.TestCheckResourceAttr(
+						"google_compute_url_map.foobar", "path_matcher.0.route_rules.1.route_action.0.weighted_backend_services.0.weight", "90"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccComputeUrlMap_routeRules(bsName, hcName, umName, 50),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeUrlMapExists(
+						"google_compute_url_map.foobar"),
+					resource.TestCheckResourceAttr(
+						"google_compute_url_map.foobar", "path_matcher.0.route_rules.1.route_action.0.weighted_backend_services.0.weight", "50"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccComputeUrlMap_basic1(bsName+"-new", hcName, umName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeUrlMapExists(
+						"google_compute_url_map.foobar"),
+					resource.TestCheckResourceAttr(
+						"google_compute_url_map.foobar", "header_action.#", "0"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckComputeUrlMapDestroy(s *terraform.State) error {
 	config := testAccProvider.Meta().(*Config)
 
@@ -409,3 +480,116 @@ resource "google_compute_url_map" "foobar" {
 }
 `, bsName, hcName, umName)
 }
+
+func testAccComputeUrlMap_defaultUrlRedirect(umName string) string {
+	return fmt.Sprintf(`
+resource "google_compute_url_map" "foobar" {
+	name = "urlmap-test-%s"
+
+	default_url_redirect {
+		https_redirect         = true
+		redirect_response_code = "PERMANENT_REDIRECT"
+		strip_query            = false
+	}
+
+	test {
+		host                            = "mysite.com"
+		path                            = "/home"
+		expected_output_url             = "https://mysite.com/home"
+		expected_redirect_response_code = 308
+	}
+}
+`, umName)
+}
+
+func testAccComputeUrlMap_routeRules(bsName, hcName, umName string, stableWeight int) string {
+	return fmt.Sprintf(`
+resource "google_compute_backend_service" "stable" {
+	name          = "urlmap-test-%s-stable"
+	health_checks = ["${google_compute_http_health_check.zero.self_link}"]
+}
+
+resource "google_compute_backend_service" "canary" {
+	name          = "urlmap-test-%s-canary"
+	health_checks = ["${google_compute_http_health_check.zero.self_link}"]
+}
+
+resource "google_compute_http_health_check" "zero" {
+	name               = "urlmap-test-%s"
+	request_path       = "/"
+	check_interval_sec = 1
+	timeout_sec        = 1
+}
+
+resource "google_compute_url_map" "foobar" {
+	name            = "urlmap-test-%s"
+	default_service = "${google_compute_backend_service.stable.self_link}"
+
+	header_action {
+		response_headers_to_add {
+			header_name  = "X-Served-By"
+			header_value = "terraform"
+			replace      = true
+		}
+		request_headers_to_remove = ["X-Debug"]
+	}
+
+	host_rule {
+		hosts        = ["mysite.com"]
+		path_matcher = "boop"
+	}
+
+	path_matcher {
+		default_service = "${google_compute_backend_service.stable.self_link}"
+		name            = "boop"
+
+		route_rules {
+			priority = 1
+			service  = "${google_compute_backend_service.stable.self_link}"
+
+			match_rules {
+				full_path_match = "/healthz"
+			}
+		}
+
+		route_rules {
+			priority = 2
+
+			match_rules {
+				prefix_match = "/api/"
+				ignore_case  = true
+			}
+
+			route_action {
+				weighted_backend_services {
+					backend_service = "${google_compute_backend_service.stable.self_link}"
+					weight          = %d
+				}
+
+				weighted_backend_services {
+					backend_service = "${google_compute_backend_service.canary.self_link}"
+					weight          = %d
+
+					header_action {
+						request_headers_to_add {
+							header_name  = "X-Canary"
+							header_value = "true"
+						}
+					}
+				}
+
+				url_rewrite {
+					path_prefix_rewrite = "/v2/"
+				}
+			}
+		}
+	}
+
+	test {
+		host    = "mysite.com"
+		path    = "/healthz"
+		service = "${google_compute_backend_service.stable.self_link}"
+	}
+}
+`, bsName, bsName, hcName, umName, stableWeight, 100-stableWeight)
+}
@@ -26,6 +26,8 @@ func resourceDataprocCluster() *schema.Resource {
 		Update: resourceDataprocClusterUpdate,
 		Delete: resourceDataprocClusterDelete,
 
+		CustomizeDiff: customizeDiffEffectiveLabels("labels"),
+
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(15 * time.Minute),
 			Update: schema.DefaultTimeout(5 * time.Minute),
@@ -84,6 +86,8 @@ func resourceDataprocCluster() *schema.Resource {
 				Computed: true,
 			},
 
+			"effective_labels": effectiveLabelsSchema(),
+
 			"cluster_config": {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -389,9 +393,7 @@ func resourceDataprocClusterCreate(d *schema.ResourceData, meta interface{}) err
 		return err
 	}
 
-	if _, ok := d.GetOk("labels"); ok {
-		cluster.Labels = expandLabels(d)
-	}
+	cluster.Labels = expandEffectiveLabels(d, config, "labels")
 
 	// Checking here caters for the case where the user does not specify cluster_config
 	// at all, as well where it is simply missing from the gce_cluster_config
@@ -410,7 +412,7 @@ func resourceDataprocClusterCreate(d *schema.ResourceData, meta interface{}) err
 
 	// Wait until it's created
 	timeoutInMinutes := int(d.Timeout(schema.TimeoutCreate).Minutes())
-	waitErr := dataprocClusterOperationWait(config, op, "creating Dataproc cluster", timeoutInMinutes, 3)
+	waitErr := dataprocClusterOperationWait(config, op, "creating Dataproc cluster", timeoutInMinutes)
 	if waitErr != nil {
 		// The resource didn't actually create
 		d.SetId("")
@@ -625,13 +627,8 @@ func resourceDataprocClusterUpdate(d *schema.ResourceData, meta interface{}) err
 
 	updMask := []string{}
 
-	if d.HasChange("labels") {
-		v := d.Get("labels")
-		m := make(map[string]string)
-		for k, val := range v.(map[string]interface{}) {
-			m[k] = val.(string)
-		}
-		cluster.Labels = m
+	if effectiveLabelsHaveChanged(d, "labels") {
+		cluster.Labels = expandEffectiveLabels(d, config, "labels")
 
 		updMask = append(updMask, "labels")
 	}
@@ -663,7 +660,7 @@ func resourceDataprocClusterUpdate(d *schema.ResourceData, meta interface{}) err
 		}
 
 		// Wait until it's updated
-		waitErr := dataprocClusterOperationWait(config, op, "updating Dataproc cluster ", timeoutInMinutes, 2)
+		waitErr := dataprocClusterOperationWait(config, op, "updating Dataproc cluster ", timeoutInMinutes)
 		if waitErr != nil {
 			return waitErr
 		}
@@ -694,7 +691,9 @@ func resourceDataprocClusterRead(d *schema.ResourceData, meta interface{}) error
 	d.Set("name", cluster.ClusterName)
 	d.Set("project", project)
 	d.Set("region", region)
-	d.Set("labels", cluster.Labels)
+	if err := setEffectiveLabels(d, config, "labels", cluster.Labels); err != nil {
+		return err
+	}
 
 	cfg, err := flattenClusterConfig(d, cluster.Config)
 	if err != nil {
@@ -848,7 +847,7 @@ func resourceDataprocClusterDelete(d *schema.ResourceData, meta interface{}) err
 	}
 
 	// Wait until it's deleted
-	waitErr := dataprocClusterOperationWait(config, op, "deleting Dataproc cluster", timeoutInMinutes, 3)
+	waitErr := dataprocClusterOperationWait(config, op, "deleting Dataproc cluster", timeoutInMinutes)
 	if waitErr != nil {
 		return waitErr
 	}
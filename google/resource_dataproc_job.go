@@ -17,6 +17,8 @@ func resourceDataprocJob() *schema.Resource {
 		Read:   resourceDataprocJobRead,
 		Delete: resourceDataprocJobDelete,
 
+		CustomizeDiff: customizeDiffEffectiveLabels("labels"),
+
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(20 * time.Minute),
 			Delete: schema.DefaultTimeout(20 * time.Minute),
@@ -137,6 +139,8 @@ func resourceDataprocJob() *schema.Resource {
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
 
+			"effective_labels": forceNewEffectiveLabelsSchema(),
+
 			"scheduling": {
 				Type:        schema.TypeList,
 				Description: "Optional. Job scheduling configuration.",
@@ -199,9 +203,7 @@ func resourceDataprocJobCreate(d *schema.ResourceData, meta interface{}) error {
 	if v, ok := d.GetOk("reference.0.job_id"); ok {
 		submitReq.Job.Reference.JobId = v.(string)
 	}
-	if _, ok := d.GetOk("labels"); ok {
-		submitReq.Job.Labels = expandLabels(d)
-	}
+	submitReq.Job.Labels = expandEffectiveLabels(d, config, "labels")
 
 	if v, ok := d.GetOk("pyspark_config"); ok {
 		jobConfCount++
@@ -271,7 +273,9 @@ func resourceDataprocJobRead(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	d.Set("force_delete", d.Get("force_delete"))
-	d.Set("labels", job.Labels)
+	if err := setEffectiveLabels(d, config, "labels", job.Labels); err != nil {
+		return err
+	}
 	d.Set("driver_output_resource_uri", job.DriverOutputResourceUri)
 	d.Set("driver_controls_files_uri", job.DriverControlFilesUri)
 
@@ -322,7 +326,7 @@ func resourceDataprocJobDelete(d *schema.ResourceData, meta interface{}) error {
 		// be cancelled. We do however wait for the state to be one that is
 		// at least not active
 		waitErr := dataprocJobOperationWait(config, region, project, d.Id(),
-			"Cancelling Dataproc job", timeoutInMinutes, 1)
+			"Cancelling Dataproc job", timeoutInMinutes)
 		if waitErr != nil {
 			return waitErr
 		}
@@ -337,7 +341,7 @@ func resourceDataprocJobDelete(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	waitErr := dataprocDeleteOperationWait(config, region, project, d.Id(),
-		"Deleting Dataproc job", timeoutInMinutes, 1)
+		"Deleting Dataproc job", timeoutInMinutes)
 	if waitErr != nil {
 		return waitErr
 	}
@@ -308,7 +308,7 @@ func testAccCheckDataprocJobCompletesSuccessfully(n string, job *dataproc.Job) r
 
 		jobCompleteTimeoutMins := 5
 		waitErr := dataprocJobOperationWait(config, region, project, job.Reference.JobId,
-			"Awaiting Dataproc job completion", jobCompleteTimeoutMins, 1)
+			"Awaiting Dataproc job completion", jobCompleteTimeoutMins)
 		if waitErr != nil {
 			return waitErr
 		}
@@ -30,6 +30,8 @@ func resourceGoogleProject() *schema.Resource {
 		},
 		MigrateState: resourceGoogleProjectMigrateState,
 
+		CustomizeDiff: customizeDiffEffectiveLabels("labels"),
+
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
 				Type:     schema.TypeString,
@@ -88,6 +90,7 @@ func resourceGoogleProject() *schema.Resource {
 				Elem:     &schema.Schema{Type: schema.TypeString},
 				Set:      schema.HashString,
 			},
+			"effective_labels": effectiveLabelsSchema(),
 		},
 	}
 }
@@ -107,9 +110,7 @@ func resourceGoogleProjectCreate(d *schema.ResourceData, meta interface{}) error
 
 	getParentResourceId(d, project)
 
-	if _, ok := d.GetOk("labels"); ok {
-		project.Labels = expandLabels(d)
-	}
+	project.Labels = expandEffectiveLabels(d, config, "labels")
 
 	op, err := config.clientResourceManager.Projects.Create(project).Do()
 	if err != nil {
@@ -176,7 +177,9 @@ func resourceGoogleProjectRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("project_id", pid)
 	d.Set("number", strconv.FormatInt(int64(p.ProjectNumber), 10))
 	d.Set("name", p.Name)
-	d.Set("labels", p.Labels)
+	if err := setEffectiveLabels(d, config, "labels", p.Labels); err != nil {
+		return err
+	}
 
 	if p.Parent != nil {
 		switch p.Parent.Type {
@@ -291,14 +294,15 @@ func resourceGoogleProjectUpdate(d *schema.ResourceData, meta interface{}) error
 	}
 
 	// Project Labels have changed
-	if ok := d.HasChange("labels"); ok {
-		p.Labels = expandLabels(d)
+	if effectiveLabelsHaveChanged(d, "labels") {
+		p.Labels = expandEffectiveLabels(d, config, "labels")
 
 		// Do Update on project
 		p, err = config.clientResourceManager.Projects.Update(p.ProjectId, p).Do()
 		if err != nil {
 			return fmt.Errorf("Error updating project %q: %s", project_name, err)
 		}
+		d.Set("effective_labels", p.Labels)
 	}
 	d.Partial(false)
 
@@ -345,7 +349,7 @@ func forceDeleteComputeNetwork(projectId, networkName string, config *Config) er
 			if err != nil {
 				return fmt.Errorf("Error deleting firewall: %s", err)
 			}
-			err = computeSharedOperationWait(config.clientCompute, op, projectId, "Deleting Firewall")
+			err = computeSharedOperationWaitConfig(config, op, projectId, "Deleting Firewall")
 			if err != nil {
 				return err
 			}
@@ -56,7 +56,7 @@ func resourceIamBindingCreate(newUpdaterFunc newResourceIamUpdaterFunc) schema.C
 		}
 
 		p := getResourceIamBinding(d)
-		err = iamPolicyReadModifyWrite(updater, func(ep *cloudresourcemanager.Policy) error {
+		err = iamPolicyBatchedReadModifyWrite(config, updater, func(ep *cloudresourcemanager.Policy) error {
 			// Creating a binding does not remove existing members if they are not in the provided members list.
 			// This prevents removing existing permission without the user's knowledge.
 			// Instead, a diff is shown in that case after creation. Subsequent calls to update will remove any
@@ -160,7 +160,7 @@ func resourceIamBindingUpdate(newUpdaterFunc newResourceIamUpdaterFunc) schema.U
 		}
 
 		binding := getResourceIamBinding(d)
-		err = iamPolicyReadModifyWrite(updater, func(p *cloudresourcemanager.Policy) error {
+		err = iamPolicyBatchedReadModifyWrite(config, updater, func(p *cloudresourcemanager.Policy) error {
 			var found bool
 			for pos, b := range p.Bindings {
 				if b.Role != binding.Role {
@@ -192,7 +192,7 @@ func resourceIamBindingDelete(newUpdaterFunc newResourceIamUpdaterFunc) schema.D
 		}
 
 		binding := getResourceIamBinding(d)
-		err = iamPolicyReadModifyWrite(updater, func(p *cloudresourcemanager.Policy) error {
+		err = iamPolicyBatchedReadModifyWrite(config, updater, func(p *cloudresourcemanager.Policy) error {
 			toRemove := -1
 			for pos, b := range p.Bindings {
 				if b.Role != binding.Role {
@@ -90,7 +90,7 @@ func resourceIamMemberCreate(newUpdaterFunc newResourceIamUpdaterFunc) schema.Cr
 		}
 
 		p := getResourceIamMember(d)
-		err = iamPolicyReadModifyWrite(updater, func(ep *cloudresourcemanager.Policy) error {
+		err = iamPolicyBatchedReadModifyWrite(config, updater, func(ep *cloudresourcemanager.Policy) error {
 			// Merge the bindings together
 			ep.Bindings = mergeBindings(append(ep.Bindings, p))
 			return nil
@@ -163,7 +163,7 @@ func resourceIamMemberDelete(newUpdaterFunc newResourceIamUpdaterFunc) schema.De
 		}
 
 		member := getResourceIamMember(d)
-		err = iamPolicyReadModifyWrite(updater, func(p *cloudresourcemanager.Policy) error {
+		err = iamPolicyBatchedReadModifyWrite(config, updater, func(p *cloudresourcemanager.Policy) error {
 			bindingToRemove := -1
 			for pos, b := range p.Bindings {
 				if b.Role != member.Role {
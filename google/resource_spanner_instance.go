@@ -24,6 +24,8 @@ func resourceSpannerInstance() *schema.Resource {
 			State: resourceSpannerInstanceImportState,
 		},
 
+		CustomizeDiff: customizeDiffEffectiveLabels("labels"),
+
 		Schema: map[string]*schema.Schema{
 
 			"config": &schema.Schema{
@@ -86,6 +88,8 @@ func resourceSpannerInstance() *schema.Resource {
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
 
+			"effective_labels": effectiveLabelsSchema(),
+
 			"project": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -114,9 +118,7 @@ func resourceSpannerInstanceCreate(d *schema.ResourceData, meta interface{}) err
 		d.Set("name", cir.InstanceId)
 	}
 
-	if v, ok := d.GetOk("labels"); ok {
-		cir.Instance.Labels = convertStringMap(v.(map[string]interface{}))
-	}
+	cir.Instance.Labels = expandEffectiveLabels(d, config, "labels")
 
 	id, err := buildSpannerInstanceId(d, config)
 	if err != nil {
@@ -166,7 +168,9 @@ func resourceSpannerInstanceRead(d *schema.ResourceData, meta interface{}) error
 	}
 
 	d.Set("config", GetResourceNameFromSelfLink(instance.Config))
-	d.Set("labels", instance.Labels)
+	if err := setEffectiveLabels(d, config, "labels", instance.Labels); err != nil {
+		return err
+	}
 	d.Set("display_name", instance.DisplayName)
 	d.Set("num_nodes", instance.NodeCount)
 	d.Set("state", instance.State)
@@ -196,9 +200,9 @@ func resourceSpannerInstanceUpdate(d *schema.ResourceData, meta interface{}) err
 		fieldMask = append(fieldMask, "displayName")
 		uir.Instance.DisplayName = d.Get("display_name").(string)
 	}
-	if d.HasChange("labels") {
+	if effectiveLabelsHaveChanged(d, "labels") {
 		fieldMask = append(fieldMask, "labels")
-		uir.Instance.Labels = convertStringMap(d.Get("labels").(map[string]interface{}))
+		uir.Instance.Labels = expandEffectiveLabels(d, config, "labels")
 	}
 
 	uir.FieldMask = strings.Join(fieldMask, ",")
@@ -26,6 +26,7 @@ func resourceStorageBucket() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceStorageBucketStateImporter,
 		},
+		CustomizeDiff: customizeDiffEffectiveLabels("labels"),
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
@@ -46,6 +47,8 @@ func resourceStorageBucket() *schema.Resource {
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
 
+			"effective_labels": effectiveLabelsSchema(),
+
 			"location": &schema.Schema{
 				Type:     schema.TypeString,
 				Default:  "US",
@@ -251,7 +254,7 @@ func resourceStorageBucketCreate(d *schema.ResourceData, meta interface{}) error
 	// Create a bucket, setting the acl, location and name.
 	sb := &storage.Bucket{
 		Name:     bucket,
-		Labels:   expandLabels(d),
+		Labels:   expandEffectiveLabels(d, config, "labels"),
 		Location: location,
 	}
 
@@ -373,10 +376,19 @@ func resourceStorageBucketUpdate(d *schema.ResourceData, meta interface{}) error
 		}
 	}
 
-	if d.HasChange("labels") {
-		sb.Labels = expandLabels(d)
+	if effectiveLabelsHaveChanged(d, "labels") {
+		sb.Labels = expandEffectiveLabels(d, config, "labels")
 		if len(sb.Labels) == 0 {
 			sb.NullFields = append(sb.NullFields, "Labels")
+		} else {
+			// Patching merges labels into the existing ones, so any that
+			// were removed have to be nulled out explicitly.
+			old, _ := d.GetChange("effective_labels")
+			for k := range old.(map[string]interface{}) {
+				if _, ok := sb.Labels[k]; !ok {
+					sb.NullFields = append(sb.NullFields, "Labels."+k)
+				}
+			}
 		}
 	}
 
@@ -434,7 +446,9 @@ func resourceStorageBucketRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("logging", flattenBucketLogging(res.Logging))
 	d.Set("versioning", flattenBucketVersioning(res.Versioning))
 	d.Set("lifecycle_rule", flattenBucketLifecycle(res.Lifecycle))
-	d.Set("labels", res.Labels)
+	if err := setEffectiveLabels(d, config, "labels", res.Labels); err != nil {
+		return err
+	}
 	d.SetId(res.Id)
 	return nil
 }
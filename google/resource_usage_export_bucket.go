@@ -73,7 +73,7 @@ func resourceProjectUsageBucketCreate(d *schema.ResourceData, meta interface{})
 		return err
 	}
 	d.SetId(project)
-	err = computeOperationWait(config.clientCompute, op, project, "Setting usage export bucket.")
+	err = computeOperationWaitConfig(config, op, project, "Setting usage export bucket.")
 	if err != nil {
 		d.SetId("")
 		return err
@@ -93,7 +93,7 @@ func resourceProjectUsageBucketDelete(d *schema.ResourceData, meta interface{})
 		return err
 	}
 	d.SetId(project)
-	err = computeOperationWait(config.clientCompute, op, project, "Setting usage export bucket.")
+	err = computeOperationWaitConfig(config, op, project, "Setting usage export bucket.")
 	if err != nil {
 		return err
 	}
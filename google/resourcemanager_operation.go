@@ -1,11 +1,6 @@
 package google
 
 import (
-	"fmt"
-	"log"
-	"time"
-
-	"github.com/hashicorp/terraform/helper/resource"
 	"google.golang.org/api/cloudresourcemanager/v1"
 	resourceManagerV2Beta1 "google.golang.org/api/cloudresourcemanager/v2beta1"
 )
@@ -15,26 +10,33 @@ type ResourceManagerOperationWaiter struct {
 	Op      *cloudresourcemanager.Operation
 }
 
-func (w *ResourceManagerOperationWaiter) RefreshFunc() resource.StateRefreshFunc {
-	return func() (interface{}, string, error) {
-		op, err := w.Service.Operations.Get(w.Op.Name).Do()
+func (w *ResourceManagerOperationWaiter) QueryOp() error {
+	op, err := w.Service.Operations.Get(w.Op.Name).Do()
+	if err != nil {
+		return err
+	}
 
-		if err != nil {
-			return nil, "", err
-		}
+	w.Op = op
+	return nil
+}
 
-		log.Printf("[DEBUG] Got %v while polling for operation %s's 'done' status", op.Done, w.Op.Name)
+func (w *ResourceManagerOperationWaiter) IsDone() bool {
+	return w.Op.Done
+}
 
-		return op, fmt.Sprint(op.Done), nil
+func (w *ResourceManagerOperationWaiter) OpError() error {
+	if w.Op.Error != nil {
+		return OperationStatusError{Code: w.Op.Error.Code, Message: w.Op.Error.Message}
 	}
+	return nil
 }
 
-func (w *ResourceManagerOperationWaiter) Conf() *resource.StateChangeConf {
-	return &resource.StateChangeConf{
-		Pending: []string{"false"},
-		Target:  []string{"true"},
-		Refresh: w.RefreshFunc(),
-	}
+func (w *ResourceManagerOperationWaiter) State() string {
+	return doneState(w.Op.Done)
+}
+
+func (w *ResourceManagerOperationWaiter) OpName() string {
+	return w.Op.Name
 }
 
 func resourceManagerOperationWait(config *Config, op *cloudresourcemanager.Operation, activity string) error {
@@ -47,21 +49,7 @@ func resourceManagerOperationWaitTime(config *Config, op *cloudresourcemanager.O
 		Op:      op,
 	}
 
-	state := w.Conf()
-	state.Delay = 10 * time.Second
-	state.Timeout = time.Duration(timeoutMin) * time.Minute
-	state.MinTimeout = 2 * time.Second
-	opRaw, err := state.WaitForState()
-	if err != nil {
-		return fmt.Errorf("Error waiting for %s: %s", activity, err)
-	}
-
-	op = opRaw.(*cloudresourcemanager.Operation)
-	if op.Error != nil {
-		return fmt.Errorf("Error code %v, message: %s", op.Error.Code, op.Error.Message)
-	}
-
-	return nil
+	return operationWaitTime(config, w, activity, timeoutMin)
 }
 
 func resourceManagerV2Beta1OperationWait(config *Config, op *resourceManagerV2Beta1.Operation, activity string) error {
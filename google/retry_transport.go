@@ -0,0 +1,235 @@
+package google
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	defaultRequestMaxAttempts = 5
+	defaultRequestMinBackoff  = time.Second
+	defaultRequestMaxBackoff  = 30 * time.Second
+)
+
+// Reasons attached to 403 responses that indicate the request may succeed
+// if it is tried again later.
+var retryableForbiddenReasons = []string{
+	"rateLimitExceeded",
+	"userRateLimitExceeded",
+}
+
+// Methods that can be sent again without repeating a side effect.
+var idempotentMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"OPTIONS": true,
+	"PUT":     true,
+	"DELETE":  true,
+}
+
+// retryTransport is an http.RoundTripper that retries requests failing with
+// a transient error, backing off exponentially with full jitter between
+// attempts. Requests that may have reached the server are only retried if
+// sending them again is safe; see isReplayableRequest.
+type retryTransport struct {
+	internal http.RoundTripper
+
+	maxAttempts int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+}
+
+func newRetryTransport(internal http.RoundTripper, maxAttempts int, minBackoff, maxBackoff time.Duration) *retryTransport {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &retryTransport{
+		internal:    internal,
+		maxAttempts: maxAttempts,
+		minBackoff:  minBackoff,
+		maxBackoff:  maxBackoff,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A body can only be sent again if it can be recreated, and media
+	// uploads are streamed and resumed by the client library itself.
+	if req.Body != nil && req.Body != http.NoBody && (req.GetBody == nil || isMediaUpload(req)) {
+		return t.internal.RoundTrip(req)
+	}
+
+	replayable := isReplayableRequest(req)
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = new(http.Request)
+			*r = *req
+			r.Body = body
+		}
+
+		res, err := t.internal.RoundTrip(r)
+		if attempt >= t.maxAttempts || !isRetryableResponse(res, err, replayable) {
+			return res, err
+		}
+
+		wait := t.backoff(attempt, res)
+		if err != nil {
+			log.Printf("[DEBUG] Retrying %s %s after transient error (attempt %d/%d, waiting %s): %s", req.Method, req.URL, attempt, t.maxAttempts, wait, err)
+		} else {
+			log.Printf("[DEBUG] Retrying %s %s after %s (attempt %d/%d, waiting %s)", req.Method, req.URL, res.Status, attempt, t.maxAttempts, wait)
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// backoff returns how long to wait before the next attempt. A Retry-After
+// header sent by the server takes precedence over the computed delay, but
+// is capped at the maximum backoff all the same.
+func (t *retryTransport) backoff(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			if wait := time.Duration(seconds) * time.Second; wait < t.maxBackoff {
+				return wait
+			}
+			return t.maxBackoff
+		}
+	}
+
+	ceiling := t.maxBackoff
+	if attempt < 32 {
+		if d := t.minBackoff << uint(attempt-1); d > 0 && d < ceiling {
+			ceiling = d
+		}
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling))) + 1
+}
+
+// isReplayableRequest reports whether req can be sent again after a failure
+// that may have happened after the server acted on it. That is the case for
+// idempotent methods, and for calls that carry a requestId the server uses
+// to ignore duplicates.
+func isReplayableRequest(req *http.Request) bool {
+	return idempotentMethods[req.Method] || req.URL.Query().Get("requestId") != ""
+}
+
+// isMediaUpload reports whether req uploads object or file contents, which
+// may be too large to hold on to between attempts.
+func isMediaUpload(req *http.Request) bool {
+	return req.URL.Query().Get("uploadType") != ""
+}
+
+// isRetryableResponse reports whether a request that ended with res and err
+// failed transiently. Rate limiting errors mean the request was rejected
+// outright, so they're retryable regardless of replayable; other failures
+// are only retryable if the request is. If res has to be inspected, its
+// body is restored so that callers can still read it.
+func isRetryableResponse(res *http.Response, err error, replayable bool) bool {
+	if err != nil {
+		return replayable && isRetryableTransportError(err)
+	}
+
+	switch {
+	case res.StatusCode == 429:
+		return true
+	case res.StatusCode >= 500 && res.StatusCode != 501:
+		return replayable
+	case res.StatusCode == 403:
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		res.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return false
+		}
+		return isRetryableForbiddenBody(body)
+	}
+	return false
+}
+
+// isRetryableError is the equivalent of isRetryableResponse for errors
+// already returned by an API client, so that retries wrapped around whole
+// calls agree with the transport on what counts as transient.
+func isRetryableError(err error) bool {
+	gerr, ok := err.(*googleapi.Error)
+	if !ok {
+		return isRetryableTransportError(err)
+	}
+
+	switch {
+	case gerr.Code == 429:
+		return true
+	case gerr.Code >= 500 && gerr.Code != 501:
+		return true
+	case gerr.Code == 403:
+		for _, item := range gerr.Errors {
+			for _, reason := range retryableForbiddenReasons {
+				if item.Reason == reason {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func isRetryableTransportError(err error) bool {
+	if uerr, ok := err.(*url.Error); ok {
+		err = uerr.Err
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return true
+	}
+	if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "connection reset by peer") ||
+		strings.Contains(msg, "broken pipe") ||
+		strings.Contains(msg, "use of closed network connection")
+}
+
+func isRetryableForbiddenBody(body []byte) bool {
+	var e struct {
+		Error struct {
+			Errors []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &e); err != nil {
+		return false
+	}
+	for _, item := range e.Error.Errors {
+		for _, reason := range retryableForbiddenReasons {
+			if item.Reason == reason {
+				return true
+			}
+		}
+	}
+	return false
+}
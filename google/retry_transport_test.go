@@ -0,0 +1,262 @@
+package google
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestRetryTransport(t *testing.T) {
+	cases := map[string]struct {
+		Method           string
+		Query            string
+		Responses        []int
+		Body             string
+		MaxAttempts      int
+		ExpectedStatus   int
+		ExpectedAttempts int
+	}{
+		"success is not retried": {
+			Responses:        []int{200},
+			MaxAttempts:      5,
+			ExpectedStatus:   200,
+			ExpectedAttempts: 1,
+		},
+		"server errors are retried": {
+			Method:           "PUT",
+			Responses:        []int{503, 500, 200},
+			MaxAttempts:      5,
+			ExpectedStatus:   200,
+			ExpectedAttempts: 3,
+		},
+		"server errors are not retried for non-idempotent requests": {
+			Responses:        []int{503, 200},
+			MaxAttempts:      5,
+			ExpectedStatus:   503,
+			ExpectedAttempts: 1,
+		},
+		"server errors are retried for requests with a request id": {
+			Query:            "?requestId=0b3d1d7e-5c4c-4b7a-9c55-8b9c1f0a6d2e",
+			Responses:        []int{503, 200},
+			MaxAttempts:      5,
+			ExpectedStatus:   200,
+			ExpectedAttempts: 2,
+		},
+		"too many requests is retried": {
+			Responses:        []int{429, 200},
+			MaxAttempts:      5,
+			ExpectedStatus:   200,
+			ExpectedAttempts: 2,
+		},
+		"rate limited forbidden is retried": {
+			Responses:        []int{403, 200},
+			Body:             `{"error": {"code": 403, "errors": [{"reason": "rateLimitExceeded"}]}}`,
+			MaxAttempts:      5,
+			ExpectedStatus:   200,
+			ExpectedAttempts: 2,
+		},
+		"permission denied is not retried": {
+			Responses:        []int{403, 200},
+			Body:             `{"error": {"code": 403, "errors": [{"reason": "forbidden"}]}}`,
+			MaxAttempts:      5,
+			ExpectedStatus:   403,
+			ExpectedAttempts: 1,
+		},
+		"not found is not retried": {
+			Responses:        []int{404, 200},
+			MaxAttempts:      5,
+			ExpectedStatus:   404,
+			ExpectedAttempts: 1,
+		},
+		"gives up after max attempts": {
+			Method:           "PUT",
+			Responses:        []int{503, 503, 503, 200},
+			MaxAttempts:      3,
+			ExpectedStatus:   503,
+			ExpectedAttempts: 3,
+		},
+	}
+
+	for tn, tc := range cases {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			if string(body) != `{"name": "foo"}` {
+				t.Errorf("bad: %s; request body was not replayed on attempt %d, got %q", tn, attempts+1, body)
+			}
+			w.WriteHeader(tc.Responses[attempts])
+			w.Write([]byte(tc.Body))
+			attempts++
+		}))
+
+		if tc.Method == "" {
+			tc.Method = "POST"
+		}
+		client := &http.Client{
+			Transport: newRetryTransport(http.DefaultTransport, tc.MaxAttempts, time.Millisecond, 5*time.Millisecond),
+		}
+		req, _ := http.NewRequest(tc.Method, server.URL+tc.Query, strings.NewReader(`{"name": "foo"}`))
+		res, err := client.Do(req)
+		server.Close()
+		if err != nil {
+			t.Errorf("bad: %s; unexpected error %s", tn, err)
+			continue
+		}
+		res.Body.Close()
+
+		if res.StatusCode != tc.ExpectedStatus {
+			t.Errorf("bad: %s; expected status %d, got %d", tn, tc.ExpectedStatus, res.StatusCode)
+		}
+		if attempts != tc.ExpectedAttempts {
+			t.Errorf("bad: %s; expected %d attempts, got %d", tn, tc.ExpectedAttempts, attempts)
+		}
+	}
+}
+
+func TestRetryTransport_bodyNotReplayable(t *testing.T) {
+	cases := map[string]struct {
+		Query string
+		Body  io.Reader
+	}{
+		"body can't be recreated": {
+			Body: ioutil.NopCloser(strings.NewReader(`{"name": "foo"}`)),
+		},
+		"media upload": {
+			Query: "?uploadType=multipart",
+			Body:  strings.NewReader(`{"name": "foo"}`),
+		},
+	}
+
+	for tn, tc := range cases {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(503)
+		}))
+
+		client := &http.Client{
+			Transport: newRetryTransport(http.DefaultTransport, 5, time.Millisecond, 5*time.Millisecond),
+		}
+		req, _ := http.NewRequest("PUT", server.URL+tc.Query, tc.Body)
+		res, err := client.Do(req)
+		server.Close()
+		if err != nil {
+			t.Errorf("bad: %s; unexpected error %s", tn, err)
+			continue
+		}
+		res.Body.Close()
+
+		if attempts != 1 {
+			t.Errorf("bad: %s; expected 1 attempt, got %d", tn, attempts)
+		}
+	}
+}
+
+func TestRetryTransport_forbiddenBodyIsReadable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(403)
+		w.Write([]byte(`{"error": {"code": 403, "message": "denied"}}`))
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: newRetryTransport(http.DefaultTransport, 5, time.Millisecond, time.Millisecond),
+	}
+	res, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("error: %s", err)
+	}
+	defer res.Body.Close()
+
+	err = googleapi.CheckResponse(res)
+	if gerr, ok := err.(*googleapi.Error); !ok || gerr.Message != "denied" {
+		t.Fatalf("expected the original error to be readable, got %v", err)
+	}
+}
+
+func TestRetryTransport_cancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(503)
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: newRetryTransport(http.DefaultTransport, 5, time.Hour, time.Hour),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	if _, err := client.Do(req.WithContext(ctx)); err == nil {
+		t.Fatalf("expected error, but got nil")
+	}
+}
+
+func TestRetryTransportBackoff(t *testing.T) {
+	transport := newRetryTransport(http.DefaultTransport, 10, time.Second, 8*time.Second)
+
+	for attempt := 1; attempt < 10; attempt++ {
+		ceiling := time.Second << uint(attempt-1)
+		if ceiling > 8*time.Second {
+			ceiling = 8 * time.Second
+		}
+		if wait := transport.backoff(attempt, nil); wait <= 0 || wait > ceiling {
+			t.Errorf("attempt %d: expected backoff in (0, %s], got %s", attempt, ceiling, wait)
+		}
+	}
+
+	res := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+	if wait := transport.backoff(1, res); wait != 7*time.Second {
+		t.Errorf("expected Retry-After to be honoured, got %s", wait)
+	}
+
+	res = &http.Response{Header: http.Header{"Retry-After": []string{"3600"}}}
+	if wait := transport.backoff(1, res); wait != 8*time.Second {
+		t.Errorf("expected Retry-After to be capped at the max backoff, got %s", wait)
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	cases := map[string]struct {
+		Err      error
+		Expected bool
+	}{
+		"too many requests": {
+			Err:      &googleapi.Error{Code: 429},
+			Expected: true,
+		},
+		"bad gateway": {
+			Err:      &googleapi.Error{Code: 502},
+			Expected: true,
+		},
+		"not implemented": {
+			Err:      &googleapi.Error{Code: 501},
+			Expected: false,
+		},
+		"user rate limit": {
+			Err:      &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}}},
+			Expected: true,
+		},
+		"permission denied": {
+			Err:      &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "forbidden"}}},
+			Expected: false,
+		},
+		"conflict": {
+			Err:      &googleapi.Error{Code: 409},
+			Expected: false,
+		},
+	}
+
+	for tn, tc := range cases {
+		if v := isRetryableError(tc.Err); v != tc.Expected {
+			t.Errorf("bad: %s; expected %t, got %t", tn, tc.Expected, v)
+		}
+	}
+}
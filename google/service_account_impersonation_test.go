@@ -0,0 +1,105 @@
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestImpersonatedTokenSource(t *testing.T) {
+	expiry := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if expected := "/projects/-/serviceAccounts/target@my-project.iam.gserviceaccount.com:generateAccessToken"; r.URL.Path != expected {
+			t.Errorf("expected path %q, got %q", expected, r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer base-token" {
+			t.Errorf("expected request authorized with the base token, got %q", auth)
+		}
+
+		var req generateAccessTokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("error decoding request: %s", err)
+		}
+		expectedDelegates := []string{
+			"projects/-/serviceAccounts/delegate@my-project.iam.gserviceaccount.com",
+			"projects/-/serviceAccounts/other@my-project.iam.gserviceaccount.com",
+		}
+		if !reflect.DeepEqual(req.Delegates, expectedDelegates) {
+			t.Errorf("expected delegates %v, got %v", expectedDelegates, req.Delegates)
+		}
+		if !reflect.DeepEqual(req.Scope, []string{"https://www.googleapis.com/auth/cloud-platform"}) {
+			t.Errorf("unexpected scopes %v", req.Scope)
+		}
+
+		json.NewEncoder(w).Encode(&generateAccessTokenResponse{
+			AccessToken: "impersonated-token",
+			ExpireTime:  expiry.Format(time.RFC3339),
+		})
+	}))
+	defer server.Close()
+
+	ts := &impersonatedTokenSource{
+		client:               oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "base-token"})),
+		basePath:             server.URL + "/",
+		targetServiceAccount: "target@my-project.iam.gserviceaccount.com",
+		delegates: []string{
+			"delegate@my-project.iam.gserviceaccount.com",
+			"projects/-/serviceAccounts/other@my-project.iam.gserviceaccount.com",
+		},
+		scopes: []string{"https://www.googleapis.com/auth/cloud-platform"},
+	}
+
+	token, err := ts.Token()
+	if err != nil {
+		t.Fatalf("error: %s", err)
+	}
+	if token.AccessToken != "impersonated-token" {
+		t.Errorf("expected impersonated-token, got %q", token.AccessToken)
+	}
+	if !token.Expiry.Equal(expiry) {
+		t.Errorf("expected expiry %s, got %s", expiry, token.Expiry)
+	}
+}
+
+func TestImpersonatedTokenSource_permissionDenied(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error": {"code": 403, "message": "The caller does not have permission"}}`))
+	}))
+	defer server.Close()
+
+	ts := &impersonatedTokenSource{
+		client:               http.DefaultClient,
+		basePath:             server.URL + "/",
+		targetServiceAccount: "target@my-project.iam.gserviceaccount.com",
+	}
+
+	if _, err := ts.Token(); err == nil {
+		t.Fatalf("expected error, but got nil")
+	}
+}
+
+func TestConfigLoadAndValidate_impersonateServiceAccount(t *testing.T) {
+	config := Config{
+		Credentials:                        testFakeCredentialsPath,
+		Project:                            "my-gce-project",
+		Region:                             "us-central1",
+		ImpersonateServiceAccount:          "target@my-gce-project.iam.gserviceaccount.com",
+		ImpersonateServiceAccountDelegates: []string{"delegate@my-gce-project.iam.gserviceaccount.com"},
+	}
+
+	err := config.loadAndValidate()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+}
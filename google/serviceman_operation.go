@@ -1,11 +1,6 @@
 package google
 
 import (
-	"fmt"
-	"log"
-	"time"
-
-	"github.com/hashicorp/terraform/helper/resource"
 	"google.golang.org/api/googleapi"
 	"google.golang.org/api/servicemanagement/v1"
 )
@@ -15,29 +10,33 @@ type ServiceManagementOperationWaiter struct {
 	Op      *servicemanagement.Operation
 }
 
-func (w *ServiceManagementOperationWaiter) RefreshFunc() resource.StateRefreshFunc {
-	return func() (interface{}, string, error) {
-		var op *servicemanagement.Operation
-		var err error
-
-		op, err = w.Service.Operations.Get(w.Op.Name).Do()
+func (w *ServiceManagementOperationWaiter) QueryOp() error {
+	op, err := w.Service.Operations.Get(w.Op.Name).Do()
+	if err != nil {
+		return err
+	}
 
-		if err != nil {
-			return nil, "", err
-		}
+	w.Op = op
+	return nil
+}
 
-		log.Printf("[DEBUG] Got %v while polling for operation %s's 'done' status", op.Done, w.Op.Name)
+func (w *ServiceManagementOperationWaiter) IsDone() bool {
+	return w.Op.Done
+}
 
-		return op, fmt.Sprint(op.Done), nil
+func (w *ServiceManagementOperationWaiter) OpError() error {
+	if w.Op.Error != nil {
+		return OperationStatusError{Code: w.Op.Error.Code, Message: w.Op.Error.Message}
 	}
+	return nil
 }
 
-func (w *ServiceManagementOperationWaiter) Conf() *resource.StateChangeConf {
-	return &resource.StateChangeConf{
-		Pending: []string{"false"},
-		Target:  []string{"true"},
-		Refresh: w.RefreshFunc(),
-	}
+func (w *ServiceManagementOperationWaiter) State() string {
+	return doneState(w.Op.Done)
+}
+
+func (w *ServiceManagementOperationWaiter) OpName() string {
+	return w.Op.Name
 }
 
 func serviceManagementOperationWait(config *Config, op *servicemanagement.Operation, activity string) (googleapi.RawMessage, error) {
@@ -50,19 +49,9 @@ func serviceManagementOperationWaitTime(config *Config, op *servicemanagement.Op
 		Op:      op,
 	}
 
-	state := w.Conf()
-	state.Delay = 10 * time.Second
-	state.Timeout = time.Duration(timeoutMin) * time.Minute
-	state.MinTimeout = 2 * time.Second
-	opRaw, err := state.WaitForState()
-	if err != nil {
-		return nil, fmt.Errorf("Error waiting for %s: %s", activity, err)
-	}
-
-	op = opRaw.(*servicemanagement.Operation)
-	if op.Error != nil {
-		return nil, fmt.Errorf("Error code %v, message: %s", op.Error.Code, op.Error.Message)
+	if err := operationWaitTime(config, w, activity, timeoutMin); err != nil {
+		return nil, err
 	}
 
-	return op.Response, nil
+	return w.Op.Response, nil
 }
@@ -1,11 +1,6 @@
 package google
 
 import (
-	"fmt"
-	"log"
-	"time"
-
-	"github.com/hashicorp/terraform/helper/resource"
 	"google.golang.org/api/spanner/v1"
 )
 
@@ -14,27 +9,33 @@ type SpannerInstanceOperationWaiter struct {
 	Op      *spanner.Operation
 }
 
-func (w *SpannerInstanceOperationWaiter) Conf() *resource.StateChangeConf {
-	return &resource.StateChangeConf{
-		Pending: []string{"false"},
-		Target:  []string{"true"},
-		Refresh: w.RefreshFunc(),
+func (w *SpannerInstanceOperationWaiter) QueryOp() error {
+	op, err := w.Service.Projects.Instances.Operations.Get(w.Op.Name).Do()
+	if err != nil {
+		return err
 	}
-}
 
-func (w *SpannerInstanceOperationWaiter) RefreshFunc() resource.StateRefreshFunc {
-	return func() (interface{}, string, error) {
+	w.Op = op
+	return nil
+}
 
-		op, err := w.Service.Projects.Instances.Operations.Get(w.Op.Name).Do()
+func (w *SpannerInstanceOperationWaiter) IsDone() bool {
+	return w.Op.Done
+}
 
-		if err != nil {
-			return nil, "", err
-		}
+func (w *SpannerInstanceOperationWaiter) OpError() error {
+	if w.Op.Error != nil {
+		return OperationStatusError{Code: w.Op.Error.Code, Message: w.Op.Error.Message}
+	}
+	return nil
+}
 
-		log.Printf("[DEBUG] Got %v while polling for operation %s's 'done' status", op.Done, w.Op.Name)
+func (w *SpannerInstanceOperationWaiter) State() string {
+	return doneState(w.Op.Done)
+}
 
-		return op, fmt.Sprint(op.Done), nil
-	}
+func (w *SpannerInstanceOperationWaiter) OpName() string {
+	return w.Op.Name
 }
 
 func spannerInstanceOperationWait(config *Config, op *spanner.Operation, activity string, timeoutMin int) error {
@@ -43,20 +44,5 @@ func spannerInstanceOperationWait(config *Config, op *spanner.Operation, activit
 		Op:      op,
 	}
 
-	state := w.Conf()
-	state.Delay = 10 * time.Second
-	state.Timeout = time.Duration(timeoutMin) * time.Minute
-	state.MinTimeout = 2 * time.Second
-	opRaw, err := state.WaitForState()
-	if err != nil {
-		return fmt.Errorf("Error waiting for %s: %s", activity, err)
-	}
-
-	op = opRaw.(*spanner.Operation)
-	if op.Error != nil {
-		return fmt.Errorf("Error code %v, message: %s", op.Error.Code, op.Error.Message)
-	}
-
-	return nil
-
+	return operationWaitTime(config, w, activity, timeoutMin)
 }
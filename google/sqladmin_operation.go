@@ -2,12 +2,7 @@ package google
 
 import (
 	"bytes"
-	"fmt"
-	"log"
-	"time"
 
-	"github.com/hashicorp/terraform/helper/resource"
-	"google.golang.org/api/googleapi"
 	"google.golang.org/api/sqladmin/v1beta4"
 )
 
@@ -17,29 +12,33 @@ type SqlAdminOperationWaiter struct {
 	Project string
 }
 
-func (w *SqlAdminOperationWaiter) RefreshFunc() resource.StateRefreshFunc {
-	return func() (interface{}, string, error) {
-		log.Printf("[DEBUG] self_link: %s", w.Op.SelfLink)
-		op, err := w.Service.Operations.Get(w.Project, w.Op.Name).Do()
+func (w *SqlAdminOperationWaiter) QueryOp() error {
+	op, err := w.Service.Operations.Get(w.Project, w.Op.Name).Do()
+	if err != nil {
+		return err
+	}
 
-		if e, ok := err.(*googleapi.Error); ok && (e.Code == 429 || e.Code == 503) {
-			return w.Op, "PENDING", nil
-		} else if err != nil {
-			return nil, "", err
-		}
+	w.Op = op
+	return nil
+}
 
-		log.Printf("[DEBUG] Got %q when asking for operation %q", op.Status, w.Op.Name)
+func (w *SqlAdminOperationWaiter) IsDone() bool {
+	return w.Op.Status == "DONE"
+}
 
-		return op, op.Status, nil
+func (w *SqlAdminOperationWaiter) OpError() error {
+	if w.Op.Error != nil {
+		return SqlAdminOperationError(*w.Op.Error)
 	}
+	return nil
 }
 
-func (w *SqlAdminOperationWaiter) Conf() *resource.StateChangeConf {
-	return &resource.StateChangeConf{
-		Pending: []string{"PENDING", "RUNNING"},
-		Target:  []string{"DONE"},
-		Refresh: w.RefreshFunc(),
-	}
+func (w *SqlAdminOperationWaiter) State() string {
+	return w.Op.Status
+}
+
+func (w *SqlAdminOperationWaiter) OpName() string {
+	return w.Op.Name
 }
 
 // SqlAdminOperationError wraps sqladmin.OperationError and implements the
@@ -67,19 +66,5 @@ func sqladminOperationWaitTime(config *Config, op *sqladmin.Operation, project,
 		Project: project,
 	}
 
-	state := w.Conf()
-	state.Timeout = time.Duration(timeoutMinutes) * time.Minute
-	state.MinTimeout = 2 * time.Second
-	state.Delay = 5 * time.Second
-	opRaw, err := state.WaitForState()
-	if err != nil {
-		return fmt.Errorf("Error waiting for %s (op %s): %s", activity, op.Name, err)
-	}
-
-	op = opRaw.(*sqladmin.Operation)
-	if op.Error != nil {
-		return SqlAdminOperationError(*op.Error)
-	}
-
-	return nil
+	return operationWaitTime(config, w, activity, timeoutMinutes)
 }
@@ -3,9 +3,11 @@ package google
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 
 	"google.golang.org/api/googleapi"
@@ -47,14 +49,34 @@ func (b *serializableBody) MarshalJSON() ([]byte, error) {
 	// false, 0, a nil pointer, a nil interface value, and any empty array,
 	// slice, map, or string.
 
-	// TODO: Add support for ForceSendFields and NullFields.
+	// Only top-level keys of the body can be listed in ForceSendFields and
+	// NullFields.
+	out := make(map[string]interface{}, len(b.body))
 	for k, v := range b.body {
-		if isEmptyValue(reflect.ValueOf(v)) {
-			delete(b.body, k)
+		rv := reflect.ValueOf(v)
+		if !rv.IsValid() {
+			// An untyped nil is never sent unless it is in NullFields.
+			continue
 		}
+		if !isEmptyValue(rv) {
+			out[k] = v
+			continue
+		}
+		if rv.Kind() != reflect.Ptr && rv.Kind() != reflect.Interface && containsString(b.ForceSendFields, k) {
+			out[k] = v
+		}
+	}
+
+	for _, k := range b.NullFields {
+		if v, ok := b.body[k]; ok {
+			if rv := reflect.ValueOf(v); rv.IsValid() && !isEmptyValue(rv) {
+				return nil, fmt.Errorf("field %q is in NullFields but has a non-empty value", k)
+			}
+		}
+		out[k] = nil
 	}
 
-	return json.Marshal(b.body)
+	return json.Marshal(out)
 }
 
 func isEmptyValue(v reflect.Value) bool {
@@ -91,21 +113,45 @@ func Delete(config *Config, url string) (map[string]interface{}, error) {
 	return sendRequest(config, "DELETE", url, nil)
 }
 
+// Patch sends body as a PATCH request. Empty values are only sent for the
+// fields named in forceSendFields, and the fields in nullFields are sent as
+// null to clear them; patchFieldLists can work both lists out from a
+// resource's changes.
+func Patch(config *Config, url string, body map[string]interface{}, forceSendFields, nullFields []string) (map[string]interface{}, error) {
+	return sendRequestWithFields(config, "PATCH", url, body, forceSendFields, nullFields)
+}
+
 func sendRequest(config *Config, method, url string, body map[string]interface{}) (map[string]interface{}, error) {
+	return sendRequestWithFields(config, method, url, body, nil, nil)
+}
+
+func sendRequestWithFields(config *Config, method, url string, body map[string]interface{}, forceSendFields, nullFields []string) (map[string]interface{}, error) {
 	reqHeaders := make(http.Header)
 	reqHeaders.Set("User-Agent", config.userAgent)
 	reqHeaders.Set("Content-Type", "application/json")
+	if userProject := config.userProject(); userProject != "" {
+		reqHeaders.Set(userProjectHeader, userProject)
+	}
 
 	var buf bytes.Buffer
 	if body != nil {
 		err := json.NewEncoder(&buf).Encode(&serializableBody{
-			body: body})
+			body:            body,
+			ForceSendFields: forceSendFields,
+			NullFields:      nullFields,
+		})
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	req, err := http.NewRequest(method, url+"?alt=json", &buf)
+	url = config.rewriteEndpoint(url)
+	if strings.Contains(url, "?") {
+		url += "&alt=json"
+	} else {
+		url += "?alt=json"
+	}
+	req, err := http.NewRequest(method, url, &buf)
 	if err != nil {
 		return nil, err
 	}
@@ -127,6 +173,31 @@ func sendRequest(config *Config, method, url string, body map[string]interface{}
 	return result, nil
 }
 
+// patchFieldLists works out which fields of a request body built from d need
+// to be listed in ForceSendFields or NullFields for a change to reach the
+// API. fields maps schema keys to the body key they are expanded into. A
+// changed field whose expanded value is empty, such as false or 0, has to be
+// force-sent, and one that expanded to nothing at all has to be nulled out.
+func patchFieldLists(d TerraformResourceData, body map[string]interface{}, fields map[string]string) (forceSendFields, nullFields []string) {
+	for schemaKey, bodyKey := range fields {
+		if !d.HasChange(schemaKey) {
+			continue
+		}
+
+		v := reflect.ValueOf(body[bodyKey])
+		switch {
+		case !v.IsValid(), (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface || v.Kind() == reflect.Map || v.Kind() == reflect.Slice) && v.IsNil():
+			nullFields = append(nullFields, bodyKey)
+		case isEmptyValue(v):
+			forceSendFields = append(forceSendFields, bodyKey)
+		}
+	}
+
+	sort.Strings(forceSendFields)
+	sort.Strings(nullFields)
+	return
+}
+
 func replaceVars(d TerraformResourceData, config *Config, linkTmpl string) (string, error) {
 	re := regexp.MustCompile("{{([[:word:]]+)}}")
 	var project, region, zone string
@@ -173,3 +244,37 @@ func replaceVars(d TerraformResourceData, config *Config, linkTmpl string) (stri
 
 	return re.ReplaceAllStringFunc(linkTmpl, replaceFunc), nil
 }
+
+// userProjectHeader names the project a request is billed to and counted
+// against the quota of, when it isn't the credentials' own project.
+const userProjectHeader = "X-Goog-User-Project"
+
+// userProjectTransport is an http.RoundTripper that sets userProjectHeader on
+// requests that don't already carry it.
+type userProjectTransport struct {
+	internal http.RoundTripper
+	project  string
+}
+
+func newUserProjectTransport(internal http.RoundTripper, project string) *userProjectTransport {
+	return &userProjectTransport{
+		internal: internal,
+		project:  project,
+	}
+}
+
+func (t *userProjectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get(userProjectHeader) == "" {
+		// RoundTrippers must not modify the request they are given.
+		r := new(http.Request)
+		*r = *req
+		r.Header = make(http.Header, len(req.Header)+1)
+		for k, v := range req.Header {
+			r.Header[k] = v
+		}
+		r.Header.Set(userProjectHeader, t.project)
+		req = r
+	}
+
+	return t.internal.RoundTrip(req)
+}
@@ -1,6 +1,10 @@
 package google
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"testing"
 )
 
@@ -94,3 +98,200 @@ func TestReplaceVars(t *testing.T) {
 		}
 	}
 }
+
+func TestSendRequest_customEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if expected := "/compute/v1/projects/my-project/global/backendBuckets/bucket"; r.URL.Path != expected {
+			t.Errorf("expected request to %q, got %q", expected, r.URL.Path)
+		}
+		w.Write([]byte(`{"name": "bucket"}`))
+	}))
+	defer server.Close()
+
+	config := &Config{
+		client: server.Client(),
+		endpointOverrides: map[string]string{
+			"https://www.googleapis.com/compute/v1/projects/": server.URL + "/compute/v1/projects/",
+		},
+	}
+
+	res, err := Get(config, "https://www.googleapis.com/compute/v1/projects/my-project/global/backendBuckets/bucket")
+	if err != nil {
+		t.Fatalf("error: %s", err)
+	}
+	if res["name"] != "bucket" {
+		t.Fatalf("unexpected response %v", res)
+	}
+}
+
+func TestSendRequest_userProject(t *testing.T) {
+	cases := map[string]struct {
+		Config   *Config
+		Expected string
+	}{
+		"no override": {
+			Config:   &Config{Project: "my-project", BillingProject: "billing-project"},
+			Expected: "",
+		},
+		"billing project": {
+			Config:   &Config{Project: "my-project", BillingProject: "billing-project", UserProjectOverride: true},
+			Expected: "billing-project",
+		},
+		"falls back to the provider project": {
+			Config:   &Config{Project: "my-project", UserProjectOverride: true},
+			Expected: "my-project",
+		},
+	}
+
+	for tn, tc := range cases {
+		var header string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header.Get(userProjectHeader)
+			w.Write([]byte(`{}`))
+		}))
+
+		tc.Config.client = server.Client()
+		_, err := Get(tc.Config, server.URL)
+		server.Close()
+		if err != nil {
+			t.Errorf("bad: %s; unexpected error %s", tn, err)
+			continue
+		}
+		if header != tc.Expected {
+			t.Errorf("bad: %s; expected %s %q, got %q", tn, userProjectHeader, tc.Expected, header)
+		}
+	}
+}
+
+func TestUserProjectTransport(t *testing.T) {
+	var headers []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Get(userProjectHeader))
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: newUserProjectTransport(http.DefaultTransport, "billing-project"),
+	}
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("error: %s", err)
+	}
+	if req.Header.Get(userProjectHeader) != "" {
+		t.Errorf("expected the original request not to be modified")
+	}
+
+	req, _ = http.NewRequest("GET", server.URL, nil)
+	req.Header.Set(userProjectHeader, "other-project")
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("error: %s", err)
+	}
+
+	if expected := []string{"billing-project", "other-project"}; !reflect.DeepEqual(headers, expected) {
+		t.Errorf("expected headers %v, got %v", expected, headers)
+	}
+}
+
+func TestSerializableBodyMarshalJSON(t *testing.T) {
+	var nilPointer *string
+	cases := map[string]struct {
+		Body            map[string]interface{}
+		ForceSendFields []string
+		NullFields      []string
+		Expected        string
+		ExpectedError   bool
+	}{
+		"empty values are omitted": {
+			Body: map[string]interface{}{
+				"name":      "foo",
+				"enableCdn": false,
+				"port":      0,
+				"tags":      []string{},
+				"unset":     nil,
+			},
+			Expected: `{"name":"foo"}`,
+		},
+		"force-sent empty values are kept": {
+			Body: map[string]interface{}{
+				"name":      "foo",
+				"enableCdn": false,
+				"port":      0,
+				"tags":      []string{},
+			},
+			ForceSendFields: []string{"enableCdn", "port", "tags"},
+			Expected:        `{"enableCdn":false,"name":"foo","port":0,"tags":[]}`,
+		},
+		"force-sent nil values are still omitted": {
+			Body: map[string]interface{}{
+				"unset":   nil,
+				"pointer": nilPointer,
+			},
+			ForceSendFields: []string{"unset", "pointer"},
+			Expected:        `{}`,
+		},
+		"null fields are sent as null": {
+			Body: map[string]interface{}{
+				"name":        "foo",
+				"description": "",
+			},
+			NullFields: []string{"description", "cdnPolicy"},
+			Expected:   `{"cdnPolicy":null,"description":null,"name":"foo"}`,
+		},
+		"null fields with a value are an error": {
+			Body: map[string]interface{}{
+				"description": "bar",
+			},
+			NullFields:    []string{"description"},
+			ExpectedError: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		b, err := json.Marshal(&serializableBody{
+			body:            tc.Body,
+			ForceSendFields: tc.ForceSendFields,
+			NullFields:      tc.NullFields,
+		})
+		if err != nil {
+			if !tc.ExpectedError {
+				t.Errorf("bad: %s; unexpected error %s", tn, err)
+			}
+			continue
+		}
+		if tc.ExpectedError {
+			t.Errorf("bad: %s; expected error", tn)
+		}
+		if string(b) != tc.Expected {
+			t.Errorf("bad: %s; expected %s, got %s", tn, tc.Expected, b)
+		}
+	}
+}
+
+func TestPatchFieldLists(t *testing.T) {
+	d := &ResourceDataMock{
+		FieldsWithHasChange: []string{"enable_cdn", "description", "cdn_policy", "port"},
+	}
+	body := map[string]interface{}{
+		"enableCdn":   false,
+		"description": "",
+		"cdnPolicy":   nil,
+		"port":        8080,
+		"timeoutSec":  0,
+	}
+
+	forceSendFields, nullFields := patchFieldLists(d, body, map[string]string{
+		"enable_cdn":  "enableCdn",
+		"description": "description",
+		"cdn_policy":  "cdnPolicy",
+		"port":        "port",
+		"timeout_sec": "timeoutSec",
+	})
+
+	if expected := []string{"description", "enableCdn"}; !reflect.DeepEqual(forceSendFields, expected) {
+		t.Errorf("expected ForceSendFields %v, got %v", expected, forceSendFields)
+	}
+	if expected := []string{"cdnPolicy"}; !reflect.DeepEqual(nullFields, expected) {
+		t.Errorf("expected NullFields %v, got %v", expected, nullFields)
+	}
+}
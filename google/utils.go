@@ -284,6 +284,15 @@ func convertStringSet(set *schema.Set) []string {
 	return s
 }
 
+func containsString(strs []string, s string) bool {
+	for _, v := range strs {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
 func mergeSchemas(a, b map[string]*schema.Schema) map[string]*schema.Schema {
 	merged := make(map[string]*schema.Schema)
 
@@ -308,8 +317,8 @@ func retryTime(retryFunc func() error, minutes int) error {
 		if err == nil {
 			return nil
 		}
-		if gerr, ok := err.(*googleapi.Error); ok && (gerr.Code == 429 || gerr.Code == 500 || gerr.Code == 502 || gerr.Code == 503) {
-			return resource.RetryableError(gerr)
+		if isRetryableError(err) {
+			return resource.RetryableError(err)
 		}
 		return resource.NonRetryableError(err)
 	})
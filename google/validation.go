@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -131,3 +132,32 @@ func validateCloudIoTID(v interface{}, k string) (warnings []string, errors []er
 	}
 	return
 }
+
+func validateDuration(v interface{}, k string) (warnings []string, errors []error) {
+	value := v.(string)
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q (%q) is not a valid duration: %s", k, value, err))
+		return
+	}
+	if d < 0 {
+		errors = append(errors, fmt.Errorf("%q (%q) must not be negative", k, value))
+	}
+	return
+}
+
+func validatePositiveFloat(v interface{}, k string) (warnings []string, errors []error) {
+	if value := v.(float64); value <= 0 {
+		errors = append(errors, fmt.Errorf("%q must be greater than 0, got %g", k, value))
+	}
+	return
+}
+
+func validateFloatBetween(min, max float64) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) (warnings []string, errors []error) {
+		if value := v.(float64); value < min || value > max {
+			errors = append(errors, fmt.Errorf("%q must be between %g and %g, got %g", k, min, max, value))
+		}
+		return
+	}
+}
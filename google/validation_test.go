@@ -76,6 +76,45 @@ func TestValidateRFC3339Time(t *testing.T) {
 	}
 }
 
+func TestValidateDuration(t *testing.T) {
+	cases := []StringValidationTestCase{
+		// No errors
+		{TestName: "seconds", Value: "1s"},
+		{TestName: "fractional minutes", Value: "1.5m"},
+		{TestName: "zero", Value: "0s"},
+
+		// With errors
+		{TestName: "no unit", Value: "30", ExpectError: true},
+		{TestName: "negative", Value: "-1s", ExpectError: true},
+		{TestName: "not a duration", Value: "soon", ExpectError: true},
+	}
+
+	es := testStringValidationCases(cases, validateDuration)
+	if len(es) > 0 {
+		t.Errorf("Failed to validate durations: %v", es)
+	}
+}
+
+func TestValidateFloatBetween(t *testing.T) {
+	cases := []struct {
+		Value       float64
+		ExpectError bool
+	}{
+		{Value: 0},
+		{Value: 0.5},
+		{Value: 1},
+		{Value: -0.1, ExpectError: true},
+		{Value: 1.1, ExpectError: true},
+	}
+
+	for _, c := range cases {
+		_, errors := validateFloatBetween(0, 1)(c.Value, "flow_sampling")
+		if (len(errors) > 0) != c.ExpectError {
+			t.Errorf("%g: expected error: %t, got %v", c.Value, c.ExpectError, errors)
+		}
+	}
+}
+
 func TestValidateRFC1035Name(t *testing.T) {
 	cases := []struct {
 		TestName    string